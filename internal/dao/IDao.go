@@ -61,4 +61,10 @@ type IDao interface {
 
 	GetSessionValidatorsById(ctx context.Context, sessionId uint) []string
 	CreateNewSession(ctx context.Context, sessionId uint, validators []string) error
+
+	SavePendingTransactions(c context.Context, list []model.PendingTransaction, ttl int) error
+	RemovePendingTransactions(c context.Context, source string, hashes ...string) error
+	PendingTransactionHashes(c context.Context, source string) ([]string, error)
+	GetPendingTransaction(c context.Context, hash string) *model.PendingTransaction
+	GetPendingTransactionList(c context.Context, source string, limit int) []model.PendingTransaction
 }
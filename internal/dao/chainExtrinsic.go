@@ -247,6 +247,7 @@ func (d *Dao) extrinsicsAsDetail(ctx context.Context, e *model.ChainExtrinsic) *
 		Fee:                e.Fee,
 		Finalized:          true,
 		Params:             e.Params,
+		Proxy:              e.ProxyJson(),
 	}
 	d.FindLifeTime(ctx, &detail, e.Era)
 	return &detail
@@ -267,6 +268,7 @@ func (d *Dao) ExtrinsicsAsJson(e *model.ChainExtrinsic) *model.ChainExtrinsicJso
 		Signature:          e.Signature,
 		Nonce:              e.Nonce,
 		Fee:                e.Fee,
+		Proxy:              e.ProxyJson(),
 	}
 	return ej
 }
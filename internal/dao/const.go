@@ -7,6 +7,7 @@ var (
 	RedisFillAlreadyBlockNum   = model.RedisKeyPrefix() + "FillAlreadyBlockNum"
 	RedisFillFinalizedBlockNum = model.RedisKeyPrefix() + "FillFinalizedBlockNum"
 	RedisExtrinsicCountKey     = model.RedisKeyPrefix() + "extrinsic_count"
+	RedisPendingPrefix         = model.RedisKeyPrefix() + "pending:"
 )
 
 // local cache value
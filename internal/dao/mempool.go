@@ -0,0 +1,111 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/itering/subscan/model"
+)
+
+// pendingIndexKey sorted set of pending hashes by first seen time
+func pendingIndexKey(source string) string {
+	return RedisPendingPrefix + source
+}
+
+func pendingTxKey(hash string) string {
+	return RedisPendingPrefix + "tx:" + strings.ToLower(hash)
+}
+
+// SavePendingTransactions refresh ttl of pending transactions, first seen time is kept
+func (d *Dao) SavePendingTransactions(c context.Context, list []model.PendingTransaction, ttl int) (err error) {
+	if len(list) == 0 {
+		return
+	}
+	conn, _ := d.redis.Redis().GetContext(c)
+	defer conn.Close()
+	for _, tx := range list {
+		hash := strings.ToLower(tx.Hash)
+		if first, err := redis.Int64(conn.Do("ZSCORE", pendingIndexKey(tx.Source), hash)); err == nil {
+			tx.FirstSeen = first
+		}
+		value, _ := json.Marshal(tx)
+		_ = conn.Send("SETEX", pendingTxKey(hash), ttl, value)
+		_ = conn.Send("ZADD", pendingIndexKey(tx.Source), "NX", tx.FirstSeen, hash)
+	}
+	_, err = conn.Do("")
+	return
+}
+
+// RemovePendingTransactions remove included or dropped transactions
+func (d *Dao) RemovePendingTransactions(c context.Context, source string, hashes ...string) (err error) {
+	if len(hashes) == 0 {
+		return
+	}
+	conn, _ := d.redis.Redis().GetContext(c)
+	defer conn.Close()
+	for _, hash := range hashes {
+		hash = strings.ToLower(hash)
+		_ = conn.Send("DEL", pendingTxKey(hash))
+		_ = conn.Send("ZREM", pendingIndexKey(source), hash)
+	}
+	_, err = conn.Do("")
+	return
+}
+
+// PendingTransactionHashes all hashes of source in pending index, include expired ones
+func (d *Dao) PendingTransactionHashes(c context.Context, source string) ([]string, error) {
+	conn, _ := d.redis.Redis().GetContext(c)
+	defer conn.Close()
+	return redis.Strings(conn.Do("ZRANGE", pendingIndexKey(source), 0, -1))
+}
+
+func (d *Dao) GetPendingTransaction(c context.Context, hash string) *model.PendingTransaction {
+	conn, _ := d.redis.Redis().GetContext(c)
+	defer conn.Close()
+	value, err := redis.Bytes(conn.Do("GET", pendingTxKey(hash)))
+	if err != nil {
+		return nil
+	}
+	var tx model.PendingTransaction
+	if json.Unmarshal(value, &tx) != nil {
+		return nil
+	}
+	return &tx
+}
+
+// GetPendingTransactionList latest seen pending transactions of source,
+// hashes expired by ttl are removed from index lazily
+func (d *Dao) GetPendingTransactionList(c context.Context, source string, limit int) []model.PendingTransaction {
+	conn, _ := d.redis.Redis().GetContext(c)
+	defer conn.Close()
+	hashes, err := redis.Strings(conn.Do("ZREVRANGE", pendingIndexKey(source), 0, limit-1))
+	if err != nil || len(hashes) == 0 {
+		return nil
+	}
+	args := redis.Args{}
+	for _, hash := range hashes {
+		args = args.Add(pendingTxKey(hash))
+	}
+	values, err := redis.ByteSlices(conn.Do("MGET", args...))
+	if err != nil {
+		return nil
+	}
+	var (
+		list    []model.PendingTransaction
+		expired = redis.Args{}.Add(pendingIndexKey(source))
+	)
+	for index, value := range values {
+		var tx model.PendingTransaction
+		if value == nil || json.Unmarshal(value, &tx) != nil {
+			expired = expired.Add(hashes[index])
+			continue
+		}
+		list = append(list, tx)
+	}
+	if len(expired) > 1 {
+		_, _ = conn.Do("ZREM", expired...)
+	}
+	return list
+}
@@ -3,6 +3,7 @@ package dao
 import (
 	"context"
 	"github.com/itering/subscan/model"
+	"github.com/itering/subscan/share/price"
 	"gorm.io/gorm"
 )
 
@@ -24,7 +25,7 @@ func (d *Dao) Migration() {
 }
 
 func (d *Dao) internalTables(blockNum uint) (models []interface{}) {
-	models = append(models, model.RuntimeVersion{}, model.Session{}, model.AccountExtrinsicMapping{})
+	models = append(models, model.RuntimeVersion{}, model.Session{}, model.AccountExtrinsicMapping{}, price.TokenPrice{})
 	for i := 0; uint(i) <= blockNum/model.SplitTableBlockNum; i++ {
 		models = append(
 			models,
@@ -83,7 +83,7 @@ func emitMsg(message *workers.Msg) {
 
 		case "plugin-extrinsic":
 			type T struct {
-				ExtrinsicIndex string `json:"event_index"`
+				ExtrinsicIndex string `json:"extrinsic_index"`
 				PluginName     string `json:"plugin_name"`
 			}
 			var args T
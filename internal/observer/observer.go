@@ -70,6 +70,12 @@ func RunCron() {
 		util.Logger().Error(fmt.Errorf("failed to register cron job: %v", err))
 		os.Exit(1)
 	}
+	if _, err := c.AddFunc("@every 10m", func() {
+		srv.RefreshTokenPrices(context.Background())
+	}); err != nil {
+		util.Logger().Error(fmt.Errorf("failed to register cron job: %v", err))
+		os.Exit(1)
+	}
 	c.Start()
 	<-stop
 	<-c.Stop().Done()
@@ -63,6 +63,9 @@ func initRouter(e *gin.Engine) {
 			// Extrinsic
 			s.POST("extrinsics", extrinsicsHandle)
 			s.POST("extrinsic", extrinsicHandle)
+			s.POST("extrinsic/submit", extrinsicSubmitHandle)
+			s.POST("extrinsic/dry_run", extrinsicDryRunHandle)
+			s.POST("pending_transactions", pendingTransactionsHandle)
 			// Event
 			s.POST("events", eventsHandle)
 			s.POST("event", eventHandle)
@@ -22,14 +22,37 @@ func metadataHandle(c *gin.Context) {
 	toJson(c, m, err)
 }
 
+type TokenParams struct {
+	Category string `json:"category" binding:"omitempty"`
+	TokenId  string `json:"token_id" binding:"omitempty"`
+	Row      int    `json:"row" binding:"omitempty,min=1,max=100"`
+}
+
 // @Summary Token list
+// @Description native token and all registered assets, with top holders if category and token_id set
 // @Tags tokens
 // @Accept json
 // @Produce json
-// @Success 200 {object} http.J{data=object{token=[]string,detail=map[string]token.Token}}
+// @Param params body TokenParams false "params"
+// @Success 200 {object} http.J{data=object{token=token.Token,list=[]token.Token,holders=[]token.Holder}}
 // @Router /api/scan/token [post]
 func tokenHandle(c *gin.Context) {
-	toJson(c, token.GetDefaultToken(), nil)
+	p := new(TokenParams)
+	if c.Request.ContentLength > 0 {
+		if err := c.MustBindWith(p, binding.JSON); err != nil {
+			toJson(c, nil, err)
+			return
+		}
+	}
+	ctx := c.Request.Context()
+	data := map[string]interface{}{
+		"token": token.GetDefaultToken(),
+		"list":  token.List(ctx),
+	}
+	if p.Category != "" {
+		data["holders"] = token.Holders(ctx, p.Category, p.TokenId, p.Row)
+	}
+	toJson(c, data, nil)
 }
 
 type BlocksParams struct {
@@ -288,7 +311,7 @@ type checkSearchParams struct {
 	Hash string `json:"hash" binding:"len=66"`
 }
 
-// checkSearchHashHandle handler check hash type, block or extrinsic or evm tx hash
+// checkSearchHashHandle handler check hash type, block or extrinsic or evm tx hash, pending if it is in transaction pool
 // @Summary Check hash type
 // @Tags hash
 // @Accept json
@@ -314,9 +337,90 @@ func checkSearchHashHandle(c *gin.Context) {
 		return
 	}
 	// todo evm tx hash
+	if data := svc.GetPendingTransaction(ctx, p.Hash); data != nil {
+		toJson(c, map[string]string{"hash_type": "pending", "source": data.Source}, nil)
+		return
+	}
 	toJson(c, nil, util.RecordNotFound)
 }
 
+type extrinsicSubmitParams struct {
+	Extrinsic string `json:"extrinsic" binding:"required,hexadecimal"`
+}
+
+// extrinsicSubmitHandle relay signed extrinsic to node
+// @Summary Submit signed extrinsic
+// @Tags extrinsic
+// @Accept json
+// @Produce json
+// @Param params body extrinsicSubmitParams true "params"
+// @Success 200 {object} http.J{data=object{hash=string}}
+// @Router /api/scan/extrinsic/submit [post]
+func extrinsicSubmitHandle(c *gin.Context) {
+	p := new(extrinsicSubmitParams)
+	if err := c.MustBindWith(p, binding.JSON); err != nil {
+		toJson(c, nil, err)
+		return
+	}
+	hash, err := svc.SubmitExtrinsic(c.Request.Context(), p.Extrinsic)
+	if err != nil {
+		toJson(c, nil, err)
+		return
+	}
+	toJson(c, map[string]string{"hash": hash}, nil)
+}
+
+type extrinsicDryRunParams struct {
+	Extrinsic string `json:"extrinsic" binding:"required,hexadecimal"`
+	BlockHash string `json:"block_hash" binding:"omitempty,len=66"`
+}
+
+// extrinsicDryRunHandle decoded call, estimated fee, weight and dispatch outcome of signed extrinsic, not submitted
+// @Summary Dry run signed extrinsic
+// @Tags extrinsic
+// @Accept json
+// @Produce json
+// @Param params body extrinsicDryRunParams true "params"
+// @Success 200 {object} http.J{data=service.ExtrinsicDryRunJson}
+// @Router /api/scan/extrinsic/dry_run [post]
+func extrinsicDryRunHandle(c *gin.Context) {
+	p := new(extrinsicDryRunParams)
+	if err := c.MustBindWith(p, binding.JSON); err != nil {
+		toJson(c, nil, err)
+		return
+	}
+	res, err := svc.DryRunExtrinsic(c.Request.Context(), p.Extrinsic, p.BlockHash)
+	if err != nil {
+		toJson(c, nil, err)
+		return
+	}
+	toJson(c, res, nil)
+}
+
+type pendingTransactionsParams struct {
+	Source string `json:"source" binding:"omitempty,oneof=substrate evm"`
+	Row    int    `json:"row" binding:"min=1,max=100"`
+}
+
+// pendingTransactionsHandle latest seen transactions in transaction pool, not included in block yet
+// @Summary Pending transactions list
+// @Tags extrinsic
+// @Accept json
+// @Produce json
+// @Param params body pendingTransactionsParams true "params"
+// @Success 200 {object} http.J{data=object{list=[]model.PendingTransaction}}
+// @Router /api/scan/pending_transactions [post]
+func pendingTransactionsHandle(c *gin.Context) {
+	p := new(pendingTransactionsParams)
+	if err := c.MustBindWith(p, binding.JSON); err != nil {
+		toJson(c, nil, err)
+		return
+	}
+	toJson(c, map[string]interface{}{
+		"list": svc.PendingTransactions(c.Request.Context(), p.Source, p.Row),
+	}, nil)
+}
+
 // @Summary Get runtime list
 // @Description runtimeListHandler  get runtime list
 // @Tags runtime
@@ -61,6 +61,7 @@ func (s *Service) ExtrinsicsAsJson(e *model.ChainExtrinsic) *model.ChainExtrinsi
 		Signature:          e.Signature,
 		Nonce:              e.Nonce,
 		Fee:                e.Fee,
+		Proxy:              e.ProxyJson(),
 	}
 	return ej
 }
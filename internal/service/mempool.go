@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/itering/subscan/model"
+	"github.com/itering/subscan/pkg/go-web3/dto"
+	"github.com/itering/subscan/share/web3"
+	"github.com/itering/subscan/util"
+	"github.com/itering/subscan/util/address"
+	"github.com/itering/substrate-api-rpc"
+	"github.com/itering/substrate-api-rpc/hasher"
+	smodel "github.com/itering/substrate-api-rpc/model"
+	substrateWs "github.com/itering/substrate-api-rpc/websocket"
+)
+
+var (
+	// mempoolEnable track transaction pool of node, pending extrinsics are polled with author_pendingExtrinsics
+	mempoolEnable = os.Getenv("MEMPOOL_ENABLE") == "true"
+	// mempoolTTL seconds pending transaction is kept after last seen
+	mempoolTTL = util.StringToInt(util.GetEnv("MEMPOOL_TTL", "600"))
+	// mempoolEthEndpoint evm websocket endpoint subscribed newPendingTransactions, evm mempool is disabled if empty
+	mempoolEthEndpoint = os.Getenv("MEMPOOL_ETH_WS_ENDPOINT")
+)
+
+const (
+	AuthorPendingExtrinsics = "author_pendingExtrinsics"
+	EthSubscription         = "eth_subscription"
+	// pendingExtrinsicsPollInterval interval of polling transaction pool of node
+	pendingExtrinsicsPollInterval = 3 * time.Second
+	// evmPendingPruneInterval interval of removing included or dropped evm transactions
+	evmPendingPruneInterval = 30 * time.Second
+	// evmPendingPruneBatch transactions looked up in one json-rpc batch
+	evmPendingPruneBatch = 100
+)
+
+func jsonRpcRequest(id int, method string, params ...interface{}) []byte {
+	if params == nil {
+		params = []interface{}{}
+	}
+	b, _ := json.Marshal(smodel.JsonRpcParams{Id: id, JsonRpc: "2.0", Method: method, Params: params})
+	return b
+}
+
+// pendingExtrinsicsAsTransactions extrinsics hash is blake2_256 of raw, same as included extrinsic
+func pendingExtrinsicsAsTransactions(extrinsics []model.ChainExtrinsic, raws []string, seen int64) []model.PendingTransaction {
+	var list []model.PendingTransaction
+	for index, e := range extrinsics {
+		if index >= len(raws) {
+			break
+		}
+		hash := e.ExtrinsicHash
+		if hash == "" {
+			hash = util.AddHex(util.BytesToHex(hasher.HashByCryptoName(util.HexToBytes(raws[index]), "Blake2_256")))
+		}
+		list = append(list, model.PendingTransaction{
+			Hash:               strings.ToLower(hash),
+			Source:             model.PendingSourceSubstrate,
+			FirstSeen:          seen,
+			CallModule:         e.CallModule,
+			CallModuleFunction: e.CallModuleFunction,
+			AccountId:          address.Format(e.AccountId),
+			Nonce:              e.Nonce,
+			Params:             e.Params,
+		})
+	}
+	return list
+}
+
+// pollPendingExtrinsics poll author_pendingExtrinsics in its own goroutine, block subscription is never blocked by mempool
+func (s *Service) pollPendingExtrinsics(ctx context.Context) {
+	ticker := time.NewTicker(pendingExtrinsicsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			v := &smodel.JsonRpcResult{}
+			if err := substrateWs.SendWsRequest(nil, v, jsonRpcRequest(rand.Intn(10000), AuthorPendingExtrinsics)); err != nil {
+				util.Logger().Error(fmt.Errorf("pending extrinsics request error: %v", err))
+				continue
+			}
+			var raws []string
+			if v.CheckErr() != nil || util.UnmarshalAny(&raws, v.Result) != nil {
+				continue
+			}
+			if err := s.updatePendingExtrinsics(ctx, raws); err != nil {
+				util.Logger().Error(fmt.Errorf("update pending extrinsics error: %v", err))
+			}
+		}
+	}
+}
+
+// updatePendingExtrinsics replace pending extrinsics with transaction pool of node,
+// extrinsics left pool are included or dropped
+func (s *Service) updatePendingExtrinsics(ctx context.Context, raws []string) error {
+	spec := util.CurrentRuntimeSpecVersion
+	if spec == 0 {
+		return nil
+	}
+	var list []model.PendingTransaction
+	if len(raws) > 0 {
+		decoded, err := substrate.DecodeExtrinsic(raws, s.getMetadataInstant(spec, ""), spec)
+		if err != nil {
+			return err
+		}
+		var extrinsics []model.ChainExtrinsic
+		_ = util.UnmarshalAny(&extrinsics, decoded)
+		list = pendingExtrinsicsAsTransactions(extrinsics, raws, time.Now().Unix())
+	}
+	if err := s.dao.SavePendingTransactions(ctx, list, mempoolTTL); err != nil {
+		return err
+	}
+	stored, err := s.dao.PendingTransactionHashes(ctx, model.PendingSourceSubstrate)
+	if err != nil {
+		return err
+	}
+	return s.dao.RemovePendingTransactions(ctx, model.PendingSourceSubstrate, stalePendingHashes(stored, list)...)
+}
+
+func stalePendingHashes(stored []string, pending []model.PendingTransaction) []string {
+	current := make(map[string]bool)
+	for _, tx := range pending {
+		current[tx.Hash] = true
+	}
+	var stale []string
+	for _, hash := range stored {
+		if !current[hash] {
+			stale = append(stale, hash)
+		}
+	}
+	return stale
+}
+
+// subscribePendingEvmTransactions subscribe newPendingTransactions of evm endpoint, reconnect if closed
+func (s *Service) subscribePendingEvmTransactions(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(evmPendingPruneInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.prunePendingEvmTransactions(ctx)
+			}
+		}
+	}()
+	for {
+		if err := s.watchPendingEvmTransactions(ctx); err != nil {
+			util.Logger().Error(fmt.Errorf("evm pending transactions subscribe error: %v", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+func (s *Service) watchPendingEvmTransactions(ctx context.Context) error {
+	c, _, err := websocket.DefaultDialer.DialContext(ctx, mempoolEthEndpoint, nil)
+	if err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		safeClose(c)
+	}()
+	if err = c.WriteMessage(websocket.TextMessage, jsonRpcRequest(1, "eth_subscribe", "newPendingTransactions")); err != nil {
+		return err
+	}
+	for {
+		_, message, err := c.ReadMessage()
+		if err != nil {
+			return err
+		}
+		var j smodel.JsonRpcResult
+		if err = json.Unmarshal(message, &j); err != nil {
+			continue
+		}
+		if j.Error != nil {
+			return j.CheckErr()
+		}
+		if j.Method != EthSubscription || j.Params == nil {
+			continue
+		}
+		if hash := util.ToString(j.Params.Result); hash != "" {
+			if err = s.savePendingEvmTransaction(ctx, hash); err != nil {
+				util.Logger().Error(err)
+			}
+		}
+	}
+}
+
+func (s *Service) savePendingEvmTransaction(ctx context.Context, hash string) error {
+	tx := model.PendingTransaction{Hash: strings.ToLower(hash), Source: model.PendingSourceEvm, FirstSeen: time.Now().Unix()}
+	if detail, err := web3.RPC.Eth.GetTransactionByHash(ctx, hash); err == nil && detail != nil {
+		if detail.BlockNumber != nil {
+			return nil
+		}
+		tx.From = strings.ToLower(detail.From)
+		tx.To = strings.ToLower(detail.To)
+		tx.Input = detail.Input
+		if detail.Nonce != nil {
+			tx.Nonce = int(detail.Nonce.Int64())
+		}
+		if detail.Value != nil {
+			tx.Value = detail.Value.String()
+		}
+	}
+	return s.dao.SavePendingTransactions(ctx, []model.PendingTransaction{tx}, mempoolTTL)
+}
+
+// prunePendingEvmTransactions remove evm transactions included in block or no longer known by node
+func (s *Service) prunePendingEvmTransactions(ctx context.Context) {
+	hashes, err := s.dao.PendingTransactionHashes(ctx, model.PendingSourceEvm)
+	if err != nil {
+		return
+	}
+	for start := 0; start < len(hashes); start += evmPendingPruneBatch {
+		batch := hashes[start:min(start+evmPendingPruneBatch, len(hashes))]
+		details, err := web3.RPC.Eth.GetTransactionsByHash(ctx, batch)
+		if err != nil {
+			util.Logger().Error(fmt.Errorf("evm pending transactions lookup error: %v", err))
+			return
+		}
+		_ = s.dao.RemovePendingTransactions(ctx, model.PendingSourceEvm, prunedEvmHashes(batch, details)...)
+	}
+}
+
+// prunedEvmHashes transactions unknown by node (dropped) or included in block
+func prunedEvmHashes(hashes []string, details []*dto.TransactionResponse) []string {
+	var stale []string
+	for index, hash := range hashes {
+		if index >= len(details) {
+			break
+		}
+		if detail := details[index]; detail == nil || detail.BlockNumber != nil {
+			stale = append(stale, hash)
+		}
+	}
+	return stale
+}
+
+// PendingTransactions latest seen pending transactions, both substrate and evm if source is empty
+func (s *Service) PendingTransactions(ctx context.Context, source string, limit int) []model.PendingTransaction {
+	var list []model.PendingTransaction
+	for _, from := range []string{model.PendingSourceSubstrate, model.PendingSourceEvm} {
+		if source == "" || source == from {
+			list = append(list, s.dao.GetPendingTransactionList(ctx, from, limit)...)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].FirstSeen > list[j].FirstSeen })
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list
+}
+
+func (s *Service) GetPendingTransaction(ctx context.Context, hash string) *model.PendingTransaction {
+	return s.dao.GetPendingTransaction(ctx, hash)
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"github.com/itering/subscan/model"
+	"github.com/itering/subscan/pkg/go-web3/dto"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func Test_pendingExtrinsicsAsTransactions(t *testing.T) {
+	raws := []string{"0x280402000b10449a7e7301", "0x1c0407005e8b4100"}
+	extrinsics := []model.ChainExtrinsic{
+		{CallModule: "timestamp", CallModuleFunction: "set"},
+		{CallModule: "finality_tracker", CallModuleFunction: "final_hint", ExtrinsicHash: "0xABCD"},
+	}
+	list := pendingExtrinsicsAsTransactions(extrinsics, raws, 1700000000)
+	assert.Len(t, list, 2)
+	assert.Len(t, list[0].Hash, 66)
+	assert.Equal(t, model.PendingSourceSubstrate, list[0].Source)
+	assert.Equal(t, "timestamp", list[0].CallModule)
+	assert.Equal(t, int64(1700000000), list[0].FirstSeen)
+	assert.Equal(t, "0xabcd", list[1].Hash)
+
+	assert.Len(t, pendingExtrinsicsAsTransactions(extrinsics, raws[:1], 0), 1)
+}
+
+func Test_stalePendingHashes(t *testing.T) {
+	pending := []model.PendingTransaction{{Hash: "0x01"}, {Hash: "0x02"}}
+	assert.Equal(t, []string{"0x03"}, stalePendingHashes([]string{"0x01", "0x03", "0x02"}, pending))
+	assert.Nil(t, stalePendingHashes([]string{"0x01"}, pending))
+	assert.Equal(t, []string{"0x01"}, stalePendingHashes([]string{"0x01"}, nil))
+}
+
+func Test_jsonRpcRequest(t *testing.T) {
+	assert.JSONEq(t, `{"id":4,"jsonrpc":"2.0","method":"author_pendingExtrinsics","params":[]}`, string(jsonRpcRequest(4, AuthorPendingExtrinsics)))
+	assert.JSONEq(t, `{"id":1,"jsonrpc":"2.0","method":"eth_subscribe","params":["newPendingTransactions"]}`, string(jsonRpcRequest(1, "eth_subscribe", "newPendingTransactions")))
+}
+
+func Test_prunedEvmHashes(t *testing.T) {
+	hashes := []string{"0x01", "0x02", "0x03"}
+	details := []*dto.TransactionResponse{nil, {Hash: "0x02"}, {Hash: "0x03", BlockNumber: big.NewInt(10)}}
+	// dropped and included are pruned, still pending is kept
+	assert.Equal(t, []string{"0x01", "0x03"}, prunedEvmHashes(hashes, details))
+	assert.Nil(t, prunedEvmHashes(hashes, nil))
+}
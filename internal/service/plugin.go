@@ -66,7 +66,7 @@ func (s *Service) emitBlock(_ context.Context, block *model.ChainBlock) (err err
 
 // after extrinsic created, emit extrinsic data to subscribe plugins
 func (s *Service) emitExtrinsic(_ context.Context, extrinsic *model.ChainExtrinsic) (err error) {
-	for _, pluginName := range subscribeExtrinsic[extrinsic.CallModule] {
+	for _, pluginName := range subscribeExtrinsic[strings.ToLower(extrinsic.CallModule)] {
 		if plugins.RegisteredPlugins[pluginName].Enable() {
 			if err = mq.Instant.Publish("plugin-extrinsic", "process", map[string]interface{}{"extrinsic_index": extrinsic.ExtrinsicIndex, "plugin_name": pluginName}); err != nil {
 				return err
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/itering/subscan/share/price"
+	"github.com/itering/subscan/util"
+	"gorm.io/gorm"
+)
+
+// RefreshTokenPrices save today price of tokens, skip if PRICE_SOURCE not set
+func (s *Service) RefreshTokenPrices(ctx context.Context) {
+	source := price.DefaultSource()
+	if source == nil {
+		return
+	}
+	count, err := price.Refresh(ctx, s.dbStorage.GetDbInstance().(*gorm.DB), source)
+	if err != nil {
+		util.Logger().Error(fmt.Errorf("refresh token prices from %s source failed: %v", source.Name(), err))
+		return
+	}
+	util.Logger().Info(fmt.Sprintf("refreshed %d token prices from %s source", count, source.Name()))
+}
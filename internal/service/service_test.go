@@ -81,6 +81,26 @@ func (m *MockDao) CreateNewSession(ctx context.Context, sessionId uint, validato
 	return nil
 }
 
+func (m *MockDao) SavePendingTransactions(c context.Context, list []model.PendingTransaction, ttl int) error {
+	return nil
+}
+
+func (m *MockDao) RemovePendingTransactions(c context.Context, source string, hashes ...string) error {
+	return nil
+}
+
+func (m *MockDao) PendingTransactionHashes(c context.Context, source string) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockDao) GetPendingTransaction(c context.Context, hash string) *model.PendingTransaction {
+	return nil
+}
+
+func (m *MockDao) GetPendingTransactionList(c context.Context, source string, limit int) []model.PendingTransaction {
+	return nil
+}
+
 func (m *MockDao) SplitBlockTable(blockNum uint) {}
 
 func (m *MockDao) GetBlockNumArr(ctx context.Context, start, end uint) []int {
@@ -204,7 +224,7 @@ func (m *MockDao) GetExtrinsicsByBlockNum(blockNum uint) []model.ChainExtrinsicJ
 func (m *MockDao) GetExtrinsicList(c context.Context, page, row int, order string, fixedTableIndex int, afterId uint, queryWhere ...model.Option) ([]model.ChainExtrinsic, int) {
 	return nil, 0
 }
-func (m *MockDao) GetExtrinsicListCursor(c context.Context, limit int, fixedTableIndex int, beforeId, afterId uint, queryWhere ...model.Option) ([]model.ChainExtrinsic, bool, bool) {
+func (m *MockDao) GetExtrinsicListCursor(c context.Context, limit int, fixedTableIndex int, beforeId, afterId uint, accountId string, queryWhere ...model.Option) ([]model.ChainExtrinsic, bool, bool) {
 	return []model.ChainExtrinsic{testSignedExtrinsic}, false, false
 }
 
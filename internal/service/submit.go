@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/itering/scale.go/types"
+	"github.com/itering/subscan/model"
+	"github.com/itering/subscan/util"
+	"github.com/itering/subscan/util/address"
+	"github.com/itering/substrate-api-rpc"
+	"github.com/itering/substrate-api-rpc/hasher"
+	smodel "github.com/itering/substrate-api-rpc/model"
+	"github.com/itering/substrate-api-rpc/rpc"
+	"github.com/itering/substrate-api-rpc/storage"
+	"github.com/itering/substrate-api-rpc/websocket"
+	"github.com/shopspring/decimal"
+)
+
+var ErrInvalidExtrinsic = errors.New("invalid signed extrinsic")
+
+// invalidTransactions variants of sp_runtime InvalidTransaction
+var invalidTransactions = []string{"Call", "Payment", "Future", "Stale", "BadProof", "AncientBirthBlock", "ExhaustsResources",
+	"Custom", "BadMandatory", "MandatoryValidation", "BadSigner", "IndeterminateImplicit", "UnknownOrigin"}
+
+// unknownTransactions variants of sp_runtime UnknownTransaction
+var unknownTransactions = []string{"CannotLookup", "NoUnsignedValidator", "Custom"}
+
+// DryRunOutcome decoded ApplyExtrinsicResult of system_dryRun
+type DryRunOutcome struct {
+	Success       bool        `json:"success"`
+	DispatchError interface{} `json:"dispatch_error,omitempty"`
+	ValidityError string      `json:"validity_error,omitempty"`
+}
+
+type ExtrinsicDryRunJson struct {
+	ExtrinsicHash      string                  `json:"extrinsic_hash"`
+	CallModule         string                  `json:"call_module"`
+	CallModuleFunction string                  `json:"call_module_function"`
+	Params             model.ExtrinsicParams   `json:"params"`
+	AccountId          string                  `json:"account_id"`
+	Nonce              int                     `json:"nonce"`
+	Class              string                  `json:"class"`
+	Weight             decimal.Decimal         `json:"weight"`
+	EstimateFee        decimal.Decimal         `json:"estimate_fee"`
+	FeeDetails         *PaymentQueryFeeDetails `json:"fee_details,omitempty"`
+	Outcome            *DryRunOutcome          `json:"outcome,omitempty"`
+	DryRunError        string                  `json:"dry_run_error,omitempty"`
+}
+
+func SystemDryRun(id int, encodedExtrinsic, hash string) []byte {
+	p := rpc.Param{Id: id, Method: "system_dryRun", Params: []string{encodedExtrinsic, hash}}
+	p.JsonRpc = "2.0"
+	b, _ := json.Marshal(p)
+	return b
+}
+
+// rpcError node rejected reason is in error data, like "Transaction has a bad signature"
+func rpcError(v *smodel.JsonRpcResult) error {
+	if v.Error == nil {
+		return nil
+	}
+	if v.Error.Data != nil {
+		return fmt.Errorf("%s: %v", v.Error.Message, v.Error.Data)
+	}
+	return errors.New(v.Error.Message)
+}
+
+func transactionValidityError(variants []string, index, custom byte) string {
+	if int(index) >= len(variants) {
+		return fmt.Sprintf("Unknown(%d)", index)
+	}
+	if variants[index] == "Custom" {
+		return fmt.Sprintf("Custom(%d)", custom)
+	}
+	return variants[index]
+}
+
+// decodeApplyExtrinsicResult Result<Result<(), DispatchError>, TransactionValidityError>
+func decodeApplyExtrinsicResult(raw string, spec int) (*DryRunOutcome, error) {
+	b := util.HexToBytes(raw)
+	if len(b) < 2 {
+		return nil, InvalidValue
+	}
+	if b[0] == 0 {
+		outcome := DryRunOutcome{Success: b[1] == 0}
+		if !outcome.Success {
+			outcome.DispatchError = util.BytesToHex(b[2:])
+			if decoded, _, err := storage.Decode(util.BytesToHex(b[2:]), "DispatchError", &types.ScaleDecoderOption{Spec: spec}); err == nil {
+				outcome.DispatchError = decoded
+			}
+		}
+		return &outcome, nil
+	}
+	if len(b) < 3 {
+		return nil, InvalidValue
+	}
+	var custom byte
+	if len(b) > 3 {
+		custom = b[3]
+	}
+	if b[1] == 0 {
+		return &DryRunOutcome{ValidityError: "Invalid::" + transactionValidityError(invalidTransactions, b[2], custom)}, nil
+	}
+	return &DryRunOutcome{ValidityError: "Unknown::" + transactionValidityError(unknownTransactions, b[2], custom)}, nil
+}
+
+// SubmitExtrinsic relay signed extrinsic to node with author_submitExtrinsic
+func (s *Service) SubmitExtrinsic(_ context.Context, extrinsic string) (string, error) {
+	v := &smodel.JsonRpcResult{}
+	if err := websocket.SendWsRequest(nil, v, rpc.AuthorSubmitExtrinsic(rand.Intn(10000), util.AddHex(extrinsic))); err != nil {
+		return "", err
+	}
+	if err := rpcError(v); err != nil {
+		return "", err
+	}
+	return v.ToString()
+}
+
+// DryRunExtrinsic decode signed extrinsic with latest metadata, estimate fee and weight,
+// dispatch outcome is empty if system_dryRun is not allowed by node
+func (s *Service) DryRunExtrinsic(ctx context.Context, extrinsic, blockHash string) (*ExtrinsicDryRunJson, error) {
+	spec := util.CurrentRuntimeSpecVersion
+	extrinsic = util.AddHex(extrinsic)
+	decoded, err := substrate.DecodeExtrinsic([]string{extrinsic}, s.getMetadataInstant(spec, ""), spec)
+	if err != nil {
+		return nil, err
+	}
+	var extrinsics []model.ChainExtrinsic
+	if err = util.UnmarshalAny(&extrinsics, decoded); err != nil || len(extrinsics) == 0 {
+		return nil, ErrInvalidExtrinsic
+	}
+	e := extrinsics[0]
+	if blockHash == "" {
+		num, _ := s.dao.GetBestBlockNum(ctx)
+		if blockHash, err = rpc.GetChainGetBlockHash(nil, int(num)); err != nil {
+			return nil, err
+		}
+	}
+	res := ExtrinsicDryRunJson{
+		ExtrinsicHash:      util.AddHex(util.BytesToHex(hasher.HashByCryptoName(util.HexToBytes(extrinsic), "Blake2_256"))),
+		CallModule:         e.CallModule,
+		CallModuleFunction: e.CallModuleFunction,
+		Params:             e.Params,
+		AccountId:          address.Format(e.AccountId),
+		Nonce:              e.Nonce,
+	}
+	// weight v2 is tried first, runtime info is decoded as v1 if failed
+	if paymentInfo, err := GetPaymentQueryInfo(ctx, spec, extrinsic, blockHash, true); err == nil && paymentInfo != nil {
+		res.Class, res.Weight, res.EstimateFee = paymentInfo.Class, paymentInfo.Weight, paymentInfo.PartialFee
+	}
+	if feeDetails, err := GetPaymentQueryFeeDetails(ctx, extrinsic, blockHash); err == nil && feeDetails != nil && feeDetails.InclusionFee != nil {
+		res.FeeDetails = feeDetails
+		if res.EstimateFee.IsZero() {
+			res.EstimateFee = feeDetails.EstimateFee()
+		}
+	}
+	v := &smodel.JsonRpcResult{}
+	if err = websocket.SendWsRequest(nil, v, SystemDryRun(rand.Intn(10000), extrinsic, blockHash)); err == nil {
+		err = rpcError(v)
+	}
+	if err != nil {
+		res.DryRunError = err.Error()
+		return &res, nil
+	}
+	if res.Outcome, err = decodeApplyExtrinsicResult(util.ToString(v.Result), spec); err != nil {
+		res.DryRunError = err.Error()
+	}
+	return &res, nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"github.com/itering/substrate-api-rpc/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_decodeApplyExtrinsicResult(t *testing.T) {
+	outcome, err := decodeApplyExtrinsicResult("0x0000", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, &DryRunOutcome{Success: true}, outcome)
+
+	outcome, err = decodeApplyExtrinsicResult("0x010004", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Invalid::BadProof", outcome.ValidityError)
+
+	outcome, err = decodeApplyExtrinsicResult("0x01000709", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Invalid::Custom(9)", outcome.ValidityError)
+
+	outcome, err = decodeApplyExtrinsicResult("0x010100", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Unknown::CannotLookup", outcome.ValidityError)
+
+	outcome, err = decodeApplyExtrinsicResult("0x000102", 1)
+	assert.NoError(t, err)
+	assert.False(t, outcome.Success)
+	assert.NotNil(t, outcome.DispatchError)
+
+	_, err = decodeApplyExtrinsicResult("0x01", 1)
+	assert.Error(t, err)
+	_, err = decodeApplyExtrinsicResult("0x0100", 1)
+	assert.Error(t, err)
+}
+
+func Test_rpcError(t *testing.T) {
+	assert.NoError(t, rpcError(&model.JsonRpcResult{}))
+	assert.Equal(t, errors.New("Invalid Transaction: Transaction has a bad signature"),
+		rpcError(&model.JsonRpcResult{Error: &model.Error{Code: 1010, Message: "Invalid Transaction", Data: "Transaction has a bad signature"}}))
+	assert.Equal(t, errors.New("Method not found"), rpcError(&model.JsonRpcResult{Error: &model.Error{Code: -32601, Message: "Method not found"}}))
+}
+
+func Test_SystemDryRun(t *testing.T) {
+	assert.JSONEq(t, `{"id":1,"jsonrpc":"2.0","method":"system_dryRun","params":["0x00","0x01"]}`, string(SystemDryRun(1, "0x00", "0x01")))
+}
+
+func Test_transactionValidityError(t *testing.T) {
+	tests := []struct {
+		variants []string
+		index    byte
+		custom   byte
+		want     string
+	}{
+		{invalidTransactions, 2, 0, "Future"},
+		{invalidTransactions, 7, 3, "Custom(3)"},
+		{invalidTransactions, 20, 0, "Unknown(20)"},
+		{unknownTransactions, 1, 0, "NoUnsignedValidator"},
+		{unknownTransactions, 2, 1, "Custom(1)"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, transactionValidityError(tt.variants, tt.index, tt.custom))
+	}
+}
@@ -97,6 +97,12 @@ func (s *Service) Subscribe(ctx context.Context) {
 	subscribeSrv := s.initSubscribeService()
 	onceFinHead.Do(func() {
 		go subscribeSrv.subscribeFetchBlock(ctx)
+		if mempoolEnable {
+			go s.pollPendingExtrinsics(ctx)
+		}
+		if mempoolEnable && mempoolEthEndpoint != "" {
+			go s.subscribePendingEvmTransactions(ctx)
+		}
 	})
 
 	go func() {
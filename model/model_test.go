@@ -48,3 +48,25 @@ func TestModelPluginRender(t *testing.T) {
 	assert.Equal(t, &storage.Extrinsic{ExtrinsicHash: "0x0", Params: ExtrinsicParams.Marshal(), Fee: decimal.New(1, 0)}, extrinsic.AsPlugin())
 
 }
+
+func TestCheckoutExtrinsicProxy(t *testing.T) {
+	realAccount := "0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"
+	inner := "0x8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48"
+	call := map[string]interface{}{"call_module": "Balances", "call_name": "transfer_keep_alive", "params": []model.ExtrinsicParam{{Name: "value", Type: "compact<U128>", Value: "1"}}}
+	params := []model.ExtrinsicParam{
+		{Name: "real", Type: "AccountId", Value: map[string]interface{}{"Id": realAccount}},
+		{Name: "force_proxy_type", Type: "option<ProxyType>", Value: "Any"},
+		{Name: "call", Type: "Call", Value: call},
+	}
+	assert.Nil(t, model.CheckoutExtrinsicProxy("balances", "transfer", params))
+	assert.Equal(t, &model.ExtrinsicProxy{Real: model.CheckoutParamValueAddress(realAccount), ForceProxyType: "Any", CallModule: "balances", CallModuleFunction: "transfer_keep_alive"},
+		model.CheckoutExtrinsicProxy("Proxy", "proxy", params))
+
+	// nested proxy
+	nested := []model.ExtrinsicParam{
+		{Name: "real", Type: "AccountId", Value: inner},
+		{Name: "force_proxy_type", Type: "option<ProxyType>", Value: nil},
+		{Name: "call", Type: "Call", Value: map[string]interface{}{"call_module": "Proxy", "call_name": "proxy", "params": params}},
+	}
+	assert.Equal(t, model.CheckoutParamValueAddress(realAccount), model.CheckoutExtrinsicProxy("proxy", "proxy", nested).Real)
+}
@@ -0,0 +1,26 @@
+package model
+
+const (
+	PendingSourceSubstrate = "substrate"
+	PendingSourceEvm       = "evm"
+)
+
+// PendingTransaction extrinsic or evm transaction in transaction pool, not included in block yet
+type PendingTransaction struct {
+	Hash      string `json:"hash"`
+	Source    string `json:"source"`
+	FirstSeen int64  `json:"first_seen"`
+
+	// substrate extrinsic
+	CallModule         string          `json:"call_module,omitempty"`
+	CallModuleFunction string          `json:"call_module_function,omitempty"`
+	AccountId          string          `json:"account_id,omitempty"`
+	Nonce              int             `json:"nonce"`
+	Params             ExtrinsicParams `json:"params,omitempty"`
+
+	// evm transaction
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+	Value string `json:"value,omitempty"`
+	Input string `json:"input,omitempty"`
+}
@@ -444,6 +444,52 @@ func CheckoutParamValueAddress(value interface{}) string {
 	return address.Format(util.ToString(value))
 }
 
+// CheckoutExtrinsicProxy resolve real account and inner call of proxy.proxy/proxy.proxy_announced,
+// nested proxy call will be resolved to the innermost real account
+func CheckoutExtrinsicProxy(callModule, callModuleFunction string, params []ExtrinsicParam) *ExtrinsicProxy {
+	if !strings.EqualFold(callModule, "proxy") || !util.StringInSlice(strings.ToLower(callModuleFunction), []string{"proxy", "proxy_announced"}) {
+		return nil
+	}
+	var (
+		proxy ExtrinsicProxy
+		call  struct {
+			CallModule string           `json:"call_module"`
+			CallName   string           `json:"call_name"`
+			Params     []ExtrinsicParam `json:"params"`
+		}
+	)
+	for _, param := range params {
+		switch param.Name {
+		case "real":
+			proxy.Real = CheckoutParamValueAddress(param.Value)
+		case "force_proxy_type":
+			if param.Value != nil {
+				proxy.ForceProxyType = util.ToString(param.Value)
+			}
+		case "call":
+			_ = util.UnmarshalAny(&call, param.Value)
+		}
+	}
+	if proxy.Real == "" || call.CallModule == "" {
+		return nil
+	}
+	if inner := CheckoutExtrinsicProxy(call.CallModule, call.CallName, call.Params); inner != nil {
+		return inner
+	}
+	proxy.CallModule = strings.ToLower(call.CallModule)
+	proxy.CallModuleFunction = call.CallName
+	return &proxy
+}
+
+// ProxyJson proxy call info of extrinsic, real account is encoded
+func (c *ChainExtrinsic) ProxyJson() *ExtrinsicProxy {
+	proxy := CheckoutExtrinsicProxy(c.CallModule, c.CallModuleFunction, c.Params)
+	if proxy != nil {
+		proxy.Real = address.Encode(proxy.Real)
+	}
+	return proxy
+}
+
 type DispatchInfo struct {
 	Weight    decimal.Decimal
 	Class     string      `json:"class"`
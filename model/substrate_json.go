@@ -40,6 +40,15 @@ type ChainExtrinsicJson struct {
 	ExtrinsicHash      string          `json:"extrinsic_hash"`
 	Success            bool            `json:"success"`
 	Fee                decimal.Decimal `json:"fee"`
+	Proxy              *ExtrinsicProxy `json:"proxy,omitempty"`
+}
+
+// ExtrinsicProxy call executed through proxy, real is the origin account
+type ExtrinsicProxy struct {
+	Real               string `json:"real"`
+	ForceProxyType     string `json:"force_proxy_type,omitempty"`
+	CallModule         string `json:"call_module"`
+	CallModuleFunction string `json:"call_module_function"`
 }
 
 type ExtrinsicDetail struct {
@@ -57,6 +66,7 @@ type ExtrinsicDetail struct {
 	Fee                decimal.Decimal `json:"fee"`
 	Finalized          bool            `json:"finalized"`
 	Lifetime           *Lifetime       `json:"lifetime"`
+	Proxy              *ExtrinsicProxy `json:"proxy,omitempty"`
 }
 
 type Lifetime struct {
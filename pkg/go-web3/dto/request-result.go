@@ -178,6 +178,25 @@ func (pointer *RequestResult) ToTraceTransactionResponse() (*TracerTransactionRe
 	return traceTransactionResponse, json.Unmarshal(marshal, traceTransactionResponse)
 }
 
+func (pointer *RequestResult) ToTraceResponse() ([]TraceResponse, error) {
+	if err := pointer.checkResponse(); err != nil {
+		return nil, err
+	}
+	result := (pointer).Result.([]interface{})
+	if len(result) == 0 {
+		return nil, customerror.EMPTYRESPONSE
+	}
+
+	var traces []TraceResponse
+
+	marshal, err := json.Marshal(result)
+	if err != nil {
+		return nil, customerror.UNPARSEABLEINTERFACE
+	}
+
+	return traces, json.Unmarshal(marshal, &traces)
+}
+
 func (pointer *RequestResult) ToSignTransactionResponse() (*SignTransactionResponse, error) {
 	if err := pointer.checkResponse(); err != nil {
 		return nil, err
@@ -254,6 +273,26 @@ func (pointer *RequestResult) ToTransactionReceipt() (*TransactionReceipt, error
 
 }
 
+func (pointer *RequestResult) ToTransactionReceipts() ([]TransactionReceipt, error) {
+
+	if err := pointer.checkResponse(); err != nil {
+		return nil, err
+	}
+
+	marshal, err := json.Marshal(pointer.Result)
+
+	if err != nil {
+		return nil, customerror.UNPARSEABLEINTERFACE
+	}
+
+	var receipts []TransactionReceipt
+
+	err = json.Unmarshal(marshal, &receipts)
+
+	return receipts, err
+
+}
+
 func (pointer *RequestResult) ToBlock() (*Block, error) {
 
 	if err := pointer.checkResponse(); err != nil {
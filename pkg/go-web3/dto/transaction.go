@@ -93,6 +93,34 @@ type TracerTransactionResponse struct {
 	Value   string                      `json:"value,omitempty"`
 	Error   *string                     `json:"error,omitempty"`
 	Calls   []TracerTransactionResponse `json:"calls,omitempty"`
+
+	RevertReason *string `json:"revertReason,omitempty"`
+}
+
+// TraceResponse parity style trace, returned by trace_transaction
+type TraceResponse struct {
+	Action struct {
+		CallType      string `json:"callType,omitempty"`
+		From          string `json:"from"`
+		To            string `json:"to,omitempty"`
+		Gas           string `json:"gas,omitempty"`
+		Input         string `json:"input,omitempty"`
+		Init          string `json:"init,omitempty"`
+		Value         string `json:"value,omitempty"`
+		Address       string `json:"address,omitempty"`
+		RefundAddress string `json:"refundAddress,omitempty"`
+		Balance       string `json:"balance,omitempty"`
+	} `json:"action"`
+	Result *struct {
+		GasUsed string `json:"gasUsed,omitempty"`
+		Output  string `json:"output,omitempty"`
+		Address string `json:"address,omitempty"`
+		Code    string `json:"code,omitempty"`
+	} `json:"result,omitempty"`
+	Error        string `json:"error,omitempty"`
+	Subtraces    int    `json:"subtraces"`
+	TraceAddress []int  `json:"traceAddress"`
+	Type         string `json:"type"`
 }
 
 type SignedTransactionParams struct {
@@ -24,7 +24,9 @@ package eth
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/itering/subscan/pkg/go-web3/complex/types"
+	customerror "github.com/itering/subscan/pkg/go-web3/constants"
 	"github.com/itering/subscan/pkg/go-web3/dto"
 	"github.com/itering/subscan/pkg/go-web3/eth/block"
 	"github.com/itering/subscan/pkg/go-web3/providers"
@@ -263,6 +265,23 @@ func (eth *Eth) DebugTraceTransaction(ctx context.Context, hash string) (*dto.Tr
 	return pointer.ToTraceTransactionResponse()
 }
 
+// TraceTransaction - Returns all traces of given transaction, parity trace module.
+// Reference: https://openethereum.github.io/JSONRPC-trace-module#trace_transaction
+// Parameters:
+//   - DATA, 32 Bytes - hash of a transaction
+//
+// Returns:
+//  1. Array - flat list of traces, ordered by traceAddress
+func (eth *Eth) TraceTransaction(ctx context.Context, hash string) ([]dto.TraceResponse, error) {
+	params := []string{hash}
+	pointer := new(dto.RequestResult)
+	err := eth.provider.SendRequest(ctx, pointer, "trace_transaction", params)
+	if err != nil {
+		return nil, err
+	}
+	return pointer.ToTraceResponse()
+}
+
 // GetTransactionCount -  Returns the number of transactions sent from an address.
 // Reference: https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_gettransactionaccount
 // Parameters:
@@ -374,6 +393,70 @@ func (eth *Eth) GetTransactionByHash(ctx context.Context, hash string) (*dto.Tra
 
 }
 
+// GetTransactionsByHash - Returns the information about transactions, sent as one json-rpc batch if provider supported.
+// Parameters:
+//   - hashes, []DATA, 32 Bytes - hash of transactions
+//
+// Returns:
+//  1. []Object - transactions in the same order as hashes, nil if transaction is not found
+//  2. error
+func (eth *Eth) GetTransactionsByHash(ctx context.Context, hashes []string) ([]*dto.TransactionResponse, error) {
+
+	transactions := make([]*dto.TransactionResponse, len(hashes))
+
+	batch, ok := eth.provider.(providers.BatchProviderInterface)
+	if !ok {
+		for index, hash := range hashes {
+			transaction, err := eth.GetTransactionByHash(ctx, hash)
+			if errors.Is(err, customerror.EMPTYRESPONSE) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			transactions[index] = transaction
+		}
+		return transactions, nil
+	}
+
+	params := make([]interface{}, len(hashes))
+	for index, hash := range hashes {
+		params[index] = []string{hash}
+	}
+
+	var pointers []dto.RequestResult
+
+	if err := batch.SendBatchRequest(ctx, &pointers, "eth_getTransactionByHash", params); err != nil {
+		return nil, err
+	}
+
+	responded := make([]bool, len(hashes))
+	for index := range pointers {
+		pointer := pointers[index]
+		if pointer.ID < 0 || pointer.ID >= len(hashes) {
+			continue
+		}
+		responded[pointer.ID] = true
+		transaction, err := pointer.ToTransactionResponse()
+		if errors.Is(err, customerror.EMPTYRESPONSE) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		transactions[pointer.ID] = transaction
+	}
+
+	for index := range hashes {
+		if !responded[index] {
+			return nil, fmt.Errorf("missing response of transaction %s", hashes[index])
+		}
+	}
+
+	return transactions, nil
+
+}
+
 // GetTransactionByBlockHashAndIndex - Returns the information about a transaction requested by block hash.
 // Reference: https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_getTransactionByBlockNumberAndIndex
 // Parameters:
@@ -493,6 +576,30 @@ func (eth *Eth) SendTransaction(ctx context.Context, transaction *dto.Transactio
 
 }
 
+// SendRawTransaction - Creates new message call transaction or a contract creation for signed transactions.
+// Reference: https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_sendrawtransaction
+// Parameters:
+//  1. DATA, The signed transaction data.
+//
+// Returns:
+//   - DATA, 32 Bytes - the transaction hash, or the zero hash if the transaction is not yet available.
+func (eth *Eth) SendRawTransaction(ctx context.Context, rawTransaction string) (string, error) {
+
+	params := make([]string, 1)
+	params[0] = rawTransaction
+
+	pointer := &dto.RequestResult{}
+
+	err := eth.provider.SendRequest(ctx, &pointer, "eth_sendRawTransaction", params)
+
+	if err != nil {
+		return "", err
+	}
+
+	return pointer.ToString()
+
+}
+
 // SignTransaction - Signs transactions without dispatching it to the network. It can be later submitted using eth_sendRawTransaction.
 // Reference: https://wiki.parity.io/JSONRPC-eth-module.html#eth_signtransaction
 // Parameters:
@@ -625,6 +732,86 @@ func (eth *Eth) GetTransactionReceipt(ctx context.Context, hash string) (*dto.Tr
 
 }
 
+// GetTransactionReceipts - Returns the receipts of transactions, sent as one json-rpc batch if provider supported.
+// Parameters:
+//   - hashes, []DATA, 32 Bytes - hash of transactions
+//
+// Returns:
+//  1. []Object - receipts in the same order as hashes
+//  2. error
+func (eth *Eth) GetTransactionReceipts(ctx context.Context, hashes []string) ([]*dto.TransactionReceipt, error) {
+
+	receipts := make([]*dto.TransactionReceipt, len(hashes))
+
+	batch, ok := eth.provider.(providers.BatchProviderInterface)
+	if !ok {
+		for index, hash := range hashes {
+			receipt, err := eth.GetTransactionReceipt(ctx, hash)
+			if err != nil {
+				return nil, err
+			}
+			receipts[index] = receipt
+		}
+		return receipts, nil
+	}
+
+	params := make([]interface{}, len(hashes))
+	for index, hash := range hashes {
+		params[index] = []string{hash}
+	}
+
+	var pointers []dto.RequestResult
+
+	if err := batch.SendBatchRequest(ctx, &pointers, "eth_getTransactionReceipt", params); err != nil {
+		return nil, err
+	}
+
+	for index := range pointers {
+		pointer := pointers[index]
+		if pointer.ID < 0 || pointer.ID >= len(hashes) {
+			continue
+		}
+		receipt, err := pointer.ToTransactionReceipt()
+		if err != nil {
+			return nil, err
+		}
+		receipts[pointer.ID] = receipt
+	}
+
+	for index, receipt := range receipts {
+		if receipt == nil {
+			return nil, fmt.Errorf("missing receipt of transaction %s", hashes[index])
+		}
+	}
+
+	return receipts, nil
+
+}
+
+// GetBlockReceipts - Returns all transaction receipts of a block.
+// Parameters:
+//   - number, QUANTITY - number of block
+//
+// Returns:
+//  1. []Object - receipts of block, ordered by transaction index
+//  2. error
+func (eth *Eth) GetBlockReceipts(ctx context.Context, number *big.Int) ([]dto.TransactionReceipt, error) {
+
+	params := make([]interface{}, 1)
+	params[0] = utils.IntToHex(number)
+
+	pointer := &dto.RequestResult{}
+
+	err := eth.provider.SendRequest(ctx, pointer, "eth_getBlockReceipts", params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pointer.ToTransactionReceipts()
+
+}
+
 // GetBlockByNumber - Returns the information about a block requested by number.
 // Reference: https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_getblockbynumber
 // Parameters:
@@ -22,6 +22,7 @@
 package providers
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
@@ -99,4 +100,36 @@ func (provider HTTPProvider) SendRequest(ctx context.Context, v interface{}, met
 	return
 }
 
+func (provider HTTPProvider) SendBatchRequest(ctx context.Context, v any, method string, params []interface{}) (err error) {
+	batch := make([]util.JSONRPCObject, len(params))
+	for index, param := range params {
+		batch[index] = util.JSONRPCObject{Version: "2.0", Method: method, Params: param, ID: index}
+	}
+	var (
+		bodyBytes []byte
+		req       *http.Request
+		rsp       *http.Response
+		data      []byte
+	)
+	if bodyBytes, err = json.Marshal(batch); err != nil {
+		return
+	}
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, provider.address, bytes.NewReader(bodyBytes)); err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json;charset=utf-8")
+	req.Header.Add("Accept", "application/json")
+	if rsp, err = provider.client.Do(req); err != nil {
+		return
+	}
+	defer rsp.Body.Close()
+	if data, err = io.ReadAll(rsp.Body); err != nil {
+		return
+	}
+	if rsp.StatusCode != 200 {
+		return errors.New(rsp.Status)
+	}
+	return json.Unmarshal(data, v)
+}
+
 func (provider HTTPProvider) Close() error { return nil }
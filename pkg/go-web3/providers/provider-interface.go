@@ -27,3 +27,8 @@ type ProviderInterface interface {
 	SendRequest(ctx context.Context, v any, method string, params interface{}) error
 	Close() error
 }
+
+// BatchProviderInterface provider supports json-rpc batch request, params[i] is sent with id i
+type BatchProviderInterface interface {
+	SendBatchRequest(ctx context.Context, v any, method string, params []interface{}) error
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/itering/subscan/plugins/balance/http"
 	"github.com/itering/subscan/plugins/balance/model"
 	"github.com/itering/subscan/plugins/balance/service"
+	"github.com/itering/subscan/share/token"
 	"github.com/shopspring/decimal"
 	"github.com/urfave/cli"
 	"strings"
@@ -37,6 +38,20 @@ func (a *Balance) Commands() []cli.Command {
 				return nil
 			},
 		},
+		{
+			Name: "InitAsset",
+			Action: func(c *cli.Context) error {
+				dao.InitAsset(a.storage())
+				return nil
+			},
+		},
+		{
+			Name: "InitVesting",
+			Action: func(c *cli.Context) error {
+				dao.InitVesting(a.storage())
+				return nil
+			},
+		},
 		{
 			Name: "InitTransfer",
 			Action: func(c *cli.Context) error {
@@ -60,6 +75,7 @@ func (a *Balance) ProcessBlock(context.Context, *storage.Block) error { return n
 func (a *Balance) SetRedisPool(pool subscan_plugin.RedisPool) {
 	a.pool = pool
 	srv = service.New(a.d, pool)
+	token.SetRegistry(srv)
 }
 
 func New() *Balance {
@@ -75,7 +91,14 @@ func (a *Balance) InitHttp() []router.Http {
 	return http.Router(srv)
 }
 
-func (a *Balance) ProcessExtrinsic(*storage.Block, *storage.Extrinsic, []storage.Event) error {
+func (a *Balance) ProcessExtrinsic(block *storage.Block, extrinsic *storage.Extrinsic, _ []storage.Event) error {
+	if extrinsic == nil {
+		return nil
+	}
+	switch strings.ToLower(extrinsic.CallModule) {
+	case "vesting":
+		return dao.EmitVestingExtrinsic(context.TODO(), a.storage(), block, extrinsic)
+	}
 	return nil
 }
 
@@ -86,17 +109,23 @@ func (a *Balance) ProcessEvent(block *storage.Block, event *storage.Event, _ dec
 	switch strings.ToLower(event.ModuleId) {
 	case strings.ToLower("Balances"):
 		return dao.EmitEvent(context.TODO(), a.storage(), event, block)
+	case model.AssetCategoryAssets, model.AssetCategoryForeignAssets:
+		return dao.EmitAssetEvent(context.TODO(), a.storage(), event, block)
+	case model.AssetCategoryTokens:
+		return dao.EmitTokensEvent(context.TODO(), a.storage(), event, block)
+	case "vesting":
+		return dao.EmitVestingEvent(context.TODO(), a.storage(), event, block)
 	}
 
 	return nil
 }
 
 func (a *Balance) SubscribeExtrinsic() []string {
-	return nil
+	return []string{"vesting"}
 }
 
 func (a *Balance) SubscribeEvent() []string {
-	return []string{"balances"}
+	return []string{"balances", model.AssetCategoryAssets, model.AssetCategoryForeignAssets, model.AssetCategoryTokens, "vesting"}
 }
 
 func (a *Balance) Version() string {
@@ -106,6 +135,11 @@ func (a *Balance) Version() string {
 func (a *Balance) Migrate() {
 	_ = a.d.AutoMigration(&model.Account{})
 	_ = a.d.AutoMigration(&model.Transfer{})
+	_ = a.d.AutoMigration(&model.Asset{})
+	_ = a.d.AutoMigration(&model.AssetHolder{})
+	_ = a.d.AutoMigration(&model.AccountLock{})
+	_ = a.d.AutoMigration(&model.BalanceHistory{})
+	_ = a.d.AutoMigration(&model.VestingSchedule{})
 }
 
 func (a *Balance) ExecWorker(context.Context, string, string, interface{}) error { return nil }
@@ -7,7 +7,6 @@ import (
 	bModel "github.com/itering/subscan/plugins/balance/model"
 	"github.com/itering/subscan/util/address"
 	"github.com/itering/substrate-api-rpc/rpc"
-	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -60,6 +59,11 @@ func GetAccountByAddress(ctx context.Context, db storage.DB, address string) *bM
 }
 
 func RefreshAccount(ctx context.Context, s *Storage, accountId string) error {
+	return RefreshAccountWithEvent(ctx, s, accountId, nil, nil)
+}
+
+// RefreshAccountWithEvent refresh account balance, locks, and record balance history triggered by event
+func RefreshAccountWithEvent(ctx context.Context, s *Storage, accountId string, block *storage.Block, event *storage.Event) error {
 	accountId = address.Format(accountId)
 	if accountId == "" {
 		return nil
@@ -70,7 +74,16 @@ func RefreshAccount(ctx context.Context, s *Storage, accountId string) error {
 	if q.RowsAffected == 1 {
 		_, _ = s.Pool.HINCRBY(ctx, model.MetadataCacheKey(), "total_account", 1)
 	}
-	return AfterAccountCreate(ctx, db, &account)
+	if err := AfterAccountCreate(ctx, db, &account); err != nil {
+		return err
+	}
+	if err := RefreshAccountLocks(ctx, db, accountId); err != nil {
+		return err
+	}
+	if block != nil && event != nil {
+		return CreateBalanceHistory(ctx, db, accountId, block, event)
+	}
+	return nil
 }
 
 func AfterAccountCreate(ctx context.Context, db *gorm.DB, account *bModel.Account) error {
@@ -82,9 +95,10 @@ func AfterAccountCreate(ctx context.Context, db *gorm.DB, account *bModel.Accoun
 	accountDataRaw.ToAny(accountData)
 	return db.WithContext(ctx).Model(account).Where("address = ?", account.Address).UpdateColumns(map[string]interface{}{
 		"nonce":    accountData.Nonce,
-		"balance":  accountData.Data.Free.Add(accountData.Data.Reserved),
-		"locked":   decimal.Max(accountData.Data.MiscFrozen, accountData.Data.FeeFrozen),
+		"balance":  accountData.Total(),
+		"free":     accountData.Data.Free,
 		"reserved": accountData.Data.Reserved,
+		"frozen":   accountData.Frozen(),
 	}).Error
 }
 
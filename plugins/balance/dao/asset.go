@@ -0,0 +1,505 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/itering/scale.go/types"
+	"github.com/itering/subscan-plugin/storage"
+	"github.com/itering/subscan/model"
+	bModel "github.com/itering/subscan/plugins/balance/model"
+	"github.com/itering/subscan/share/substrate"
+	"github.com/itering/subscan/util"
+	"github.com/itering/subscan/util/address"
+	"github.com/itering/substrate-api-rpc/metadata"
+	"github.com/itering/substrate-api-rpc/rpc"
+	substrateStorage "github.com/itering/substrate-api-rpc/storage"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"strconv"
+	"strings"
+)
+
+// assetStorageModule asset category => runtime pallet name
+var assetStorageModule = map[string]string{
+	bModel.AssetCategoryAssets:        "Assets",
+	bModel.AssetCategoryForeignAssets: "ForeignAssets",
+	bModel.AssetCategoryTokens:        "Tokens",
+}
+
+// AssetIdFromParam convert asset id / currency id param to string
+// u32 asset id => "1984", Location or CurrencyId enum => json string
+func AssetIdFromParam(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	}
+	return util.ToString(value)
+}
+
+// assetIdFromKey convert decoded storage key to the same asset id as event param
+func assetIdFromKey(key substrateStorage.StateStorage) string {
+	var value interface{}
+	key.ToAny(&value)
+	return AssetIdFromParam(value)
+}
+
+// assetIdValue revert asset id string to the value used by scale encode
+func assetIdValue(assetId string) interface{} {
+	if d, err := decimal.NewFromString(assetId); err == nil {
+		return d
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(assetId), &v); err == nil {
+		return v
+	}
+	return assetId
+}
+
+// bytesParamToString Vec<u8> param to utf8 string
+func bytesParamToString(value interface{}) string {
+	s := util.ToString(value)
+	if strings.HasPrefix(s, "0x") {
+		return strings.TrimRight(string(util.HexToBytes(s)), "\x00")
+	}
+	return s
+}
+
+func EmitAssetEvent(ctx context.Context, d *Storage, event *storage.Event, block *storage.Block) error {
+	var paramEvent []storage.EventParam
+	_ = util.UnmarshalAny(&paramEvent, event.Params)
+	if len(paramEvent) < 2 {
+		return nil
+	}
+	category := strings.ToLower(event.ModuleId)
+	assetId := AssetIdFromParam(paramEvent[0].Value)
+	switch event.EventId {
+	// [asset_id, creator, owner] or [asset_id, owner]
+	case "Created", "ForceCreated":
+		_, err := TouchAsset(ctx, d, category, assetId)
+		return err
+	// [asset_id, name, symbol, decimals, is_frozen]
+	case "MetadataSet":
+		if len(paramEvent) < 4 {
+			return nil
+		}
+		if _, err := TouchAsset(ctx, d, category, assetId); err != nil {
+			return err
+		}
+		return updateAssetMetadata(ctx, d, category, assetId, &bModel.AssetMetadata{
+			Name:     bytesParamToString(paramEvent[1].Value),
+			Symbol:   bytesParamToString(paramEvent[2].Value),
+			Decimals: util.IntFromInterface(paramEvent[3].Value),
+		})
+	// [asset_id]
+	case "MetadataCleared":
+		return updateAssetMetadata(ctx, d, category, assetId, &bModel.AssetMetadata{})
+	// [asset_id, owner, amount]
+	case "Issued", "Burned", "Deposited", "Withdrawn":
+		_ = RefreshAssetSupply(ctx, d, category, assetId)
+		return RefreshAssetHolder(ctx, d, category, assetId, model.CheckoutParamValueAddress(paramEvent[1].Value))
+	// [asset_id, who]
+	case "Frozen", "Thawed", "Touched", "Blocked":
+		return RefreshAssetHolder(ctx, d, category, assetId, model.CheckoutParamValueAddress(paramEvent[1].Value))
+	// [asset_id, from, to, amount]
+	case "Transferred":
+		if len(paramEvent) < 4 {
+			return nil
+		}
+		return CreateAssetTransfer(ctx, d, event, block, category, assetId,
+			model.CheckoutParamValueAddress(paramEvent[1].Value),
+			model.CheckoutParamValueAddress(paramEvent[2].Value),
+			util.DecimalFromInterface(paramEvent[3].Value))
+	// [asset_id, owner, delegate, destination, amount]
+	case "TransferredApproved":
+		if len(paramEvent) < 5 {
+			return nil
+		}
+		return CreateAssetTransfer(ctx, d, event, block, category, assetId,
+			model.CheckoutParamValueAddress(paramEvent[1].Value),
+			model.CheckoutParamValueAddress(paramEvent[3].Value),
+			util.DecimalFromInterface(paramEvent[4].Value))
+	}
+	return nil
+}
+
+// EmitTokensEvent ORML tokens pallet event
+func EmitTokensEvent(ctx context.Context, d *Storage, event *storage.Event, block *storage.Block) error {
+	var paramEvent []storage.EventParam
+	_ = util.UnmarshalAny(&paramEvent, event.Params)
+	if len(paramEvent) < 2 {
+		return nil
+	}
+	category := bModel.AssetCategoryTokens
+	currencyId := AssetIdFromParam(paramEvent[0].Value)
+	if _, err := TouchAsset(ctx, d, category, currencyId); err != nil {
+		return err
+	}
+	switch event.EventId {
+	// [currency_id, from, to, amount]
+	case "Transfer":
+		if len(paramEvent) < 4 {
+			return nil
+		}
+		return CreateAssetTransfer(ctx, d, event, block, category, currencyId,
+			model.CheckoutParamValueAddress(paramEvent[1].Value),
+			model.CheckoutParamValueAddress(paramEvent[2].Value),
+			util.DecimalFromInterface(paramEvent[3].Value))
+	// [currency_id, amount]
+	case "Issued", "Rescinded", "TotalIssuanceSet":
+		return RefreshAssetSupply(ctx, d, category, currencyId)
+	// [currency_id, who, amount]
+	case "Deposited", "Withdrawn":
+		_ = RefreshAssetSupply(ctx, d, category, currencyId)
+		return RefreshAssetHolder(ctx, d, category, currencyId, model.CheckoutParamValueAddress(paramEvent[1].Value))
+	case "Endowed", "DustLost", "Reserved", "Unreserved", "Slashed", "BalanceSet", "Locked", "Unlocked", "LockSet", "LockRemoved":
+		return RefreshAssetHolder(ctx, d, category, currencyId, model.CheckoutParamValueAddress(paramEvent[1].Value))
+	}
+	return nil
+}
+
+// TouchAsset register asset if not exists, fill supply and metadata from chain storage when created
+func TouchAsset(ctx context.Context, d *Storage, category, assetId string) (*bModel.Asset, error) {
+	db := d.Dao.GetDbInstance().(*gorm.DB)
+	asset := bModel.Asset{Category: category, AssetId: assetId}
+	q := db.WithContext(ctx).Scopes(model.IgnoreDuplicate).Where("category = ? and asset_id = ?", category, assetId).FirstOrCreate(&asset)
+	if q.Error != nil {
+		return nil, q.Error
+	}
+	if q.RowsAffected == 1 {
+		_ = RefreshAsset(ctx, d, category, assetId)
+		if refreshed := GetAsset(ctx, d.Dao, category, assetId); refreshed != nil {
+			return refreshed, nil
+		}
+	}
+	return &asset, nil
+}
+
+// RefreshAsset refresh asset owner, supply and metadata from chain storage
+func RefreshAsset(ctx context.Context, d *Storage, category, assetId string) error {
+	if err := RefreshAssetSupply(ctx, d, category, assetId); err != nil {
+		return err
+	}
+	var meta bModel.AssetMetadata
+	switch category {
+	case bModel.AssetCategoryTokens:
+		// ORML tokens pallet has no metadata, use asset registry when runtime supported
+		if util.StringInSlice("AssetRegistry", metadata.SupportModule()) {
+			if raw, err := readAssetStorage("AssetRegistry", "AssetMetadatas", assetIdValue(assetId)); err == nil && raw != "" {
+				raw.ToAny(&meta)
+			}
+		}
+		if meta.Symbol == "" {
+			meta.Symbol = currencySymbol(assetId)
+		}
+	default:
+		raw, err := readAssetStorage(assetStorageModule[category], "Metadata", assetIdValue(assetId))
+		if err != nil {
+			return err
+		}
+		raw.ToAny(&meta)
+	}
+	return updateAssetMetadata(ctx, d, category, assetId, &meta)
+}
+
+// RefreshAssetSupply refresh asset total supply(and owner) from chain storage
+func RefreshAssetSupply(ctx context.Context, d *Storage, category, assetId string) error {
+	db := d.Dao.GetDbInstance().(*gorm.DB)
+	updates := make(map[string]interface{})
+	switch category {
+	case bModel.AssetCategoryTokens:
+		raw, err := readAssetStorage(assetStorageModule[category], "TotalIssuance", assetIdValue(assetId))
+		if err != nil {
+			return err
+		}
+		updates["supply"] = raw.ToDecimal()
+	default:
+		raw, err := readAssetStorage(assetStorageModule[category], "Asset", assetIdValue(assetId))
+		if err != nil {
+			return err
+		}
+		var details bModel.AssetDetails
+		raw.ToAny(&details)
+		updates["supply"] = details.Supply
+		updates["owner"] = address.Format(details.Owner)
+	}
+	return db.WithContext(ctx).Model(bModel.Asset{}).Where("category = ? and asset_id = ?", category, assetId).UpdateColumns(updates).Error
+}
+
+func updateAssetMetadata(ctx context.Context, d *Storage, category, assetId string, meta *bModel.AssetMetadata) error {
+	db := d.Dao.GetDbInstance().(*gorm.DB)
+	return db.WithContext(ctx).Model(bModel.Asset{}).Where("category = ? and asset_id = ?", category, assetId).UpdateColumns(map[string]interface{}{
+		"name":     bytesParamToString(meta.Name),
+		"symbol":   bytesParamToString(meta.Symbol),
+		"decimals": meta.Decimals,
+	}).Error
+}
+
+// RefreshAssetHolder refresh holder balance of asset from chain storage
+func RefreshAssetHolder(ctx context.Context, d *Storage, category, assetId, accountId string) error {
+	if err := refreshAssetHolderBalance(ctx, d, category, assetId, accountId); err != nil {
+		return err
+	}
+	return refreshAssetHolderCount(ctx, d, category, assetId)
+}
+
+func refreshAssetHolderBalance(ctx context.Context, d *Storage, category, assetId, accountId string) error {
+	accountId = address.Format(accountId)
+	if accountId == "" {
+		return nil
+	}
+	var balance decimal.Decimal
+	switch category {
+	case bModel.AssetCategoryTokens:
+		raw, err := readAssetStorage(assetStorageModule[category], "Accounts", accountId, assetIdValue(assetId))
+		if err != nil {
+			return err
+		}
+		var account bModel.TokensAccount
+		raw.ToAny(&account)
+		balance = account.Free.Add(account.Reserved)
+	default:
+		raw, err := readAssetStorage(assetStorageModule[category], "Account", assetIdValue(assetId), accountId)
+		if err != nil {
+			return err
+		}
+		var account bModel.AssetAccount
+		raw.ToAny(&account)
+		balance = account.Balance
+	}
+	return d.AddOrUpdateItem(ctx, &bModel.AssetHolder{Category: category, AssetId: assetId, Address: accountId, Balance: balance},
+		[]string{"category", "asset_id", "address"}, "balance").Error
+}
+
+func refreshAssetHolderCount(ctx context.Context, d *Storage, category, assetId string) error {
+	db := d.Dao.GetDbInstance().(*gorm.DB)
+	var count int64
+	db.WithContext(ctx).Model(bModel.AssetHolder{}).Where("category = ? and asset_id = ?", category, assetId).Where("balance > 0").Count(&count)
+	return db.WithContext(ctx).Model(bModel.Asset{}).Where("category = ? and asset_id = ?", category, assetId).UpdateColumn("holders", count).Error
+}
+
+func CreateAssetTransfer(ctx context.Context, d *Storage, event *storage.Event, block *storage.Block, category, assetId, from, to string, amount decimal.Decimal) error {
+	asset, err := TouchAsset(ctx, d, category, assetId)
+	if err != nil {
+		return err
+	}
+	db := d.Dao.GetDbInstance().(*gorm.DB)
+	transfer := &bModel.Transfer{
+		Id:             event.Id,
+		Sender:         from,
+		Receiver:       to,
+		Amount:         amount,
+		BlockNum:       uint(event.BlockNum),
+		BlockTimestamp: int64(block.BlockTimestamp),
+		Symbol:         asset.Symbol,
+		TokenId:        assetId,
+		Category:       category,
+		Decimals:       asset.Decimals,
+		ExtrinsicIndex: fmt.Sprintf("%d-%d", event.BlockNum, event.ExtrinsicIdx),
+	}
+	query := db.WithContext(ctx).Scopes(model.IgnoreDuplicate).Create(transfer)
+	if query.RowsAffected > 0 {
+		_, _ = d.Pool.HINCRBY(ctx, model.MetadataCacheKey(), "total_asset_transfer", 1)
+		_ = RefreshAssetHolder(ctx, d, category, assetId, from)
+		_ = RefreshAssetHolder(ctx, d, category, assetId, to)
+	}
+	return query.Error
+}
+
+// readAssetStorage read map storage, args will be scale encoded by storage key types
+func readAssetStorage(module, method string, args ...interface{}) (substrateStorage.StateStorage, error) {
+	encoded, err := encodeStorageArgs(module, method, args...)
+	if err != nil {
+		return "", err
+	}
+	return rpc.ReadStorage(nil, module, method, "", encoded...)
+}
+
+func encodeStorageArgs(module, method string, args ...interface{}) (encoded []string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("encode storage %s.%s args error: %v", module, method, r)
+		}
+	}()
+	keyTypes := substrate.StorageKeyTypes(module, method)
+	if len(keyTypes) < len(args) {
+		return nil, fmt.Errorf("storage %s.%s not found", module, method)
+	}
+	for index, arg := range args {
+		// account id already is hex public key
+		if accountId, ok := arg.(string); ok && address.Format(accountId) != "" {
+			encoded = append(encoded, util.TrimHex(accountId))
+			continue
+		}
+		encoded = append(encoded, types.Encode(keyTypes[index], arg))
+	}
+	return
+}
+
+// currencySymbol ORML currency id symbol, {"Token":"KSM"} => KSM
+func currencySymbol(currencyId string) string {
+	var enum map[string]interface{}
+	if err := json.Unmarshal([]byte(currencyId), &enum); err != nil || len(enum) != 1 {
+		return currencyId
+	}
+	if symbol, ok := enum[util.EnumKey(enum)].(string); ok {
+		return symbol
+	}
+	return currencyId
+}
+
+// InitAsset fill assets registry and holders from chain storage
+func InitAsset(sg *Storage) {
+	ctx := context.Background()
+	support := metadata.SupportModule()
+	for _, category := range []string{bModel.AssetCategoryAssets, bModel.AssetCategoryForeignAssets} {
+		module := assetStorageModule[category]
+		if !util.StringInSliceFold(module, support) {
+			continue
+		}
+		touched := make(map[string]struct{})
+		if err := substrate.BatchReadKeysPaged(ctx, module, "Account", "", func(keys []string, _ string) error {
+			for _, key := range keys {
+				val, err := substrate.ParseStorageKey(key)
+				if err != nil || len(val) < 2 {
+					continue
+				}
+				assetId := assetIdFromKey(val[0])
+				if _, ok := touched[assetId]; !ok {
+					if _, err = TouchAsset(ctx, sg, category, assetId); err != nil {
+						return err
+					}
+					touched[assetId] = struct{}{}
+				}
+				util.Logger().Error(refreshAssetHolderBalance(ctx, sg, category, assetId, val[1].ToString()))
+			}
+			return nil
+		}); err != nil {
+			util.Logger().Error(err)
+		}
+		// recount holders once per asset
+		for assetId := range touched {
+			util.Logger().Error(refreshAssetHolderCount(ctx, sg, category, assetId))
+		}
+	}
+}
+
+func GetAssetListCursor(db storage.DB, category string, limit int, before, after *uint) ([]bModel.Asset, bool, bool) {
+	var assets []bModel.Asset
+	d := db.GetDbInstance().(*gorm.DB)
+	fetch := limit + 1
+	var hasPrev, hasNext bool
+	q := d.Model(bModel.Asset{})
+	if category != "" {
+		q = q.Where("category = ?", category)
+	}
+	if after != nil && *after > 0 {
+		q = q.Where("id < ?", *after).Order("id desc")
+	} else if before != nil && *before > 0 {
+		q = q.Where("id > ?", *before).Order("id asc")
+	} else {
+		q = q.Order("id desc")
+	}
+	q = q.Limit(fetch).Find(&assets)
+	if q.Error != nil {
+		return nil, false, false
+	}
+	if before != nil && *before > 0 {
+		hasPrev = len(assets) > limit
+		if hasPrev {
+			assets = assets[:limit]
+		}
+		for i, j := 0, len(assets)-1; i < j; i, j = i+1, j-1 {
+			assets[i], assets[j] = assets[j], assets[i]
+		}
+		hasNext = true
+	} else {
+		hasNext = len(assets) > limit
+		if hasNext {
+			assets = assets[:limit]
+		}
+		hasPrev = after != nil && *after > 0
+	}
+	return assets, hasPrev, hasNext
+}
+
+func GetAsset(ctx context.Context, db storage.DB, category, assetId string) *bModel.Asset {
+	var asset bModel.Asset
+	d := db.GetDbInstance().(*gorm.DB)
+	if q := d.WithContext(ctx).Where("category = ? and asset_id = ?", category, assetId).First(&asset); q.Error != nil {
+		return nil
+	}
+	return &asset
+}
+
+var cursorDecode = func(c *string) []string {
+	if c == nil || *c == "" {
+		return nil
+	}
+	decoded := util.Base64Decode(*c)
+	if decoded == "" {
+		return nil
+	}
+	parts := strings.SplitN(decoded, "_", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	return []string{parts[0], parts[1]}
+}
+
+func GetAssetHoldersCursor(ctx context.Context, db storage.DB, category, assetId string, limit int, before, after *string) ([]bModel.AssetHolder, bool, bool) {
+	var list []bModel.AssetHolder
+	d := db.GetDbInstance().(*gorm.DB)
+	fetch := limit + 1
+	q := d.WithContext(ctx).Model(bModel.AssetHolder{}).Where("category = ? and asset_id = ?", category, assetId).Where("balance > 0")
+	if cursor := cursorDecode(after); len(cursor) == 2 {
+		q = q.Where("(balance,id) < (?,?)", cursor[0], cursor[1]).Order("balance desc").Order("id desc")
+	} else if cursor = cursorDecode(before); len(cursor) == 2 {
+		q = q.Where("(balance,id) > (?,?)", cursor[0], cursor[1]).Order("balance asc").Order("id asc")
+	} else {
+		q = q.Order("balance desc").Order("id desc")
+	}
+	q = q.Limit(fetch).Find(&list)
+	if q.Error != nil {
+		return nil, false, false
+	}
+	var hasPrev, hasNext bool
+	if before != nil && *before != "" {
+		hasPrev = len(list) > limit
+		if hasPrev {
+			list = list[:limit]
+		}
+		for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+			list[i], list[j] = list[j], list[i]
+		}
+		hasNext = true
+	} else {
+		hasNext = len(list) > limit
+		if hasNext {
+			list = list[:limit]
+		}
+		hasPrev = after != nil && *after != ""
+	}
+	return list, hasPrev, hasNext
+}
+
+func GetAccountAssets(ctx context.Context, db storage.DB, accountId string) (list []bModel.AssetHolder) {
+	d := db.GetDbInstance().(*gorm.DB)
+	d.WithContext(ctx).Model(bModel.AssetHolder{}).Where("address = ?", accountId).Where("balance > 0").Find(&list)
+	return
+}
+
+func GetAssets(ctx context.Context, db storage.DB) (list []bModel.Asset) {
+	d := db.GetDbInstance().(*gorm.DB)
+	d.WithContext(ctx).Model(bModel.Asset{}).Order("id asc").Find(&list)
+	return
+}
+
+// GetTopAccounts native token holders sorted by balance
+func GetTopAccounts(ctx context.Context, db storage.DB, limit int) (list []bModel.Account) {
+	d := db.GetDbInstance().(*gorm.DB)
+	d.WithContext(ctx).Model(bModel.Account{}).Where("balance > 0").Order("balance desc").Order("address asc").Limit(limit).Find(&list)
+	return
+}
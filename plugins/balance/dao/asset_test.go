@@ -0,0 +1,48 @@
+package dao
+
+import (
+	"testing"
+
+	bModel "github.com/itering/subscan/plugins/balance/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AssetIdFromParam(t *testing.T) {
+	assert.Equal(t, "1984", AssetIdFromParam(float64(1984)))
+	assert.Equal(t, "1984", AssetIdFromParam("1984"))
+	assert.Equal(t, `{"Token":"KSM"}`, AssetIdFromParam(map[string]interface{}{"Token": "KSM"}))
+}
+
+func Test_assetIdFromKey(t *testing.T) {
+	assert.Equal(t, "1984", assetIdFromKey("1984"))
+	location := map[string]interface{}{"parents": float64(1), "interior": map[string]interface{}{"X1": map[string]interface{}{"Parachain": float64(2011)}}}
+	assert.Equal(t, AssetIdFromParam(location), assetIdFromKey(`{"interior":{"X1":{"Parachain":2011}},"parents":1}`))
+	assert.Equal(t, AssetIdFromParam(location), assetIdFromKey(`{"parents": 1, "interior": {"X1": {"Parachain": 2011}}}`))
+}
+
+func Test_assetIdValue(t *testing.T) {
+	assert.Equal(t, decimal.New(1984, 0), assetIdValue("1984"))
+	assert.Equal(t, map[string]interface{}{"Token": "KSM"}, assetIdValue(`{"Token":"KSM"}`))
+	assert.Equal(t, "KSM", assetIdValue("KSM"))
+}
+
+func Test_bytesParamToString(t *testing.T) {
+	assert.Equal(t, "USDT", bytesParamToString("0x55534454"))
+	assert.Equal(t, "USDT", bytesParamToString("0x5553445400"))
+	assert.Equal(t, "USDT", bytesParamToString("USDT"))
+}
+
+func Test_currencySymbol(t *testing.T) {
+	assert.Equal(t, "KSM", currencySymbol(`{"Token":"KSM"}`))
+	assert.Equal(t, `{"ForeignAsset":1}`, currencySymbol(`{"ForeignAsset":1}`))
+	assert.Equal(t, "1984", currencySymbol("1984"))
+}
+
+func Test_cursorDecode(t *testing.T) {
+	cursor := bModel.AssetHolder{ID: 3, Balance: decimal.New(15, 0)}.Cursor()
+	assert.Equal(t, []string{"15", "3"}, cursorDecode(&cursor))
+	empty := ""
+	assert.Nil(t, cursorDecode(&empty))
+	assert.Nil(t, cursorDecode(nil))
+}
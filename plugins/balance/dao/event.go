@@ -22,8 +22,13 @@ func EmitEvent(ctx context.Context, d *Storage, event *storage.Event, block *sto
 	_ = util.UnmarshalAny(&paramEvent, event.Params)
 	switch event.EventId {
 	// [accountId, balance]
-	case "Endowed", "Reserved", "Unreserved", "Deposit", "Minted", "Issued", "Locked", "Unlocked", "Withdraw":
-		return RefreshAccount(ctx, d, model.CheckoutParamValueAddress(paramEvent[0].Value))
+	case "Endowed", "Reserved", "Unreserved", "Deposit", "Minted", "Issued", "Locked", "Unlocked", "Withdraw",
+		"Frozen", "Thawed", "Burned", "Suspended", "Restored", "Slashed", "DustLost":
+		return RefreshAccountWithEvent(ctx, d, model.CheckoutParamValueAddress(paramEvent[0].Value), block, event)
+		// ["AccountId","AccountId","Balance","BalanceStatus"]
+	case "ReserveRepatriated":
+		_ = RefreshAccountWithEvent(ctx, d, model.CheckoutParamValueAddress(paramEvent[0].Value), block, event)
+		return RefreshAccountWithEvent(ctx, d, model.CheckoutParamValueAddress(paramEvent[1].Value), block, event)
 		// ["AccountId","AccountId","Balance"]
 	case "Transfer":
 		from := model.CheckoutParamValueAddress(paramEvent[0].Value)
@@ -39,8 +44,10 @@ func EmitEvent(ctx context.Context, d *Storage, event *storage.Event, block *sto
 			BlockTimestamp: int64(block.BlockTimestamp),
 			Symbol:         t.Symbol,
 			TokenId:        t.TokenId,
+			Category:       bModel.AssetCategoryNative,
+			Decimals:       t.Decimals,
 			ExtrinsicIndex: fmt.Sprintf("%d-%d", event.BlockNum, event.ExtrinsicIdx),
-		})
+		}, block, event)
 	}
 	return nil
 }
@@ -50,7 +57,15 @@ func RefreshMetadata(ctx context.Context, d *Storage) {
 	var count int64
 	db := d.Dao.GetDbInstance().(*gorm.DB)
 	_ = db.Model(&bModel.Account{}).Count(&count)
-	var transferCount int64
-	_ = db.Model(&bModel.Transfer{}).Count(&transferCount)
-	_ = d.Pool.HmSetEx(ctx, model.MetadataCacheKey(), map[string]int{"total_transfer": int(transferCount), "total_account": int(count)}, -1)
+	var transferCount, assetTransferCount int64
+	_ = db.Model(&bModel.Transfer{}).Where("category = ?", bModel.AssetCategoryNative).Count(&transferCount)
+	_ = db.Model(&bModel.Transfer{}).Where("category <> ?", bModel.AssetCategoryNative).Count(&assetTransferCount)
+	var assetCount int64
+	_ = db.Model(&bModel.Asset{}).Count(&assetCount)
+	_ = d.Pool.HmSetEx(ctx, model.MetadataCacheKey(), map[string]int{
+		"total_transfer":       int(transferCount),
+		"total_asset_transfer": int(assetTransferCount),
+		"total_account":        int(count),
+		"total_asset":          int(assetCount),
+	}, -1)
 }
@@ -0,0 +1,189 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"github.com/itering/subscan-plugin/storage"
+	"github.com/itering/subscan/model"
+	bModel "github.com/itering/subscan/plugins/balance/model"
+	"github.com/itering/subscan/share/substrate"
+	"github.com/itering/subscan/util"
+	"github.com/itering/substrate-api-rpc/rpc"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// RefreshAccountLocks sync Balances.Locks, Balances.Holds and Balances.Freezes of account, and locked balance of account
+func RefreshAccountLocks(ctx context.Context, db *gorm.DB, accountId string) error {
+	var locks []bModel.AccountLock
+	for _, category := range []string{bModel.LockCategoryLock, bModel.LockCategoryHold, bModel.LockCategoryFreeze} {
+		items, err := readAccountLocks(category, accountId)
+		if err != nil {
+			return err
+		}
+		locks = append(locks, items...)
+	}
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(bModel.Account{}).Where("address = ?", accountId).UpdateColumn("locked", bModel.LockedAmount(locks)).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("address = ?", accountId).Delete(&bModel.AccountLock{}).Error; err != nil {
+			return err
+		}
+		if len(locks) == 0 {
+			return nil
+		}
+		return tx.Scopes(model.IgnoreDuplicate).Create(&locks).Error
+	})
+}
+
+var lockStorage = map[string]string{
+	bModel.LockCategoryLock:   "Locks",
+	bModel.LockCategoryHold:   "Holds",
+	bModel.LockCategoryFreeze: "Freezes",
+}
+
+func readAccountLocks(category, accountId string) ([]bModel.AccountLock, error) {
+	method := lockStorage[category]
+	// Holds and Freezes only exist after fungible migration
+	if len(substrate.StorageKeyTypes("Balances", method)) == 0 {
+		return nil, nil
+	}
+	raw, err := rpc.ReadStorage(nil, "Balances", method, "", accountId)
+	if err != nil {
+		return nil, err
+	}
+	var locks []bModel.AccountLock
+	if category == bModel.LockCategoryLock {
+		var items []bModel.BalanceLock
+		raw.ToAny(&items)
+		for _, item := range items {
+			locks = append(locks, bModel.AccountLock{
+				Address:  accountId,
+				Category: category,
+				LockId:   strings.TrimSpace(bytesParamToString(item.Id)),
+				Amount:   item.Amount,
+				Reasons:  item.Reasons,
+			})
+		}
+		return locks, nil
+	}
+	var items []bModel.IdAmount
+	raw.ToAny(&items)
+	for _, item := range items {
+		locks = append(locks, bModel.AccountLock{
+			Address:  accountId,
+			Category: category,
+			LockId:   reasonToString(item.Id),
+			Amount:   item.Amount,
+		})
+	}
+	return locks, nil
+}
+
+// reasonToString RuntimeHoldReason/RuntimeFreezeReason enum to string
+// {"Preimage":"Preimage"} => Preimage.Preimage, {"NominationPools":{"PoolMinBalance":null}} => NominationPools.PoolMinBalance
+func reasonToString(v interface{}) string {
+	switch r := v.(type) {
+	case string:
+		return r
+	case map[string]interface{}:
+		if len(r) != 1 {
+			break
+		}
+		for key, value := range r {
+			if sub := reasonToString(value); sub != "" {
+				return fmt.Sprintf("%s.%s", key, sub)
+			}
+			return key
+		}
+	}
+	return util.ToString(v)
+}
+
+func GetAccountLocks(ctx context.Context, db storage.DB, accountId string) (list []bModel.AccountLock) {
+	d := db.GetDbInstance().(*gorm.DB)
+	d.WithContext(ctx).Where("address = ?", accountId).Order("category asc").Order("amount desc").Find(&list)
+	return
+}
+
+// CreateBalanceHistory record account balance at block if balance changed
+func CreateBalanceHistory(ctx context.Context, db *gorm.DB, accountId string, block *storage.Block, event *storage.Event) error {
+	// balance of history should be read at event block
+	if block.Hash == "" {
+		return nil
+	}
+	raw, err := rpc.ReadStorage(nil, "System", "Account", block.Hash, accountId)
+	if err != nil {
+		return err
+	}
+	accountData := new(bModel.AccountData)
+	raw.ToAny(accountData)
+
+	history := bModel.BalanceHistory{
+		Address:        accountId,
+		EventId:        event.Id,
+		BlockNum:       uint(block.BlockNum),
+		BlockTimestamp: int64(block.BlockTimestamp),
+		EventIndex:     fmt.Sprintf("%d-%d", event.BlockNum, event.EventIdx),
+		ExtrinsicIndex: fmt.Sprintf("%d-%d", event.BlockNum, event.ExtrinsicIdx),
+		ModuleId:       event.ModuleId,
+		EventName:      event.EventId,
+		Free:           accountData.Data.Free,
+		Reserved:       accountData.Data.Reserved,
+		Frozen:         accountData.Frozen(),
+		Balance:        accountData.Total(),
+		Change:         accountData.Total(),
+	}
+
+	var prev bModel.BalanceHistory
+	q := db.WithContext(ctx).Where("address = ?", accountId).Where("block_num <= ?", block.BlockNum).Where("event_id <> ?", event.Id).Order("block_num desc").Order("id desc").Limit(1).Find(&prev)
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected > 0 {
+		// balance not changed, e.g. multiple events in one block
+		if prev.Balance.Equal(history.Balance) && prev.Free.Equal(history.Free) &&
+			prev.Reserved.Equal(history.Reserved) && prev.Frozen.Equal(history.Frozen) {
+			return nil
+		}
+		history.Change = history.Balance.Sub(prev.Balance)
+	}
+	return db.WithContext(ctx).Scopes(model.IgnoreDuplicate).Create(&history).Error
+}
+
+func BalanceHistoryCursor(ctx context.Context, db storage.DB, accountId string, limit int, before, after *uint) ([]bModel.BalanceHistory, bool, bool) {
+	var list []bModel.BalanceHistory
+	d := db.GetDbInstance().(*gorm.DB)
+	fetch := limit + 1
+	q := d.WithContext(ctx).Model(bModel.BalanceHistory{}).Where("address = ?", accountId)
+	var hasPrev, hasNext bool
+	if after != nil && *after > 0 {
+		q = q.Where("id < ?", *after).Order("id desc")
+	} else if before != nil && *before > 0 {
+		q = q.Where("id > ?", *before).Order("id asc")
+	} else {
+		q = q.Order("id desc")
+	}
+	q = q.Limit(fetch).Find(&list)
+	if q.Error != nil {
+		return nil, false, false
+	}
+	if before != nil && *before > 0 {
+		hasPrev = len(list) > limit
+		if hasPrev {
+			list = list[:limit]
+		}
+		for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+			list[i], list[j] = list[j], list[i]
+		}
+		hasNext = true
+	} else {
+		hasNext = len(list) > limit
+		if hasNext {
+			list = list[:limit]
+		}
+		hasPrev = after != nil && *after > 0
+	}
+	return list, hasPrev, hasNext
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/itering/subscan/util"
 	"github.com/itering/subscan/util/address"
 	"github.com/panjf2000/ants/v2"
-	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 	"log"
 	"sync"
@@ -27,10 +26,11 @@ func InitAccount(sg *Storage) {
 		sg.AddOrUpdateItem(ctx, &bModel.Account{
 			Address:  addr,
 			Nonce:    info.Nonce,
-			Balance:  info.Data.Free.Add(info.Data.Reserved),
-			Locked:   decimal.Max(info.Data.MiscFrozen, info.Data.FeeFrozen),
+			Balance:  info.Total(),
+			Free:     info.Data.Free,
 			Reserved: info.Data.Reserved,
-		}, []string{"address"}, "nonce", "balance", "locked", "reserved")
+			Frozen:   info.Frozen(),
+		}, []string{"address"}, "nonce", "balance", "free", "reserved", "frozen")
 	})
 	defer bp.Release()
 
@@ -49,6 +49,26 @@ func InitAccount(sg *Storage) {
 		log.Panic(err)
 	}
 	wg.Wait()
+
+	// locked balance of Balances.Locks
+	db := sg.Dao.GetDbInstance().(*gorm.DB)
+	if err := substrate.BatchReadKeysPaged(ctx, "Balances", "Locks", "", func(keys []string, scaleType string) error {
+		r, _ := substrate.BatchStorageByKey(ctx, keys, scaleType, "")
+		for key, v := range r {
+			val, _ := substrate.ParseStorageKey(key)
+			addr := address.Format(val[0].ToString())
+			var items []bModel.BalanceLock
+			v.ToAny(&items)
+			locks := make([]bModel.AccountLock, 0, len(items))
+			for _, item := range items {
+				locks = append(locks, bModel.AccountLock{Category: bModel.LockCategoryLock, Amount: item.Amount})
+			}
+			util.Logger().Error(db.WithContext(ctx).Model(bModel.Account{}).Where("address = ?", addr).UpdateColumn("locked", bModel.LockedAmount(locks)).Error)
+		}
+		return nil
+	}); err != nil {
+		log.Panic(err)
+	}
 }
 
 func RefreshAllAccount(_ *Storage) {
@@ -76,7 +96,7 @@ func InitTransfer(sg *Storage) {
 				blockNums = append(blockNums, e.BlockNum)
 			}
 
-			for _, b := range sg.Dao.GetBlocksByNums(c, blockNums, "id,block_num,block_timestamp") {
+			for _, b := range sg.Dao.GetBlocksByNums(c, blockNums, "id,block_num,block_timestamp,hash") {
 				blocks[b.BlockNum] = b
 			}
 
@@ -8,13 +8,13 @@ import (
 	"gorm.io/gorm"
 )
 
-func CreateTransfer(ctx context.Context, d *Storage, transfer *bModel.Transfer) error {
+func CreateTransfer(ctx context.Context, d *Storage, transfer *bModel.Transfer, block *storage.Block, event *storage.Event) error {
 	db := d.Dao.GetDbInstance().(*gorm.DB)
 	query := db.WithContext(ctx).Scopes(model.IgnoreDuplicate).Create(transfer)
 	if query.RowsAffected > 0 {
 		_, _ = d.Pool.HINCRBY(ctx, model.MetadataCacheKey(), "total_transfer", 1)
-		_ = RefreshAccount(ctx, d, model.CheckoutParamValueAddress(transfer.Sender))
-		_ = RefreshAccount(ctx, d, model.CheckoutParamValueAddress(transfer.Receiver))
+		_ = RefreshAccountWithEvent(ctx, d, model.CheckoutParamValueAddress(transfer.Sender), block, event)
+		_ = RefreshAccountWithEvent(ctx, d, model.CheckoutParamValueAddress(transfer.Receiver), block, event)
 	}
 	return query.Error
 }
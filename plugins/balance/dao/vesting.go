@@ -0,0 +1,159 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"github.com/itering/subscan-plugin/storage"
+	"github.com/itering/subscan/model"
+	bModel "github.com/itering/subscan/plugins/balance/model"
+	"github.com/itering/subscan/share/substrate"
+	"github.com/itering/subscan/util"
+	"github.com/itering/subscan/util/address"
+	"github.com/itering/substrate-api-rpc/rpc"
+	substrateStorage "github.com/itering/substrate-api-rpc/storage"
+	"gorm.io/gorm"
+	"log"
+	"strings"
+)
+
+var errFinalizedBlockNotFound = errors.New("finalized block not found")
+
+func EmitVestingEvent(ctx context.Context, d *Storage, event *storage.Event, block *storage.Block) error {
+	var paramEvent []storage.EventParam
+	_ = util.UnmarshalAny(&paramEvent, event.Params)
+	switch event.EventId {
+	// [account, unvested]
+	// [account]
+	case "VestingUpdated", "VestingCompleted":
+		accountId := model.CheckoutParamValueAddress(paramEvent[0].Value)
+		if err := RefreshVesting(ctx, d, accountId, block); err != nil {
+			return err
+		}
+		// vesting lock changed
+		return RefreshAccountWithEvent(ctx, d, accountId, block, event)
+	}
+	return nil
+}
+
+func EmitVestingExtrinsic(ctx context.Context, d *Storage, block *storage.Block, extrinsic *storage.Extrinsic) error {
+	if !extrinsic.Success {
+		return nil
+	}
+	var params []storage.ExtrinsicParam
+	_ = util.UnmarshalAny(&params, extrinsic.Params)
+	var accountId string
+	switch strings.ToLower(extrinsic.CallModuleFunction) {
+	// vested_transfer(target, schedule)
+	// force_vested_transfer(source, target, schedule)
+	case "vested_transfer", "force_vested_transfer":
+		for _, param := range params {
+			if param.Name == "target" {
+				accountId = model.CheckoutParamValueAddress(param.Value)
+			}
+		}
+	// merge_schedules(schedule1_index, schedule2_index)
+	case "merge_schedules":
+		accountId = address.Format(extrinsic.AccountId)
+	}
+	if accountId == "" {
+		return nil
+	}
+	return RefreshVesting(ctx, d, accountId, block)
+}
+
+// finalizedBlock latest finalized block, state of best block may be reverted
+func finalizedBlock(ctx context.Context, d *Storage) *storage.Block {
+	blockNum, err := d.Dao.GetCurrentBlockNum(ctx)
+	if err != nil {
+		return nil
+	}
+	if blocks := d.Dao.GetBlocksByNums(ctx, []uint{uint(blockNum)}, "block_num,hash"); len(blocks) > 0 && blocks[0].Hash != "" {
+		return blocks[0]
+	}
+	return nil
+}
+
+// RefreshVesting sync Vesting.Vesting schedules of account at block, latest finalized block if block hash unknown
+func RefreshVesting(ctx context.Context, d *Storage, accountId string, block *storage.Block) error {
+	accountId = address.Format(accountId)
+	if accountId == "" {
+		return nil
+	}
+	if block == nil || block.Hash == "" {
+		if block = finalizedBlock(ctx, d); block == nil {
+			return errFinalizedBlockNotFound
+		}
+	}
+	raw, err := rpc.ReadStorage(nil, "Vesting", "Vesting", block.Hash, accountId)
+	if err != nil {
+		return err
+	}
+	return saveVestingSchedules(ctx, d, accountId, uint(block.BlockNum), parseVestingInfo(raw))
+}
+
+// parseVestingInfo BoundedVec<VestingInfo> or legacy Option<VestingInfo>
+func parseVestingInfo(raw substrateStorage.StateStorage) []bModel.VestingInfo {
+	var list []bModel.VestingInfo
+	raw.ToAny(&list)
+	if len(list) > 0 {
+		return list
+	}
+	var info bModel.VestingInfo
+	raw.ToAny(&info)
+	if info.Locked.IsPositive() {
+		list = append(list, info)
+	}
+	return list
+}
+
+func saveVestingSchedules(ctx context.Context, d *Storage, accountId string, blockNum uint, list []bModel.VestingInfo) error {
+	db := d.Dao.GetDbInstance().(*gorm.DB)
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("address = ?", accountId).Delete(&bModel.VestingSchedule{}).Error; err != nil {
+			return err
+		}
+		var schedules []bModel.VestingSchedule
+		for index := range list {
+			schedules = append(schedules, *list[index].AsSchedule(accountId, index, blockNum))
+		}
+		if len(schedules) == 0 {
+			return nil
+		}
+		return tx.Create(&schedules).Error
+	})
+}
+
+func InitVesting(sg *Storage) {
+	ctx := context.Background()
+	block := finalizedBlock(ctx, sg)
+	if block == nil {
+		log.Panic(errFinalizedBlockNotFound)
+	}
+	if err := substrate.BatchReadKeysPaged(ctx, "Vesting", "Vesting", block.Hash, func(keys []string, scaleType string) error {
+		r, _ := substrate.BatchStorageByKey(ctx, keys, scaleType, block.Hash)
+		for key, v := range r {
+			val, _ := substrate.ParseStorageKey(key)
+			accountId := address.Format(val[0].ToString())
+			util.Logger().Error(saveVestingSchedules(ctx, sg, accountId, uint(block.BlockNum), parseVestingInfo(v)))
+		}
+		return nil
+	}); err != nil {
+		log.Panic(err)
+	}
+}
+
+func GetAccountVesting(ctx context.Context, db storage.DB, accountId string) (list []bModel.VestingSchedule) {
+	d := db.GetDbInstance().(*gorm.DB)
+	d.WithContext(ctx).Where("address = ?", accountId).Order("idx asc").Find(&list)
+	return
+}
+
+// GetUnfinishedVesting vesting schedules not fully unlocked at block
+func GetUnfinishedVesting(ctx context.Context, db storage.DB, blockNum uint, action func(list []bModel.VestingSchedule)) {
+	var list []bModel.VestingSchedule
+	d := db.GetDbInstance().(*gorm.DB)
+	d.WithContext(ctx).Where("end_block > ?", blockNum).FindInBatches(&list, 5000, func(tx *gorm.DB, batch int) error {
+		action(list)
+		return nil
+	})
+}
@@ -21,6 +21,14 @@ func Router(s *service.Service) []router.Http {
 		{"accounts", accountsHandle, http.MethodPost},
 		{"account", accountHandle, http.MethodPost},
 		{"transfer", transferHandle, http.MethodPost},
+		{"assets", assetsHandle, http.MethodPost},
+		{"asset", assetHandle, http.MethodPost},
+		{"asset/holders", assetHoldersHandle, http.MethodPost},
+		{"account/assets", accountAssetsHandle, http.MethodPost},
+		{"account/locks", accountLocksHandle, http.MethodPost},
+		{"account/balance_history", balanceHistoryHandle, http.MethodPost},
+		{"account/vesting", accountVestingHandle, http.MethodPost},
+		{"vesting/unlocks", vestingUnlocksHandle, http.MethodPost},
 	}
 }
 
@@ -76,6 +84,8 @@ func accountHandle(w http.ResponseWriter, r *http.Request) error {
 type transferParams struct {
 	Address  string `json:"address" validate:"omitempty,addr"`
 	BlockNum uint   `json:"block_num" validate:"omitempty,min=0"`
+	Category string `json:"category" validate:"omitempty,oneof=native assets foreignassets tokens"`
+	TokenId  string `json:"token_id" validate:"omitempty"`
 	Limit    int    `json:"row" validate:"min=1,max=100"`
 	Before   *uint  `json:"before" validate:"omitempty,min=0"`
 	After    *uint  `json:"after" validate:"omitempty,min=0"`
@@ -94,13 +104,190 @@ func transferHandle(w http.ResponseWriter, r *http.Request) error {
 		toJson(w, 10001, nil, err)
 		return nil
 	}
-	list, page := svc.GetTransferCursor(r.Context(), address.Decode(p.Address), p.BlockNum, p.Limit, p.Before, p.After)
+	list, page := svc.GetTransferCursor(r.Context(), address.Decode(p.Address), p.BlockNum, p.Category, p.TokenId, p.Limit, p.Before, p.After)
 	toJson(w, 0, map[string]interface{}{
 		"list": list, "pagination": page,
 	}, nil)
 	return nil
 }
 
+type assetsParams struct {
+	Category string `json:"category" validate:"omitempty,oneof=assets foreignassets tokens"`
+	Limit    int    `json:"row" validate:"min=1,max=100"`
+	Before   *uint  `json:"before" validate:"omitempty,min=0"`
+	After    *uint  `json:"after" validate:"omitempty,min=0"`
+}
+
+// @Summary Get registered assets list
+// @Tags assets
+// @Accept json
+// @Produce json
+// @Param params body assetsParams true "params"
+// @Success 200 {object} J{data=object{list=[]model.Asset,pagination=object}}
+// @Router /api/plugin/balance/assets [post]
+func assetsHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(assetsParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	list, page := svc.GetAssetListCursor(r.Context(), p.Category, p.Limit, p.Before, p.After)
+	toJson(w, 0, map[string]interface{}{
+		"list": list, "pagination": page,
+	}, nil)
+	return nil
+}
+
+type assetParams struct {
+	Category string `json:"category" validate:"required,oneof=assets foreignassets tokens"`
+	AssetId  string `json:"asset_id" validate:"required"`
+}
+
+// @Summary Get asset details
+// @Tags assets
+// @Accept json
+// @Produce json
+// @Param params body assetParams true "params"
+// @Success 200 {object} J{data=model.Asset}
+// @Router /api/plugin/balance/asset [post]
+func assetHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(assetParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	toJson(w, 0, svc.GetAssetJson(r.Context(), p.Category, p.AssetId), nil)
+	return nil
+}
+
+type assetHoldersParams struct {
+	Category string  `json:"category" validate:"required,oneof=assets foreignassets tokens"`
+	AssetId  string  `json:"asset_id" validate:"required"`
+	Limit    int     `json:"row" validate:"min=1,max=100"`
+	Before   *string `json:"before" validate:"omitempty,min=0"`
+	After    *string `json:"after" validate:"omitempty,min=0"`
+}
+
+// @Summary Get asset holders
+// @Tags assets
+// @Accept json
+// @Produce json
+// @Param params body assetHoldersParams true "params"
+// @Success 200 {object} J{data=object{list=[]model.AssetHolder,pagination=object}}
+// @Router /api/plugin/balance/asset/holders [post]
+func assetHoldersHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(assetHoldersParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	list, page := svc.GetAssetHoldersCursor(r.Context(), p.Category, p.AssetId, p.Limit, p.Before, p.After)
+	toJson(w, 0, map[string]interface{}{
+		"list": list, "pagination": page,
+	}, nil)
+	return nil
+}
+
+// @Summary Get account assets balance
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param params body accountParams true "params"
+// @Success 200 {object} J{data=[]model.AssetHolder}
+// @Router /api/plugin/balance/account/assets [post]
+func accountAssetsHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(accountParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	toJson(w, 0, svc.GetAccountAssets(r.Context(), address.Decode(p.Address)), nil)
+	return nil
+}
+
+// @Summary Get account locks, holds and freezes
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param params body accountParams true "params"
+// @Success 200 {object} J{data=[]model.AccountLock}
+// @Router /api/plugin/balance/account/locks [post]
+func accountLocksHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(accountParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	toJson(w, 0, svc.GetAccountLocks(r.Context(), address.Decode(p.Address)), nil)
+	return nil
+}
+
+type balanceHistoryParams struct {
+	Address string `json:"address" validate:"required,addr"`
+	Limit   int    `json:"row" validate:"min=1,max=100"`
+	Before  *uint  `json:"before" validate:"omitempty,min=0"`
+	After   *uint  `json:"after" validate:"omitempty,min=0"`
+}
+
+// @Summary Get account balance history
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param params body balanceHistoryParams true "params"
+// @Success 200 {object} J{data=object{list=[]model.BalanceHistory,pagination=object}}
+// @Router /api/plugin/balance/account/balance_history [post]
+func balanceHistoryHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(balanceHistoryParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	list, page := svc.GetBalanceHistoryCursor(r.Context(), address.Decode(p.Address), p.Limit, p.Before, p.After)
+	toJson(w, 0, map[string]interface{}{
+		"list": list, "pagination": page,
+	}, nil)
+	return nil
+}
+
+// @Summary Get account vesting schedules
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param params body accountParams true "params"
+// @Success 200 {object} J{data=model.AccountVesting}
+// @Router /api/plugin/balance/account/vesting [post]
+func accountVestingHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(accountParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	toJson(w, 0, svc.GetAccountVesting(r.Context(), address.Decode(p.Address)), nil)
+	return nil
+}
+
+type vestingUnlocksParams struct {
+	Interval uint `json:"interval" validate:"min=1,max=1000000"`
+	Count    uint `json:"count" validate:"min=1,max=100"`
+}
+
+// @Summary Get upcoming vesting unlocks
+// @Tags vesting
+// @Accept json
+// @Produce json
+// @Param params body vestingUnlocksParams true "params"
+// @Success 200 {object} J{data=[]model.VestingUnlock}
+// @Router /api/plugin/balance/vesting/unlocks [post]
+func vestingUnlocksHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(vestingUnlocksParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	toJson(w, 0, svc.GetVestingUnlocks(r.Context(), p.Interval, p.Count), nil)
+	return nil
+}
+
 type J struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
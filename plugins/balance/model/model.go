@@ -1,6 +1,8 @@
 package model
 
 import (
+	"fmt"
+	"github.com/itering/subscan/util"
 	"github.com/shopspring/decimal"
 )
 
@@ -9,8 +11,12 @@ type Account struct {
 	Address  string          `gorm:"default: null;size:100;index:address,unique;index:balance_address,priority:2" json:"address"`
 	Nonce    int             `json:"nonce"`
 	Balance  decimal.Decimal `json:"balance" gorm:"type:decimal(65,0);index:balance;index:balance_address,priority:1"`
+	Free     decimal.Decimal `json:"free" gorm:"type:decimal(65,0);"`
 	Locked   decimal.Decimal `json:"locked" gorm:"type:decimal(65,0);"`
 	Reserved decimal.Decimal `json:"reserved" gorm:"type:decimal(65,0);"`
+	Frozen   decimal.Decimal `json:"frozen" gorm:"type:decimal(65,0);"`
+
+	ValueUSD *decimal.Decimal `json:"value_usd" gorm:"-"`
 }
 
 func (a *Account) TableName() string {
@@ -21,13 +27,95 @@ type AccountData struct {
 	Nonce    int `json:"nonce"`
 	RefCount int `json:"ref_count"`
 	Data     struct {
-		Free       decimal.Decimal `json:"free"`
-		Reserved   decimal.Decimal `json:"reserved"`
+		Free     decimal.Decimal `json:"free"`
+		Reserved decimal.Decimal `json:"reserved"`
+		Frozen   decimal.Decimal `json:"frozen"`
+		Flags    decimal.Decimal `json:"flags"`
+		// legacy runtime, replaced by frozen
 		MiscFrozen decimal.Decimal `json:"miscFrozen"`
 		FeeFrozen  decimal.Decimal `json:"feeFrozen"`
 	} `json:"data"`
 }
 
+// Total free + reserved
+func (a *AccountData) Total() decimal.Decimal {
+	return a.Data.Free.Add(a.Data.Reserved)
+}
+
+// Frozen frozen balance, compatible with legacy miscFrozen/feeFrozen
+func (a *AccountData) Frozen() decimal.Decimal {
+	if a.Data.Frozen.IsPositive() {
+		return a.Data.Frozen
+	}
+	return decimal.Max(a.Data.MiscFrozen, a.Data.FeeFrozen)
+}
+
+const (
+	LockCategoryLock   = "lock"
+	LockCategoryHold   = "hold"
+	LockCategoryFreeze = "freeze"
+)
+
+// AccountLock Balances.Locks, Balances.Holds and Balances.Freezes of account
+type AccountLock struct {
+	ID       uint            `gorm:"primary_key" json:"-"`
+	Address  string          `json:"address" gorm:"size:100;index:account_lock,unique,priority:1"`
+	Category string          `json:"category" gorm:"size:32;index:account_lock,unique,priority:2"`
+	LockId   string          `json:"lock_id" gorm:"size:255;index:account_lock,unique,priority:3"`
+	Amount   decimal.Decimal `json:"amount" gorm:"type:decimal(65,0);"`
+	Reasons  string          `json:"reasons" gorm:"size:32"`
+}
+
+func (a *AccountLock) TableName() string {
+	return "balance_account_locks"
+}
+
+// LockedAmount balance locked by Balances.Locks, locks overlap so it is the max lock amount
+func LockedAmount(locks []AccountLock) decimal.Decimal {
+	locked := decimal.Zero
+	for _, lock := range locks {
+		if lock.Category == LockCategoryLock {
+			locked = decimal.Max(locked, lock.Amount)
+		}
+	}
+	return locked
+}
+
+// BalanceLock Balances.Locks item
+type BalanceLock struct {
+	Id      string          `json:"id"`
+	Amount  decimal.Decimal `json:"amount"`
+	Reasons string          `json:"reasons"`
+}
+
+// IdAmount Balances.Holds and Balances.Freezes item
+type IdAmount struct {
+	Id     interface{}     `json:"id"`
+	Amount decimal.Decimal `json:"amount"`
+}
+
+// BalanceHistory account balance snapshot after balance changed
+type BalanceHistory struct {
+	ID             uint            `gorm:"primary_key" json:"id"`
+	Address        string          `json:"address" gorm:"size:100;index:address_event,unique,priority:1"`
+	EventId        uint            `json:"-" gorm:"index:address_event,unique,priority:2"`
+	BlockNum       uint            `json:"block_num" gorm:"size:32"`
+	BlockTimestamp int64           `json:"block_timestamp"`
+	EventIndex     string          `json:"event_index" gorm:"size:100"`
+	ExtrinsicIndex string          `json:"extrinsic_index" gorm:"size:100"`
+	ModuleId       string          `json:"module_id" gorm:"size:100"`
+	EventName      string          `json:"event_id" gorm:"size:100"`
+	Free           decimal.Decimal `json:"free" gorm:"type:decimal(65,0);"`
+	Reserved       decimal.Decimal `json:"reserved" gorm:"type:decimal(65,0);"`
+	Frozen         decimal.Decimal `json:"frozen" gorm:"type:decimal(65,0);"`
+	Balance        decimal.Decimal `json:"balance" gorm:"type:decimal(65,0);"`
+	Change         decimal.Decimal `json:"change" gorm:"type:decimal(65,0);"`
+}
+
+func (a *BalanceHistory) TableName() string {
+	return "balance_account_histories"
+}
+
 type Transfer struct {
 	Id             uint            `json:"id" gorm:"primary_key;autoIncrement:false"`
 	BlockNum       uint            `json:"blockNum" gorm:"size:32"`
@@ -36,10 +124,153 @@ type Transfer struct {
 	Amount         decimal.Decimal `json:"amount" gorm:"decimal(65)"`
 	BlockTimestamp int64           `json:"block_timestamp" `
 	Symbol         string          `json:"symbol" gorm:"size:255"`
-	TokenId        string          `json:"token_id" gorm:"size:255"`
+	TokenId        string          `json:"token_id" gorm:"size:255;index:token"`
+	Category       string          `json:"category" gorm:"size:32;default:'native';index:token"`
+	Decimals       int             `json:"decimals"`
 	ExtrinsicIndex string          `json:"extrinsic_index" gorm:"size:255;index:extrinsic_index"`
+
+	ValueUSD *decimal.Decimal `json:"value_usd" gorm:"-"`
 }
 
 func (a *Transfer) TableName() string {
 	return "balance_transfers"
 }
+
+const (
+	AssetCategoryNative        = "native"
+	AssetCategoryAssets        = "assets"
+	AssetCategoryForeignAssets = "foreignassets"
+	AssetCategoryTokens        = "tokens"
+)
+
+// Asset registered by assets/foreignAssets pallet or ORML tokens pallet
+type Asset struct {
+	ID       uint            `gorm:"primary_key" json:"-"`
+	Category string          `json:"category" gorm:"size:32;index:asset,unique,priority:1"`
+	AssetId  string          `json:"asset_id" gorm:"size:255;index:asset,unique,priority:2"`
+	Name     string          `json:"name" gorm:"size:255"`
+	Symbol   string          `json:"symbol" gorm:"size:255"`
+	Decimals int             `json:"decimals"`
+	Owner    string          `json:"owner" gorm:"size:100"`
+	Supply   decimal.Decimal `json:"supply" gorm:"type:decimal(65,0);"`
+	Holders  uint            `json:"holders" gorm:"size:32"`
+}
+
+func (a *Asset) TableName() string {
+	return "balance_assets"
+}
+
+type AssetHolder struct {
+	ID       uint            `gorm:"primary_key" json:"-"`
+	Category string          `json:"category" gorm:"size:32;index:asset_holder,unique,priority:1"`
+	AssetId  string          `json:"asset_id" gorm:"size:255;index:asset_holder,unique,priority:2"`
+	Address  string          `json:"address" gorm:"size:100;index:asset_holder,unique,priority:3;index:holder"`
+	Balance  decimal.Decimal `json:"balance" gorm:"type:decimal(65,0);index:balance"`
+
+	ValueUSD *decimal.Decimal `json:"value_usd" gorm:"-"`
+}
+
+func (a *AssetHolder) TableName() string {
+	return "balance_asset_holders"
+}
+
+func (a AssetHolder) Cursor() string {
+	return util.Base64Encode(fmt.Sprintf("%s_%d", a.Balance.String(), a.ID))
+}
+
+// AssetDetails Assets.Asset storage
+type AssetDetails struct {
+	Owner  string          `json:"owner"`
+	Supply decimal.Decimal `json:"supply"`
+}
+
+// AssetMetadata Assets.Metadata or AssetRegistry.AssetMetadatas storage
+type AssetMetadata struct {
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+// AssetAccount Assets.Account storage
+type AssetAccount struct {
+	Balance decimal.Decimal `json:"balance"`
+}
+
+// TokensAccount ORML Tokens.Accounts storage
+type TokensAccount struct {
+	Free     decimal.Decimal `json:"free"`
+	Reserved decimal.Decimal `json:"reserved"`
+	Frozen   decimal.Decimal `json:"frozen"`
+}
+
+// VestingInfo Vesting.Vesting storage item
+type VestingInfo struct {
+	Locked        decimal.Decimal `json:"locked"`
+	PerBlock      decimal.Decimal `json:"per_block"`
+	StartingBlock uint            `json:"starting_block"`
+	// legacy runtime
+	PerBlockLegacy      decimal.Decimal `json:"perBlock"`
+	StartingBlockLegacy uint            `json:"startingBlock"`
+}
+
+func (v *VestingInfo) AsSchedule(accountId string, idx int, blockNum uint) *VestingSchedule {
+	schedule := VestingSchedule{
+		Address:       accountId,
+		Idx:           idx,
+		Locked:        v.Locked,
+		PerBlock:      v.PerBlock,
+		StartingBlock: v.StartingBlock,
+		BlockNum:      blockNum,
+	}
+	if schedule.PerBlock.IsZero() && schedule.StartingBlock == 0 {
+		schedule.PerBlock = v.PerBlockLegacy
+		schedule.StartingBlock = v.StartingBlockLegacy
+	}
+	schedule.EndBlock = schedule.StartingBlock
+	if schedule.PerBlock.IsPositive() {
+		schedule.EndBlock += uint(schedule.Locked.Div(schedule.PerBlock).Ceil().IntPart())
+	}
+	return &schedule
+}
+
+// VestingSchedule account vesting schedule, index is the position in Vesting.Vesting
+type VestingSchedule struct {
+	ID            uint            `gorm:"primary_key" json:"-"`
+	Address       string          `json:"address" gorm:"size:100;index:address_idx,unique,priority:1"`
+	Idx           int             `json:"idx" gorm:"index:address_idx,unique,priority:2"`
+	Locked        decimal.Decimal `json:"locked" gorm:"type:decimal(65,0);"`
+	PerBlock      decimal.Decimal `json:"per_block" gorm:"type:decimal(65,0);"`
+	StartingBlock uint            `json:"starting_block"`
+	EndBlock      uint            `json:"end_block" gorm:"index:end_block"`
+	BlockNum      uint            `json:"block_num"`
+}
+
+func (v *VestingSchedule) TableName() string {
+	return "balance_vesting_schedules"
+}
+
+// Vested amount already unlocked at block
+func (v *VestingSchedule) Vested(blockNum uint) decimal.Decimal {
+	if blockNum <= v.StartingBlock {
+		return decimal.Zero
+	}
+	return decimal.Min(v.Locked, v.PerBlock.Mul(decimal.NewFromInt(int64(blockNum-v.StartingBlock))))
+}
+
+// AccountVesting vesting schedules of account at block
+type AccountVesting struct {
+	Address   string            `json:"address"`
+	BlockNum  uint              `json:"block_num"`
+	Locked    decimal.Decimal   `json:"locked"`
+	Vested    decimal.Decimal   `json:"vested"`
+	Unvested  decimal.Decimal   `json:"unvested"`
+	Schedules []VestingSchedule `json:"schedules"`
+}
+
+// VestingUnlock vesting amount will be unlocked between blocks
+type VestingUnlock struct {
+	StartBlock uint            `json:"start_block"`
+	EndBlock   uint            `json:"end_block"`
+	Amount     decimal.Decimal `json:"amount"`
+	Accounts   int             `json:"accounts"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountData_Frozen(t *testing.T) {
+	var data AccountData
+	data.Data.Frozen = decimal.NewFromInt(5)
+	assert.Equal(t, "5", data.Frozen().String())
+
+	// legacy runtime
+	var legacy AccountData
+	legacy.Data.MiscFrozen = decimal.NewFromInt(3)
+	legacy.Data.FeeFrozen = decimal.NewFromInt(7)
+	assert.Equal(t, "7", legacy.Frozen().String())
+}
+
+func TestLockedAmount(t *testing.T) {
+	assert.True(t, LockedAmount(nil).IsZero())
+	locks := []AccountLock{
+		{Category: LockCategoryLock, LockId: "staking", Amount: decimal.NewFromInt(100)},
+		{Category: LockCategoryLock, LockId: "democrac", Amount: decimal.NewFromInt(40)},
+		{Category: LockCategoryHold, LockId: "Preimage.Preimage", Amount: decimal.NewFromInt(500)},
+		{Category: LockCategoryFreeze, LockId: "NominationPools.PoolMinBalance", Amount: decimal.NewFromInt(300)},
+	}
+	assert.Equal(t, "100", LockedAmount(locks).String())
+}
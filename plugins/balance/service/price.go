@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+
+	"github.com/itering/subscan/plugins/balance/dao"
+	"github.com/itering/subscan/plugins/balance/model"
+	"github.com/itering/subscan/share/price"
+	"github.com/itering/subscan/share/token"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+func (s *Service) priceBook() *price.Book {
+	return price.NewBook(s.d.GetDbInstance().(*gorm.DB))
+}
+
+// nativeValueUSD value of native token balance at latest price
+func nativeValueUSD(ctx context.Context, book *price.Book, balance decimal.Decimal) *decimal.Decimal {
+	t := token.GetDefaultToken()
+	if t == nil {
+		return nil
+	}
+	return price.ValueUSD(balance, t.Decimals, book.Latest(ctx, price.NativeKey))
+}
+
+// fillAssetHoldersValue value of asset holders balance at latest price
+func (s *Service) fillAssetHoldersValue(ctx context.Context, list []model.AssetHolder) {
+	book := s.priceBook()
+	assets := make(map[string]*model.Asset)
+	for i := range list {
+		key := list[i].Category + "/" + list[i].AssetId
+		asset, ok := assets[key]
+		if !ok {
+			asset = dao.GetAsset(ctx, s.d, list[i].Category, list[i].AssetId)
+			assets[key] = asset
+		}
+		if asset == nil {
+			continue
+		}
+		list[i].ValueUSD = price.ValueUSD(list[i].Balance, asset.Decimals, book.Latest(ctx, price.AssetKey(list[i].Category, list[i].AssetId)))
+	}
+}
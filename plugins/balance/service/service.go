@@ -7,6 +7,7 @@ import (
 	cmodel "github.com/itering/subscan/model"
 	"github.com/itering/subscan/plugins/balance/dao"
 	"github.com/itering/subscan/plugins/balance/model"
+	"github.com/itering/subscan/share/price"
 	"github.com/itering/subscan/util/address"
 )
 
@@ -15,10 +16,12 @@ type Service struct {
 	pool subscan_plugin.RedisPool
 }
 
-func (s *Service) GetAccountListCursor(_ context.Context, limit int, before, after *uint) ([]model.Account, map[string]interface{}) {
+func (s *Service) GetAccountListCursor(ctx context.Context, limit int, before, after *uint) ([]model.Account, map[string]interface{}) {
 	list, hasPrev, hasNext := dao.GetAccountListCursor(s.d, limit, before, after)
+	book := s.priceBook()
 	for i := range list {
 		list[i].Address = address.Encode(list[i].Address)
+		list[i].ValueUSD = nativeValueUSD(ctx, book, list[i].Balance)
 	}
 	var start, end *uint
 	if len(list) > 0 {
@@ -39,21 +42,31 @@ func (s *Service) GetAccountJson(ctx context.Context, addr string) *model.Accoun
 		return nil
 	}
 	account.Address = address.Encode(account.Address)
+	account.ValueUSD = nativeValueUSD(ctx, s.priceBook(), account.Balance)
 	return account
 }
 
-func (s *Service) GetTransferCursor(ctx context.Context, addr string, blockNum uint, limit int, before, after *uint) ([]model.Transfer, map[string]interface{}) {
+func (s *Service) GetTransferCursor(ctx context.Context, addr string, blockNum uint, category, tokenId string, limit int, before, after *uint) ([]model.Transfer, map[string]interface{}) {
 	var opts []cmodel.Option
 	if blockNum > 0 {
 		opts = append(opts, cmodel.Where("block_num = ?", blockNum))
 	}
+	if category != "" {
+		opts = append(opts, cmodel.Where("category = ?", category))
+	}
+	if tokenId != "" {
+		opts = append(opts, cmodel.Where("token_id = ?", tokenId))
+	}
 	if addr != "" {
 		opts = append(opts, cmodel.Where("sender = ? or receiver = ?", addr, addr))
 	}
 	list, hasPrev, hasNext := dao.TransfersCursor(ctx, s.d, limit, before, after, opts...)
+	book := s.priceBook()
 	for index := range list {
 		list[index].Sender = address.Encode(list[index].Sender)
 		list[index].Receiver = address.Encode(list[index].Receiver)
+		// value at the day of transfer
+		list[index].ValueUSD = price.ValueUSD(list[index].Amount, list[index].Decimals, book.At(ctx, price.AssetKey(list[index].Category, list[index].TokenId), list[index].BlockTimestamp))
 	}
 	var start, end *uint
 	if len(list) > 0 {
@@ -68,6 +81,141 @@ func (s *Service) GetTransferCursor(ctx context.Context, addr string, blockNum u
 	}
 }
 
+func (s *Service) GetAssetListCursor(_ context.Context, category string, limit int, before, after *uint) ([]model.Asset, map[string]interface{}) {
+	list, hasPrev, hasNext := dao.GetAssetListCursor(s.d, category, limit, before, after)
+	for i := range list {
+		if list[i].Owner != "" {
+			list[i].Owner = address.Encode(list[i].Owner)
+		}
+	}
+	var start, end *uint
+	if len(list) > 0 {
+		start = &list[0].ID
+		end = &list[len(list)-1].ID
+	}
+	return list, map[string]interface{}{
+		"start_cursor":      start,
+		"end_cursor":        end,
+		"has_previous_page": hasPrev,
+		"has_next_page":     hasNext,
+	}
+}
+
+func (s *Service) GetAssetJson(ctx context.Context, category, assetId string) *model.Asset {
+	asset := dao.GetAsset(ctx, s.d, category, assetId)
+	if asset == nil {
+		return nil
+	}
+	if asset.Owner != "" {
+		asset.Owner = address.Encode(asset.Owner)
+	}
+	return asset
+}
+
+func (s *Service) GetAssetHoldersCursor(ctx context.Context, category, assetId string, limit int, before, after *string) ([]model.AssetHolder, map[string]interface{}) {
+	list, hasPrev, hasNext := dao.GetAssetHoldersCursor(ctx, s.d, category, assetId, limit, before, after)
+	for i := range list {
+		list[i].Address = address.Encode(list[i].Address)
+	}
+	s.fillAssetHoldersValue(ctx, list)
+	var start, end *string
+	if len(list) > 0 {
+		s := list[0].Cursor()
+		e := list[len(list)-1].Cursor()
+		start = &s
+		end = &e
+	}
+	return list, map[string]interface{}{
+		"start_cursor":      start,
+		"end_cursor":        end,
+		"has_previous_page": hasPrev,
+		"has_next_page":     hasNext,
+	}
+}
+
+func (s *Service) GetAccountAssets(ctx context.Context, addr string) []model.AssetHolder {
+	list := dao.GetAccountAssets(ctx, s.d, addr)
+	for i := range list {
+		list[i].Address = address.Encode(list[i].Address)
+	}
+	s.fillAssetHoldersValue(ctx, list)
+	return list
+}
+
+func (s *Service) GetAccountLocks(ctx context.Context, addr string) []model.AccountLock {
+	list := dao.GetAccountLocks(ctx, s.d, addr)
+	for i := range list {
+		list[i].Address = address.Encode(list[i].Address)
+	}
+	return list
+}
+
+func (s *Service) GetBalanceHistoryCursor(ctx context.Context, addr string, limit int, before, after *uint) ([]model.BalanceHistory, map[string]interface{}) {
+	list, hasPrev, hasNext := dao.BalanceHistoryCursor(ctx, s.d, addr, limit, before, after)
+	for i := range list {
+		list[i].Address = address.Encode(list[i].Address)
+	}
+	var start, end *uint
+	if len(list) > 0 {
+		start = &list[0].ID
+		end = &list[len(list)-1].ID
+	}
+	return list, map[string]interface{}{
+		"start_cursor":      start,
+		"end_cursor":        end,
+		"has_previous_page": hasPrev,
+		"has_next_page":     hasNext,
+	}
+}
+
+// GetAccountVesting vesting schedules with vested/unvested amount at latest finalized block
+func (s *Service) GetAccountVesting(ctx context.Context, addr string) *model.AccountVesting {
+	blockNum, _ := s.d.GetCurrentBlockNum(ctx)
+	vesting := model.AccountVesting{
+		Address:   address.Encode(addr),
+		BlockNum:  uint(blockNum),
+		Schedules: dao.GetAccountVesting(ctx, s.d, addr),
+	}
+	for i, schedule := range vesting.Schedules {
+		vesting.Schedules[i].Address = vesting.Address
+		vesting.Locked = vesting.Locked.Add(schedule.Locked)
+		vesting.Vested = vesting.Vested.Add(schedule.Vested(vesting.BlockNum))
+	}
+	vesting.Unvested = vesting.Locked.Sub(vesting.Vested)
+	return &vesting
+}
+
+// GetVestingUnlocks chain-wide vesting amount will be unlocked in next count * interval blocks
+func (s *Service) GetVestingUnlocks(ctx context.Context, interval, count uint) []model.VestingUnlock {
+	blockNum, _ := s.d.GetCurrentBlockNum(ctx)
+	current := uint(blockNum)
+	unlocks := make([]model.VestingUnlock, count)
+	accounts := make([]map[string]struct{}, count)
+	for i := range unlocks {
+		unlocks[i].StartBlock = current + uint(i)*interval
+		unlocks[i].EndBlock = unlocks[i].StartBlock + interval
+		accounts[i] = make(map[string]struct{})
+	}
+	dao.GetUnfinishedVesting(ctx, s.d, current, func(list []model.VestingSchedule) {
+		for _, schedule := range list {
+			for i := range unlocks {
+				if schedule.EndBlock <= unlocks[i].StartBlock {
+					break
+				}
+				amount := schedule.Vested(unlocks[i].EndBlock).Sub(schedule.Vested(unlocks[i].StartBlock))
+				if amount.IsPositive() {
+					unlocks[i].Amount = unlocks[i].Amount.Add(amount)
+					accounts[i][schedule.Address] = struct{}{}
+				}
+			}
+		}
+	})
+	for i := range unlocks {
+		unlocks[i].Accounts = len(accounts[i])
+	}
+	return unlocks
+}
+
 func New(d storage.Dao, pool subscan_plugin.RedisPool) *Service {
 	return &Service{
 		d:    d,
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+
+	"github.com/itering/subscan/plugins/balance/dao"
+	"github.com/itering/subscan/plugins/balance/model"
+	"github.com/itering/subscan/share/token"
+	"github.com/itering/subscan/util/address"
+)
+
+const maxTokenHolders = 100
+
+// Tokens registered assets of assets, foreignAssets and tokens pallets, implement token.Registry
+func (s *Service) Tokens(ctx context.Context) []token.Token {
+	assets := dao.GetAssets(ctx, s.d)
+	list := make([]token.Token, 0, len(assets))
+	for _, asset := range assets {
+		list = append(list, assetToken(asset))
+	}
+	return list
+}
+
+// Holders top holders of native token or registered asset, implement token.Registry
+func (s *Service) Holders(ctx context.Context, category, tokenId string, limit int) []token.Holder {
+	if limit <= 0 || limit > maxTokenHolders {
+		limit = maxTokenHolders
+	}
+	var holders []token.Holder
+	if category == model.AssetCategoryNative {
+		for _, account := range dao.GetTopAccounts(ctx, s.d, limit) {
+			holders = append(holders, token.Holder{Address: address.Encode(account.Address), Balance: account.Balance})
+		}
+		return holders
+	}
+	list, _, _ := dao.GetAssetHoldersCursor(ctx, s.d, category, tokenId, limit, nil, nil)
+	for _, holder := range list {
+		holders = append(holders, token.Holder{Address: address.Encode(holder.Address), Balance: holder.Balance})
+	}
+	return holders
+}
+
+func assetToken(asset model.Asset) token.Token {
+	return token.Token{
+		TokenId:  asset.AssetId,
+		Category: asset.Category,
+		Symbol:   asset.Symbol,
+		Decimals: asset.Decimals,
+		Holders:  asset.Holders,
+	}
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/itering/subscan/plugins/balance/model"
+	"github.com/itering/subscan/share/token"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_assetToken(t *testing.T) {
+	asset := model.Asset{
+		Category: model.AssetCategoryForeignAssets,
+		AssetId:  `{"parents":1,"interior":"Here"}`,
+		Name:     "Kusama",
+		Symbol:   "KSM",
+		Decimals: 12,
+		Supply:   decimal.New(1, 12),
+		Holders:  3,
+	}
+	assert.Equal(t, token.Token{
+		TokenId:  asset.AssetId,
+		Category: model.AssetCategoryForeignAssets,
+		Symbol:   "KSM",
+		Decimals: 12,
+		Holders:  3,
+	}, assetToken(asset))
+}
@@ -0,0 +1,73 @@
+package contract
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/itering/subscan/util"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+var (
+	// compileConcurrency max compiler processes run at the same time
+	compileConcurrency = util.StringToInt(util.GetEnv("SOLC_MAX_CONCURRENT", fmt.Sprint(runtime.NumCPU())))
+	compileSemaphore   = make(chan struct{}, max(compileConcurrency, 1))
+)
+
+// acquireCompile wait for a compile slot, release must be called after compiler exit
+func acquireCompile(ctx context.Context) (release func(), err error) {
+	select {
+	case compileSemaphore <- struct{}{}:
+		return func() { <-compileSemaphore }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// checkSha256 expected is hex sha256 digest, with or without 0x prefix
+func checkSha256(data []byte, expected string) error {
+	expected = strings.ToLower(util.TrimHex(expected))
+	if expected == "" {
+		return fmt.Errorf("missing sha256 checksum")
+	}
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return fmt.Errorf("sha256 mismatch, expected %s, got %s", expected, actual)
+	}
+	return nil
+}
+
+// downloadVerifiedBinary download compiler binary, it is executable only after checksum matched.
+// no lock is held while downloading, concurrent downloads of same binary are renamed atomically
+func downloadVerifiedBinary(ctx context.Context, url, checksum, bin string) error {
+	data, err := util.HttpGet(ctx, url)
+	if err != nil {
+		return fmt.Errorf("download %s failed: %w", url, err)
+	}
+	if err = checkSha256(data, checksum); err != nil {
+		return fmt.Errorf("verify %s failed: %w", url, err)
+	}
+	if err = os.MkdirAll(filepath.Dir(bin), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(bin), filepath.Base(bin)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), 0755); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), bin)
+}
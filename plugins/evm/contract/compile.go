@@ -129,9 +129,11 @@ type VerificationRes struct {
 	CreationBytecodeLength int           `json:"creation_bytecode_length"`
 	ReviveVersion          string        `json:"revive_version,omitempty"`
 	ContractName           string        `json:"contract_name,omitempty"`
+	ConstructorArguments   string        `json:"constructor_arguments,omitempty"`
 }
 
-func (metadataValue *CompilerJSONInput) VerifyFromJsonInput(_ context.Context, address string) (*VerificationRes, error) {
+// VerifyFromJsonInput verify by VERIFY_SERVER if set, otherwise compile with local solc
+func (metadataValue *CompilerJSONInput) VerifyFromJsonInput(ctx context.Context, address, creationCode string) (*VerificationRes, error) {
 	if verifyServer != "" {
 		type Input struct {
 			Address         string `json:"address"`
@@ -158,5 +160,5 @@ func (metadataValue *CompilerJSONInput) VerifyFromJsonInput(_ context.Context, a
 		}
 		return &vr, nil
 	}
-	return nil, errors.New("verify server disabled")
+	return metadataValue.VerifyLocal(ctx, address, creationCode)
 }
@@ -0,0 +1,115 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/itering/subscan/util"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+var (
+	// resolcDir local resolc binaries directory, binary file named as resolc version, like v0.3.0
+	resolcDir = os.Getenv("RESOLC_DIR")
+	// resolcCacheDir downloaded resolc binaries cache directory
+	resolcCacheDir   = util.GetEnv("RESOLC_CACHE_DIR", filepath.Join(os.TempDir(), "resolc"))
+	resolcBinaryHost = util.GetEnv("RESOLC_BINARY_HOST", "https://github.com/paritytech/revive/releases/download")
+	// resolcReleaseApi github release api, sha256 digest of release assets is read from it
+	resolcReleaseApi = util.GetEnv("RESOLC_RELEASE_API", "https://api.github.com/repos/paritytech/revive/releases/tags")
+)
+
+// PvmBlobMagic PolkaVM program blob starts with "PVM\0"
+const PvmBlobMagic = "50564d00"
+
+// IsPvmBlob code is PolkaVM program blob
+func IsPvmBlob(code string) bool {
+	return strings.HasPrefix(strings.ToLower(util.TrimHex(code)), PvmBlobMagic)
+}
+
+// resolcAsset release asset of platform, apple darwin asset is universal binary
+func resolcAsset() (string, error) {
+	switch runtime.GOOS + "/" + runtime.GOARCH {
+	case "linux/amd64":
+		return "resolc-x86_64-unknown-linux-musl", nil
+	case "darwin/amd64", "darwin/arm64":
+		return "resolc-universal-apple-darwin", nil
+	case "windows/amd64":
+		return "resolc-x86_64-pc-windows-msvc.exe", nil
+	}
+	return "", fmt.Errorf("no resolc release for %s/%s", runtime.GOOS, runtime.GOARCH)
+}
+
+type resolcReleaseAsset struct {
+	Name   string `json:"name"`
+	Digest string `json:"digest"`
+}
+
+// resolcChecksum sha256 digest of release asset published by github, like sha256:<hex>
+func resolcChecksum(ctx context.Context, version, asset string) (string, error) {
+	data, err := util.HttpGet(ctx, fmt.Sprintf("%s/%s", resolcReleaseApi, version))
+	if err != nil {
+		return "", fmt.Errorf("fetch resolc release %s failed: %w", version, err)
+	}
+	var release struct {
+		Assets []resolcReleaseAsset `json:"assets"`
+	}
+	if err = json.Unmarshal(data, &release); err != nil {
+		return "", err
+	}
+	return findResolcDigest(release.Assets, asset)
+}
+
+func findResolcDigest(assets []resolcReleaseAsset, asset string) (string, error) {
+	for _, a := range assets {
+		if a.Name == asset && strings.HasPrefix(a.Digest, "sha256:") {
+			return strings.TrimPrefix(a.Digest, "sha256:"), nil
+		}
+	}
+	return "", fmt.Errorf("sha256 digest of resolc asset %s not found", asset)
+}
+
+// resolcBinary find resolc binary from RESOLC_DIR, or download release to cache directory,
+// downloaded release is executable only if sha256 matched release digest
+func resolcBinary(ctx context.Context, version string) (string, error) {
+	if resolcDir != "" {
+		for _, name := range []string{version, "resolc-" + version} {
+			if bin := filepath.Join(resolcDir, name); isExecutable(bin) {
+				return bin, nil
+			}
+		}
+	}
+	if !util.StringInSlice(version, ReviveVersion) {
+		return "", fmt.Errorf("unsupported resolc version %s", version)
+	}
+	bin := filepath.Join(resolcCacheDir, fmt.Sprintf("resolc-%s", version))
+	if isExecutable(bin) {
+		return bin, nil
+	}
+	asset, err := resolcAsset()
+	if err != nil {
+		return "", err
+	}
+	checksum, err := resolcChecksum(ctx, version, asset)
+	if err != nil {
+		return "", err
+	}
+	if err = downloadVerifiedBinary(ctx, fmt.Sprintf("%s/%s/%s", resolcBinaryHost, version, asset), checksum, bin); err != nil {
+		return "", err
+	}
+	return bin, nil
+}
+
+// matchPvmBlob resolc emit the whole PolkaVM blob as bytecode, it is the code uploaded on chain
+func matchPvmBlob(compiled *solcBytecode, deployed string) string {
+	onChain := strings.ToLower(util.TrimHex(deployed))
+	if !IsPvmBlob(onChain) {
+		return ""
+	}
+	if strings.ToLower(util.TrimHex(compiled.Object)) == onChain {
+		return VerifiedPerfect
+	}
+	return ""
+}
@@ -0,0 +1,419 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/itering/subscan/share/web3"
+	"github.com/itering/subscan/util"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// solcDir local solc binaries directory, binary file named as compiler version, like v0.8.26+commit.8a97fa7a
+	solcDir = os.Getenv("SOLC_DIR")
+	// solcCacheDir downloaded solc binaries cache directory
+	solcCacheDir   = util.GetEnv("SOLC_CACHE_DIR", filepath.Join(os.TempDir(), "solc"))
+	solcBinaryHost = util.GetEnv("SOLC_BINARY_HOST", "https://binaries.soliditylang.org")
+	solcLock       sync.Mutex
+	solcBuildLists = make(map[string][]solcBuild)
+)
+
+const (
+	VerifiedPerfect = "perfect"
+	VerifiedPartial = "partial"
+
+	compileTimeout = 5 * time.Minute
+)
+
+var ErrBytecodeMismatch = errors.New("the deployed and recompiled bytecode don't match")
+
+// NormalizeSolcVersion soljson-v0.8.26+commit.8a97fa7a.js, 0.8.26+commit.8a97fa7a => v0.8.26+commit.8a97fa7a
+func NormalizeSolcVersion(version string) string {
+	version = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(version), "soljson-"), ".js")
+	if version != "" && !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return version
+}
+
+// solcPlatform native build platform of binaries.soliditylang.org, macosx build is universal binary
+func solcPlatform() (string, error) {
+	switch runtime.GOOS + "/" + runtime.GOARCH {
+	case "linux/amd64":
+		return "linux-amd64", nil
+	case "linux/arm64":
+		return "linux-arm64", nil
+	case "darwin/amd64", "darwin/arm64":
+		return "macosx-amd64", nil
+	case "windows/amd64":
+		return "windows-amd64", nil
+	}
+	return "", fmt.Errorf("no native solc build for %s/%s", runtime.GOOS, runtime.GOARCH)
+}
+
+type solcBuild struct {
+	Path        string `json:"path"`
+	LongVersion string `json:"longVersion"`
+	Sha256      string `json:"sha256"`
+}
+
+// solcBuilds native builds published in list.json of platform, cached after first fetch
+func solcBuilds(ctx context.Context, platform string) ([]solcBuild, error) {
+	solcLock.Lock()
+	builds, ok := solcBuildLists[platform]
+	solcLock.Unlock()
+	if ok {
+		return builds, nil
+	}
+	data, err := util.HttpGet(ctx, fmt.Sprintf("%s/%s/list.json", solcBinaryHost, platform))
+	if err != nil {
+		return nil, fmt.Errorf("fetch solc build list failed: %w", err)
+	}
+	var list struct {
+		Builds []solcBuild `json:"builds"`
+	}
+	if err = json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	solcLock.Lock()
+	solcBuildLists[platform] = list.Builds
+	solcLock.Unlock()
+	return list.Builds, nil
+}
+
+// findSolcBuild nightly builds only release soljson, they are not in native build list
+func findSolcBuild(builds []solcBuild, version string) (*solcBuild, error) {
+	longVersion := strings.TrimPrefix(version, "v")
+	for index := range builds {
+		if builds[index].LongVersion == longVersion {
+			return &builds[index], nil
+		}
+	}
+	if strings.Contains(longVersion, "nightly") {
+		return nil, fmt.Errorf("nightly compiler %s has no native build, use a release version", version)
+	}
+	return nil, fmt.Errorf("compiler version %s has no native build", version)
+}
+
+// solcBinary find solc binary from SOLC_DIR, or download selected build to cache directory,
+// downloaded build is executable only if sha256 matched list.json
+func solcBinary(ctx context.Context, version string) (string, error) {
+	if solcDir != "" {
+		for _, name := range []string{version, "solc-" + version} {
+			if bin := filepath.Join(solcDir, name); isExecutable(bin) {
+				return bin, nil
+			}
+		}
+	}
+	if !util.StringInSlice(fmt.Sprintf("soljson-%s.js", version), soljsonSources) {
+		return "", fmt.Errorf("unsupported compiler version %s", version)
+	}
+	platform, err := solcPlatform()
+	if err != nil {
+		return "", err
+	}
+	bin := filepath.Join(solcCacheDir, fmt.Sprintf("solc-%s-%s", platform, version))
+	if isExecutable(bin) {
+		return bin, nil
+	}
+	builds, err := solcBuilds(ctx, platform)
+	if err != nil {
+		return "", err
+	}
+	build, err := findSolcBuild(builds, version)
+	if err != nil {
+		return "", err
+	}
+	if err = downloadVerifiedBinary(ctx, fmt.Sprintf("%s/%s/%s", solcBinaryHost, platform, build.Path), build.Sha256, bin); err != nil {
+		return "", err
+	}
+	return bin, nil
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir() && info.Mode()&0111 != 0
+}
+
+type solcStandardInput struct {
+	Language string                        `json:"language"`
+	Sources  map[string]solcStandardSource `json:"sources"`
+	Settings solcStandardSettings          `json:"settings"`
+}
+
+type solcStandardSource struct {
+	Content string `json:"content"`
+}
+
+type solcStandardSettings struct {
+	Remappings []string `json:"remappings,omitempty"`
+	Optimizer  struct {
+		Enabled bool `json:"enabled"`
+		Runs    int  `json:"runs"`
+	} `json:"optimizer"`
+	EvmVersion      string                       `json:"evmVersion,omitempty"`
+	Libraries       map[string]map[string]string `json:"libraries,omitempty"`
+	Metadata        map[string]interface{}       `json:"metadata,omitempty"`
+	OutputSelection interface{}                  `json:"outputSelection"`
+}
+
+type solcLinkReferences map[string]map[string][]solcReference
+
+type solcReference struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+type solcBytecode struct {
+	Object              string                     `json:"object"`
+	LinkReferences      solcLinkReferences         `json:"linkReferences"`
+	ImmutableReferences map[string][]solcReference `json:"immutableReferences"`
+}
+
+type solcCompiledContract struct {
+	Abi []interface{} `json:"abi"`
+	Evm struct {
+		Bytecode         solcBytecode `json:"bytecode"`
+		DeployedBytecode solcBytecode `json:"deployedBytecode"`
+	} `json:"evm"`
+}
+
+type solcStandardOutput struct {
+	Errors []struct {
+		Severity         string `json:"severity"`
+		FormattedMessage string `json:"formattedMessage"`
+		Message          string `json:"message"`
+	} `json:"errors"`
+	Contracts map[string]map[string]solcCompiledContract `json:"contracts"`
+}
+
+// standardInput convert to solc standard json input, compilationTarget and revive settings are not accepted by solc
+func (metadataValue *CompilerJSONInput) standardInput() *solcStandardInput {
+	input := solcStandardInput{
+		Language: util.IfEmptyElse(metadataValue.Language, "Solidity"),
+		Sources:  make(map[string]solcStandardSource),
+	}
+	for path, source := range metadataValue.Sources {
+		input.Sources[path] = solcStandardSource{Content: source.Content}
+	}
+	settings := metadataValue.Settings
+	input.Settings.Remappings = settings.Remappings
+	input.Settings.Optimizer = settings.Optimizer
+	input.Settings.EvmVersion = settings.EvmVersion
+	input.Settings.Metadata = settings.Metadata
+	input.Settings.OutputSelection = map[string]interface{}{
+		"*": map[string][]string{"*": {"abi", "evm.bytecode", "evm.deployedBytecode"}},
+	}
+	if len(settings.Libraries) > 0 {
+		input.Settings.Libraries = make(map[string]map[string]string)
+		for key, value := range settings.Libraries {
+			switch v := value.(type) {
+			// {"path": {"name": "address"}}
+			case map[string]interface{}:
+				libraries := make(map[string]string)
+				for name, address := range v {
+					libraries[name] = util.AddHex(util.TrimHex(fmt.Sprint(address)))
+				}
+				input.Settings.Libraries[key] = libraries
+			// {"name": "address"}, library declared in any source file
+			case string:
+				for path := range input.Sources {
+					if input.Settings.Libraries[path] == nil {
+						input.Settings.Libraries[path] = make(map[string]string)
+					}
+					input.Settings.Libraries[path][key] = util.AddHex(util.TrimHex(v))
+				}
+			}
+		}
+	}
+	return &input
+}
+
+// resolcVersion PolkaVM contract compiled by resolc, version set by verify request or compile settings
+func (metadataValue *CompilerJSONInput) resolcVersion() string {
+	return util.IfEmptyElse(metadataValue.ResolcVersion, metadataValue.Settings.ReviveVersion)
+}
+
+// compile run solc --standard-json, or resolc --standard-json with solc as frontend
+func (metadataValue *CompilerJSONInput) compile(ctx context.Context) (*solcStandardOutput, error) {
+	release, err := acquireCompile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	version := NormalizeSolcVersion(metadataValue.Compiler.Version)
+	bin, err := solcBinary(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"--standard-json"}
+	if resolcVersion := metadataValue.resolcVersion(); resolcVersion != "" {
+		args = append(args, "--solc", bin)
+		if bin, err = resolcBinary(ctx, resolcVersion); err != nil {
+			return nil, err
+		}
+		version = fmt.Sprintf("%s(resolc %s)", version, resolcVersion)
+	}
+	ctx, cancel := context.WithTimeout(ctx, compileTimeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Stdin = bytes.NewReader(util.ToBytes(metadataValue.standardInput()))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		return nil, fmt.Errorf("solc %s failed: %v %s", version, err, stderr.String())
+	}
+	var output solcStandardOutput
+	if err = json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, err
+	}
+	var compileErrors []string
+	for _, e := range output.Errors {
+		if e.Severity == "error" {
+			compileErrors = append(compileErrors, util.IfEmptyElse(e.FormattedMessage, e.Message))
+		}
+	}
+	if len(compileErrors) > 0 {
+		return nil, errors.New(strings.Join(compileErrors, "\n"))
+	}
+	return &output, nil
+}
+
+// targetContract find compilationTarget contract in solc output
+func (metadataValue *CompilerJSONInput) targetContract(output *solcStandardOutput) (string, *solcCompiledContract) {
+	for path, name := range metadataValue.Settings.CompilationTarget {
+		if compiled, ok := output.Contracts[path][name]; ok {
+			return name, &compiled
+		}
+		for _, contracts := range output.Contracts {
+			if compiled, ok := contracts[name]; ok {
+				return name, &compiled
+			}
+		}
+	}
+	return "", nil
+}
+
+// VerifyLocal compile with solc and compare runtime bytecode with on-chain code,
+// PolkaVM contract compare the resolc blob with on-chain code
+func (metadataValue *CompilerJSONInput) VerifyLocal(ctx context.Context, address, creationCode string) (*VerificationRes, error) {
+	deployed, err := web3.RPC.Eth.GetCode(ctx, address, "latest")
+	if err != nil {
+		return nil, err
+	}
+	if len(util.TrimHex(deployed)) == 0 {
+		return nil, fmt.Errorf("unable to locate contract code at %s", address)
+	}
+	output, err := metadataValue.compile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name, compiled := metadataValue.targetContract(output)
+	if compiled == nil {
+		return nil, errors.New("compilation target contract not found in compiler output")
+	}
+	var status string
+	if metadataValue.resolcVersion() != "" {
+		status = matchPvmBlob(&compiled.Evm.Bytecode, deployed)
+	} else {
+		status = matchRuntimeBytecode(&compiled.Evm.DeployedBytecode, deployed)
+	}
+	if status == "" {
+		return nil, ErrBytecodeMismatch
+	}
+	res := VerificationRes{VerifiedStatus: status, Abi: compiled.Abi, ContractName: name, ReviveVersion: metadataValue.resolcVersion()}
+	if length, args, ok := matchCreationBytecode(&compiled.Evm.Bytecode, creationCode); ok {
+		res.CreationBytecodeLength = length
+		res.ConstructorArguments = args
+	}
+	return &res, nil
+}
+
+// matchRuntimeBytecode return perfect if bytecode is same include metadata hash, partial if only metadata hash is different
+func matchRuntimeBytecode(compiled *solcBytecode, deployed string) string {
+	onChain := strings.ToLower(util.TrimHex(deployed))
+	recompiled := linkBytecode(compiled, onChain)
+	// library runtime start with PUSH20 address of itself
+	if zeroAddress := "73" + strings.Repeat("0", 40); strings.HasPrefix(recompiled, zeroAddress) && len(onChain) >= len(zeroAddress) {
+		recompiled = onChain[:len(zeroAddress)] + recompiled[len(zeroAddress):]
+	}
+	// immutable values are zero in compiled runtime
+	for _, references := range compiled.ImmutableReferences {
+		for _, reference := range references {
+			start, end := reference.Start*2, (reference.Start+reference.Length)*2
+			if end <= len(onChain) {
+				onChain = onChain[:start] + strings.Repeat("0", end-start) + onChain[end:]
+			}
+		}
+	}
+	if recompiled == onChain {
+		return VerifiedPerfect
+	}
+	if trimmed := TrimBytecodeMetadata(recompiled); trimmed != "" && trimmed == TrimBytecodeMetadata(onChain) {
+		return VerifiedPartial
+	}
+	return ""
+}
+
+// matchCreationBytecode return creation bytecode hex length(with 0x prefix) and constructor arguments
+func matchCreationBytecode(compiled *solcBytecode, creationCode string) (int, string, bool) {
+	creation := strings.ToLower(util.TrimHex(creationCode))
+	recompiled := linkBytecode(compiled, creation)
+	if len(recompiled) == 0 || len(creation) < len(recompiled) {
+		return 0, "", false
+	}
+	prefix := creation[:len(recompiled)]
+	if prefix != recompiled && TrimBytecodeMetadata(prefix) != TrimBytecodeMetadata(recompiled) {
+		return 0, "", false
+	}
+	return len(recompiled) + 2, creation[len(recompiled):], true
+}
+
+// linkBytecode replace library placeholders with address in on-chain code
+func linkBytecode(compiled *solcBytecode, onChain string) string {
+	code := strings.ToLower(util.TrimHex(compiled.Object))
+	for _, libraries := range compiled.LinkReferences {
+		for _, references := range libraries {
+			for _, reference := range references {
+				start, end := reference.Start*2, (reference.Start+reference.Length)*2
+				if end > len(code) {
+					continue
+				}
+				address := strings.Repeat("0", end-start)
+				if end <= len(onChain) {
+					address = onChain[start:end]
+				}
+				code = code[:start] + address + code[end:]
+			}
+		}
+	}
+	return code
+}
+
+// TrimBytecodeMetadata remove cbor encoded metadata at the end of bytecode, last 2 bytes is metadata length
+func TrimBytecodeMetadata(code string) string {
+	code = util.TrimHex(code)
+	if len(code) < 4 {
+		return code
+	}
+	length := util.HexToBytes(code[len(code)-4:])
+	metadataLength := (int(length[0])<<8|int(length[1]))*2 + 4
+	if metadataLength > len(code) {
+		return code
+	}
+	// cbor map header
+	if header := util.HexToBytes(code[len(code)-metadataLength : len(code)-metadataLength+2]); len(header) == 0 || header[0]&0xe0 != 0xa0 {
+		return code
+	}
+	return code[:len(code)-metadataLength]
+}
@@ -0,0 +1,124 @@
+package contract
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/itering/subscan/util"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_NormalizeSolcVersion(t *testing.T) {
+	assert.Equal(t, "v0.8.26+commit.8a97fa7a", NormalizeSolcVersion("soljson-v0.8.26+commit.8a97fa7a.js"))
+	assert.Equal(t, "v0.8.26+commit.8a97fa7a", NormalizeSolcVersion("0.8.26+commit.8a97fa7a"))
+	assert.Equal(t, "", NormalizeSolcVersion(""))
+}
+
+func Test_TrimBytecodeMetadata(t *testing.T) {
+	assert.Equal(t, "6080", TrimBytecodeMetadata("0x6080a10000000004"))
+	// not cbor map
+	assert.Equal(t, "608000000004", TrimBytecodeMetadata("608000000004"))
+	assert.Equal(t, "60", TrimBytecodeMetadata("60"))
+}
+
+func Test_MatchBytecode(t *testing.T) {
+	library := "66a1cba4138cbb5bcc3e3af1b10c7fd56b6aac0b"
+	compiled := &solcBytecode{
+		Object:              "6080__$" + strings.Repeat("a", 34) + "$__6000" + strings.Repeat("0", 16) + "a10000000004",
+		LinkReferences:      solcLinkReferences{"lib.sol": {"Lib": {{Start: 2, Length: 20}}}},
+		ImmutableReferences: map[string][]solcReference{"3": {{Start: 24, Length: 8}}},
+	}
+	deployed := "0x6080" + library + "60001122334455667788"
+	assert.Equal(t, VerifiedPerfect, matchRuntimeBytecode(compiled, deployed+"a10000000004"))
+	assert.Equal(t, VerifiedPartial, matchRuntimeBytecode(compiled, deployed+"a1ffffff0004"))
+	assert.Equal(t, "", matchRuntimeBytecode(compiled, "0x6080"+library+"6001112233445566778a10000000004"))
+
+	creation := &solcBytecode{Object: "6080a10000000004"}
+	length, args, ok := matchCreationBytecode(creation, "0x6080a1ffffff0004"+"0000000000000000000000000000000000000000000000000000000000000001")
+	assert.True(t, ok)
+	assert.Equal(t, 18, length)
+	assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000001", args)
+	_, _, ok = matchCreationBytecode(creation, "0x6081a10000000004")
+	assert.False(t, ok)
+}
+
+func Test_MatchPvmBlob(t *testing.T) {
+	blob := "0x" + PvmBlobMagic + "0102030405"
+	assert.True(t, IsPvmBlob(blob))
+	assert.False(t, IsPvmBlob("0x6080604052"))
+	assert.Equal(t, VerifiedPerfect, matchPvmBlob(&solcBytecode{Object: PvmBlobMagic + "0102030405"}, blob))
+	assert.Equal(t, "", matchPvmBlob(&solcBytecode{Object: PvmBlobMagic + "0102030406"}, blob))
+	assert.Equal(t, "", matchPvmBlob(&solcBytecode{Object: "6080604052"}, "0x6080604052"))
+}
+
+func Test_checkSha256(t *testing.T) {
+	data := []byte("solc")
+	assert.NoError(t, checkSha256(data, sha256Hex(data)))
+	assert.NoError(t, checkSha256(data, util.TrimHex(sha256Hex(data))))
+	assert.Error(t, checkSha256(data, sha256Hex([]byte("resolc"))))
+	assert.Error(t, checkSha256(data, ""))
+}
+
+func Test_findSolcBuild(t *testing.T) {
+	builds := []solcBuild{{Path: "solc-linux-amd64-v0.8.26+commit.8a97fa7a", LongVersion: "0.8.26+commit.8a97fa7a", Sha256: "0x01"}}
+	build, err := findSolcBuild(builds, "v0.8.26+commit.8a97fa7a")
+	assert.NoError(t, err)
+	assert.Equal(t, "solc-linux-amd64-v0.8.26+commit.8a97fa7a", build.Path)
+	_, err = findSolcBuild(builds, "v0.8.27-nightly.2024.5.1+commit.1a2b3c4d")
+	assert.ErrorContains(t, err, "nightly")
+	_, err = findSolcBuild(builds, "v0.8.25+commit.b61c2a91")
+	assert.Error(t, err)
+}
+
+func Test_downloadVerifiedBinary(t *testing.T) {
+	data := []byte("#!/bin/sh\necho solc\n")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write(data) }))
+	defer server.Close()
+	bin := filepath.Join(t.TempDir(), "solc")
+
+	assert.Error(t, downloadVerifiedBinary(context.TODO(), server.URL, "0x00", bin))
+	assert.False(t, isExecutable(bin))
+
+	assert.NoError(t, downloadVerifiedBinary(context.TODO(), server.URL, sha256Hex(data), bin))
+	assert.True(t, isExecutable(bin))
+}
+
+func Test_acquireCompile(t *testing.T) {
+	release, err := acquireCompile(context.TODO())
+	assert.NoError(t, err)
+	release()
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	for i := 0; i < cap(compileSemaphore); i++ {
+		compileSemaphore <- struct{}{}
+	}
+	_, err = acquireCompile(ctx)
+	assert.Error(t, err)
+	for i := 0; i < cap(compileSemaphore); i++ {
+		<-compileSemaphore
+	}
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "0x" + hex.EncodeToString(sum[:])
+}
+
+func Test_findResolcDigest(t *testing.T) {
+	assets := []resolcReleaseAsset{
+		{Name: "resolc-x86_64-unknown-linux-musl", Digest: "sha256:0123"},
+		{Name: "resolc-universal-apple-darwin"},
+	}
+	digest, err := findResolcDigest(assets, "resolc-x86_64-unknown-linux-musl")
+	assert.NoError(t, err)
+	assert.Equal(t, "0123", digest)
+	_, err = findResolcDigest(assets, "resolc-universal-apple-darwin")
+	assert.Error(t, err)
+	_, err = findResolcDigest(assets, "resolc-x86_64-pc-windows-msvc.exe")
+	assert.Error(t, err)
+}
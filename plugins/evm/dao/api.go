@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"github.com/itering/subscan/model"
 	balanceModel "github.com/itering/subscan/plugins/balance/model"
+	"github.com/itering/subscan/plugins/evm/feature/delegateProxy"
+	"github.com/itering/subscan/share/price"
 	"github.com/itering/subscan/util"
 	"github.com/shopspring/decimal"
 	"strings"
@@ -15,21 +17,55 @@ type ISrv interface {
 	API_GetAccounts(ctx context.Context, h160 []string) (map[string]balanceModel.Account, error)
 	API_Transactions(ctx context.Context, opts ...model.Option) (res []EtherscanTxnRes)
 	API_TokenEventRes(ctx context.Context, opts ...model.Option) []EtherscanTokenEventRes
+	API_InternalTransactions(ctx context.Context, opts ...model.Option) []EtherscanInternalTxnRes
 	API_ContractSourceCode(_ context.Context, c *Contract) *EtherscanContractSourceCodeRes
 	API_GetContractCreation(ctx context.Context, addresses []string) (res []EtherscanContractCreationRes)
+	API_EthSupply(ctx context.Context) (decimal.Decimal, error)
+	API_TokenSupply(ctx context.Context, contract string) *decimal.Decimal
+	API_TokenBalance(ctx context.Context, contract, holder string) decimal.Decimal
+	API_BlockNoByTime(ctx context.Context, timestamp uint, closest string) *uint64
+	API_BlockReward(ctx context.Context, blockNum uint64) *EtherscanBlockRewardRes
+	API_BlockCountdown(ctx context.Context, blockNum uint64) (*EtherscanBlockCountdownRes, error)
+	API_ProxyBlockNumber(ctx context.Context) string
+	API_ProxyTransaction(ctx context.Context, hash string) *EtherscanProxyTxnRes
+	API_ProxyBlock(ctx context.Context, blockNum uint64, full bool) *EtherscanProxyBlockRes
+	API_ProxyCall(ctx context.Context, to, data string) (string, error)
 
 	ContractsByAddr(ctx context.Context, address string) (contract *Contract)
+	ContractFunctions(ctx context.Context, address string, asProxy bool) (*ContractFunctionsJson, error)
+	ContractRead(ctx context.Context, address string, asProxy bool, method string, args []interface{}, from string) ([]DecodedParam, error)
+	ContractEncodeCall(ctx context.Context, address string, asProxy bool, method string, args []interface{}, value string) (*ContractCallData, error)
 	GetTransactionByHash(c context.Context, hash string) *Transaction
 	Blocks(ctx context.Context, page int, row int) ([]EvmBlockJson, int)
 	BlocksCursor(ctx context.Context, limit int, before, after *uint) ([]EvmBlockJson, map[string]interface{})
 	BlockByNum(ctx context.Context, blockNum uint) *EvmBlock
 	BlockByHash(ctx context.Context, hash string) *EvmBlock
 	TransactionsCursor(ctx context.Context, limit int, before, after *uint, opts ...model.Option) ([]TransactionSampleJson, map[string]interface{})
+	LogsCursor(ctx context.Context, hash, address string, limit int, before, after *uint64) ([]ReceiptJson, map[string]interface{})
 	AccountsCursor(ctx context.Context, address string, limit int, before, after *string) ([]AccountsJson, map[string]interface{})
 	ContractsCursor(ctx context.Context, limit int, before, after *string) ([]ContractsJson, map[string]interface{})
 
 	AccountTokens(ctx context.Context, address, category string) []AccountTokenJson
+	AccountApprovalsCursor(ctx context.Context, address, category string, limit int, before, after *uint) ([]TokenApprovalJson, map[string]interface{})
+	AccountBalanceHistoryCursor(ctx context.Context, address string, limit int, before, after *uint) ([]BalanceHistoryJson, map[string]interface{})
+	API_BalanceHistory(ctx context.Context, address string, blockNum uint) (decimal.Decimal, error)
+	GasTracker(ctx context.Context, window int) *GasTrackerJson
+	API_GasOracle(ctx context.Context) *GasTrackerJson
+	API_GasEstimate(ctx context.Context, gasPrice decimal.Decimal) (int64, error)
 	CollectiblesCursor(ctx context.Context, address string, contract string, limit int, before, after *string) ([]Erc721Holders, map[string]interface{})
+	Erc1155HoldersCursor(ctx context.Context, address, contract, tokenId string, limit int, before, after *string) ([]ERC1155HolderJson, map[string]interface{})
+	NftMetadataStatus(ctx context.Context, contract string) *NftMetadataStatusJson
+	RefreshNftMetadata(ctx context.Context, contract, tokenId string) (*NftMetadata, error)
+	CreateEventSubscription(ctx context.Context, name, address string, rawAbi []byte) (*EventSubscription, error)
+	EventSubscriptions(ctx context.Context) []EventSubscription
+	DeleteEventSubscription(ctx context.Context, id uint) error
+	BackfillEventSubscription(ctx context.Context, id uint, from, to uint64) error
+	SubscriptionEventsCursor(ctx context.Context, id uint, confirmations uint64, limit int, after uint64) ([]SubscriptionEventJson, map[string]interface{}, error)
+	ReviveCode(ctx context.Context, codeHash string) (*ReviveCodeJson, error)
+	HolderSnapshotPage(ctx context.Context, contract, tokenId string, blockNum uint64, page, row int) (*HolderSnapshotJson, error)
+	ResolveName(ctx context.Context, name string) (*NameRecord, error)
+	ReverseName(ctx context.Context, address string) (*NameReverseJson, error)
+	SubmitRawTransaction(ctx context.Context, raw string) (string, error)
 	TokenListCursor(ctx context.Context, contract, category string, limit int, before, after *string) ([]Token, map[string]interface{})
 	TokenTransfersCursor(ctx context.Context, address, tokenAddress, category string, limit int, before, after *uint) ([]TokenTransferJson, map[string]interface{})
 	TokenHoldersCursor(ctx context.Context, address string, limit int, before, after *string) ([]TokenHolder, map[string]interface{})
@@ -287,18 +323,70 @@ type EtherscanContractSourceCodeRes struct {
 	SimilarMatch         string `json:"SimilarMatch"`
 }
 
+type EtherscanInternalTxnRes struct {
+	BlockNumber     string `json:"blockNumber"`
+	TimeStamp       string `json:"timeStamp"`
+	Hash            string `json:"hash"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Value           string `json:"value"`
+	ContractAddress string `json:"contractAddress"`
+	Input           string `json:"input"`
+	Type            string `json:"type"`
+	Gas             string `json:"gas"`
+	GasUsed         string `json:"gasUsed"`
+	TraceId         string `json:"traceId"`
+	IsError         string `json:"isError"`
+	ErrCode         string `json:"errCode"`
+}
+
+func (a *ApiSrv) API_InternalTransactions(ctx context.Context, opts ...model.Option) []EtherscanInternalTxnRes {
+	var list []InternalTransaction
+	// depth 0 is the transaction itself
+	sg.db.WithContext(ctx).Where("depth > 0").Scopes(opts...).Find(&list)
+	var res []EtherscanInternalTxnRes
+	for _, v := range list {
+		var isErr = "0"
+		if v.Error != "" {
+			isErr = "1"
+		}
+		item := EtherscanInternalTxnRes{
+			BlockNumber: fmt.Sprintf("%d", v.BlockNum),
+			TimeStamp:   fmt.Sprintf("%d", v.BlockTimestamp),
+			Hash:        v.Hash,
+			From:        v.FromAddress,
+			To:          v.ToAddress,
+			Value:       v.Value.String(),
+			Input:       v.Input,
+			Type:        strings.ToLower(v.CallType),
+			Gas:         v.Gas.String(),
+			GasUsed:     v.GasUsed.String(),
+			TraceId:     v.TraceAddress,
+			IsError:     isErr,
+			ErrCode:     v.Error,
+		}
+		// create contract, to is empty
+		if strings.HasPrefix(v.CallType, Create) {
+			item.ContractAddress = v.ToAddress
+			item.To = ""
+		}
+		res = append(res, item)
+	}
+	return res
+}
+
 func (a *ApiSrv) API_ContractSourceCode(_ context.Context, c *Contract) *EtherscanContractSourceCodeRes {
 	res := &EtherscanContractSourceCodeRes{
-		SourceCode:       c.SourceCode,
-		ABI:              c.Abi.String(),
-		ContractName:     c.ContractName,
-		CompilerVersion:  c.CompilerVersion,
-		OptimizationUsed: "0",
-		Runs:             fmt.Sprintf("%d", c.OptimizationRuns),
-		EVMVersion:       c.EvmVersion,
-		Library:          c.ExternalLibraries.String(),
-		Proxy:            c.VerifyType,
-		// ConstructorArguments: "",
+		SourceCode:           c.SourceCode,
+		ABI:                  c.Abi.String(),
+		ContractName:         c.ContractName,
+		CompilerVersion:      c.CompilerVersion,
+		OptimizationUsed:     "0",
+		Runs:                 fmt.Sprintf("%d", c.OptimizationRuns),
+		EVMVersion:           c.EvmVersion,
+		Library:              c.ExternalLibraries.String(),
+		Proxy:                c.VerifyType,
+		ConstructorArguments: util.TrimHex(c.ConstructorArguments),
 		// LicenseType:          "",
 	}
 	if c.Optimize {
@@ -336,11 +424,39 @@ func (a *ApiSrv) API_GetContractCreation(ctx context.Context, addresses []string
 }
 
 func (a *ApiSrv) ContractsByAddr(ctx context.Context, address string) (contract *Contract) {
-	return ContractsByAddr(ctx, address)
+	if contract = ContractsByAddr(ctx, address); contract != nil && contract.EipStandard == delegateProxy.EIP2535Standard {
+		contract.Facets = ContractFacets(ctx, address)
+	}
+	return
+}
+
+func (a *ApiSrv) ContractFunctions(ctx context.Context, address string, asProxy bool) (*ContractFunctionsJson, error) {
+	return ContractFunctions(ctx, address, asProxy)
+}
+
+func (a *ApiSrv) ContractRead(ctx context.Context, address string, asProxy bool, method string, args []interface{}, from string) ([]DecodedParam, error) {
+	return ContractRead(ctx, address, asProxy, method, args, from)
+}
+
+func (a *ApiSrv) ContractEncodeCall(ctx context.Context, address string, asProxy bool, method string, args []interface{}, value string) (*ContractCallData, error) {
+	return ContractEncodeCall(ctx, address, asProxy, method, args, value)
 }
 
 func (a *ApiSrv) GetTransactionByHash(c context.Context, hash string) *Transaction {
-	return GetTransactionByHash(c, hash)
+	transaction := GetTransactionByHash(c, hash)
+	if transaction == nil {
+		return nil
+	}
+	if list := InternalTransactionsByHash(c, hash); len(list) > 0 {
+		transaction.TraceErrorMsg = TraceErrorMsg(list)
+		transaction.Trace = BuildCallTree(list)
+	}
+	decoder := NewAbiDecoder()
+	transaction.DecodedInput = decoder.DecodeInput(c, transaction.ToAddress, transaction.InputData)
+	transaction.Logs = DecodeReceipts(c, decoder, ReceiptsByHash(c, hash))
+	names := PrimaryNames(c, []string{transaction.FromAddress, transaction.ToAddress})
+	transaction.FromName, transaction.ToName = names[transaction.FromAddress], names[transaction.ToAddress]
+	return transaction
 }
 
 type EvmBlockJson struct {
@@ -415,6 +531,8 @@ type TransactionSampleJson struct {
 	ToAddress      string          `json:"to_address"`
 	Create         string          `json:"create"`
 	Value          decimal.Decimal `json:"value"`
+	FromName       string          `json:"from_name,omitempty"`
+	ToName         string          `json:"to_name,omitempty"`
 }
 
 func (a *ApiSrv) TransactionsCursor(ctx context.Context, limit int, before, after *uint, opts ...model.Option) ([]TransactionSampleJson, map[string]interface{}) {
@@ -450,8 +568,14 @@ func (a *ApiSrv) TransactionsCursor(ctx context.Context, limit int, before, afte
 		hasPrev = after != nil && *after > 0
 	}
 	var res []TransactionSampleJson
+	var addresses []string
 	for _, v := range txs {
-		res = append(res, TransactionSampleJson{Hash: v.Hash, BlockNum: v.BlockNum, BlockTimestamp: v.BlockTimestamp, FromAddress: v.FromAddress, ToAddress: v.ToAddress, Value: v.Value, Create: v.Contract, TransactionId: v.TransactionId})
+		addresses = append(addresses, v.FromAddress, v.ToAddress)
+	}
+	names := PrimaryNames(ctx, addresses)
+	for _, v := range txs {
+		res = append(res, TransactionSampleJson{Hash: v.Hash, BlockNum: v.BlockNum, BlockTimestamp: v.BlockTimestamp, FromAddress: v.FromAddress, ToAddress: v.ToAddress, Value: v.Value, Create: v.Contract, TransactionId: v.TransactionId,
+			FromName: names[v.FromAddress], ToName: names[v.ToAddress]})
 	}
 	var start, end *uint
 	if len(txs) > 0 {
@@ -463,9 +587,56 @@ func (a *ApiSrv) TransactionsCursor(ctx context.Context, limit int, before, afte
 	return res, map[string]interface{}{"start_cursor": start, "end_cursor": end, "has_previous_page": hasPrev, "has_next_page": hasNext}
 }
 
+func (a *ApiSrv) LogsCursor(ctx context.Context, hash, address string, limit int, before, after *uint64) ([]ReceiptJson, map[string]interface{}) {
+	var list []TransactionReceipt
+	fetch := limit + 1
+	q := sg.db.WithContext(ctx).Model(TransactionReceipt{})
+	if hash != "" {
+		q.Where("transaction_hash = ?", hash)
+	}
+	if address != "" {
+		q.Where("address = ?", address)
+	}
+	if after != nil && *after > 0 {
+		q = q.Where("id < ?", *after).Order("id desc")
+	} else if before != nil && *before > 0 {
+		q = q.Where("id > ?", *before).Order("id asc")
+	} else {
+		q = q.Order("id desc")
+	}
+	q = q.Limit(fetch).Find(&list)
+	if q.Error != nil {
+		return nil, nil
+	}
+	var hasPrev, hasNext bool
+	if before != nil && *before > 0 {
+		hasPrev = len(list) > limit
+		if hasPrev {
+			list = list[:limit]
+		}
+		for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+			list[i], list[j] = list[j], list[i]
+		}
+		hasNext = true
+	} else {
+		hasNext = len(list) > limit
+		if hasNext {
+			list = list[:limit]
+		}
+		hasPrev = after != nil && *after > 0
+	}
+	var start, end *uint64
+	if len(list) > 0 {
+		start = &list[0].Id
+		end = &list[len(list)-1].Id
+	}
+	return DecodeReceipts(ctx, NewAbiDecoder(), list), map[string]interface{}{"start_cursor": start, "end_cursor": end, "has_previous_page": hasPrev, "has_next_page": hasNext}
+}
+
 type AccountsJson struct {
 	EvmAccount string          `json:"evm_account"`
 	Balance    decimal.Decimal `json:"balance"`
+	Name       string          `json:"name,omitempty" gorm:"-"`
 }
 
 func (a AccountsJson) Cursor() string {
@@ -487,6 +658,14 @@ func (a *ApiSrv) AccountsCursor(ctx context.Context, address string, limit int,
 		q = q.Order("balance desc").Order("balance_accounts.address desc")
 	}
 	q.Limit(fetch).Scan(&list)
+	var accounts []string
+	for _, account := range list {
+		accounts = append(accounts, account.EvmAccount)
+	}
+	names := PrimaryNames(ctx, accounts)
+	for index := range list {
+		list[index].Name = names[list[index].EvmAccount]
+	}
 	var hasPrev, hasNext bool
 	if before != nil && *before != "" {
 		hasPrev = len(list) > limit
@@ -588,6 +767,64 @@ func (a *ApiSrv) AccountTokens(ctx context.Context, address, category string) []
 	return tokenHolders
 }
 
+func (a *ApiSrv) AccountApprovalsCursor(ctx context.Context, address, category string, limit int, before, after *uint) ([]TokenApprovalJson, map[string]interface{}) {
+	var list []TokenApproval
+	fetch := limit + 1
+	q := sg.db.WithContext(ctx).Model(&TokenApproval{}).Where("owner = ?", address)
+	if category != "" {
+		q.Where("category = ?", category)
+	}
+	if after != nil && *after > 0 {
+		q = q.Where("id < ?", *after).Order("id desc")
+	} else if before != nil && *before > 0 {
+		q = q.Where("id > ?", *before).Order("id asc")
+	} else {
+		q = q.Order("id desc")
+	}
+	q = q.Limit(fetch).Find(&list)
+	if q.Error != nil {
+		return nil, nil
+	}
+	var hasPrev, hasNext bool
+	if before != nil && *before > 0 {
+		hasPrev = len(list) > limit
+		if hasPrev {
+			list = list[:limit]
+		}
+		for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+			list[i], list[j] = list[j], list[i]
+		}
+		hasNext = true
+	} else {
+		hasNext = len(list) > limit
+		if hasNext {
+			list = list[:limit]
+		}
+		hasPrev = after != nil && *after > 0
+	}
+	var tokensAddress []string
+	for _, v := range list {
+		tokensAddress = append(tokensAddress, v.Contract)
+	}
+	addr2Token := ContractAddr2Token(ctx, tokensAddress)
+	var res []TokenApprovalJson
+	for _, v := range list {
+		approval := TokenApprovalJson{TokenApproval: v}
+		if token, ok := addr2Token[v.Contract]; ok {
+			approval.Name = token.Name
+			approval.Symbol = token.Symbol
+			approval.Decimals = token.Decimals
+		}
+		res = append(res, approval)
+	}
+	var start, end *uint
+	if len(list) > 0 {
+		start = &list[0].Id
+		end = &list[len(list)-1].Id
+	}
+	return res, map[string]interface{}{"start_cursor": start, "end_cursor": end, "has_previous_page": hasPrev, "has_next_page": hasNext}
+}
+
 var cursorDecode = func(c *string) []string {
 	// decode base64
 	if c == nil || *c == "" {
@@ -657,6 +894,83 @@ func (a *ApiSrv) CollectiblesCursor(ctx context.Context, address string, contrac
 	}
 }
 
+func (a *ApiSrv) Erc1155HoldersCursor(ctx context.Context, address, contract, tokenId string, limit int, before, after *string) ([]ERC1155HolderJson, map[string]interface{}) {
+	var list []ERC1155Holder
+	fetch := limit + 1
+	q := sg.db.WithContext(ctx).Model(&ERC1155Holder{}).Where("balance > 0")
+	if address != "" {
+		q.Where("holder = ?", address)
+	}
+	if contract != "" {
+		q.Where("contract = ?", contract)
+	}
+	if tokenId != "" {
+		q.Where("token_id = ?", tokenId)
+	}
+	if cursor := cursorDecode(after); len(cursor) == 2 {
+		q = q.Where("(balance,id) < (?,?)", cursor[0], cursor[1]).Order("balance desc").Order("id desc")
+	} else if cursor = cursorDecode(before); len(cursor) == 2 {
+		q = q.Where("(balance,id) > (?,?)", cursor[0], cursor[1]).Order("balance asc").Order("id asc")
+	} else {
+		q = q.Order("balance desc").Order("id desc")
+	}
+	q = q.Limit(fetch).Find(&list)
+	if q.Error != nil {
+		return nil, nil
+	}
+	var hasPrev, hasNext bool
+	if before != nil && *before != "" {
+		hasPrev = len(list) > limit
+		if hasPrev {
+			list = list[:limit]
+		}
+		for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+			list[i], list[j] = list[j], list[i]
+		}
+		hasNext = true
+	} else {
+		hasNext = len(list) > limit
+		if hasNext {
+			list = list[:limit]
+		}
+		hasPrev = after != nil && *after != ""
+	}
+	var itemIds []string
+	for _, v := range list {
+		itemIds = append(itemIds, erc1155ItemId(v.Contract, v.TokenId))
+	}
+	var items []ERC1155Item
+	if len(itemIds) > 0 {
+		sg.db.WithContext(ctx).Where("id in ?", itemIds).Find(&items)
+	}
+	var itemMap = make(map[string]ERC1155Item)
+	for _, v := range items {
+		itemMap[v.Id] = v
+	}
+	var res []ERC1155HolderJson
+	for _, v := range list {
+		holder := ERC1155HolderJson{ERC1155Holder: v}
+		if item, ok := itemMap[erc1155ItemId(v.Contract, v.TokenId)]; ok {
+			holder.Metadata = &item.Metadata
+			holder.StorageUrl = item.StorageUrl
+		}
+		res = append(res, holder)
+	}
+	var start, end *string
+	if len(list) > 0 {
+		s := list[0].Cursor()
+		e := list[len(list)-1].Cursor()
+		start = &s
+		end = &e
+	}
+	return res, map[string]interface{}{
+		"start_cursor":      start,
+		"end_cursor":        end,
+		"has_previous_page": hasPrev,
+		"has_next_page":     hasNext,
+	}
+}
+
 func (a *ApiSrv) TokenListCursor(ctx context.Context, contract, category string, limit int, before, after *string) ([]Token, map[string]interface{}) {
 	var list []Token
 	fetch := limit + 1
@@ -721,7 +1035,7 @@ func (a *ApiSrv) TokenTransfersCursor(ctx context.Context, address, tokenAddress
 		q.Where("contract = ?", tokenAddress)
 	}
 	if category != "" {
-		q.Where("category = ?", category)
+		q.Where("category = ?", TransferCategory[category])
 	}
 	if after != nil && *after > 0 {
 		q = q.Where("transfer_id < ?", *after).Order("transfer_id desc")
@@ -757,14 +1071,28 @@ func (a *ApiSrv) TokenTransfersCursor(ctx context.Context, address, tokenAddress
 		tokensAddress = append(tokensAddress, v.Contract)
 	}
 	addr2Token := ContractAddr2Token(ctx, tokensAddress)
+	book := price.NewBook(sg.db)
+	var addresses []string
+	for _, v := range transfers {
+		addresses = append(addresses, v.Sender, v.Receiver)
+	}
+	names := PrimaryNames(ctx, addresses)
 	for index := range transfers {
 		transfer := transfers[index]
-		tj := TokenTransferJson{ID: transfer.TransferId, Contract: transfer.Contract, Hash: transfer.Hash, CreateAt: transfer.CreateAt, From: transfer.Sender, To: transfer.Receiver, Value: &transfer.Value}
+		tj := TokenTransferJson{ID: transfer.TransferId, Contract: transfer.Contract, Hash: transfer.Hash, CreateAt: transfer.CreateAt, From: transfer.Sender, To: transfer.Receiver, Value: &transfer.Value,
+			FromName: names[transfer.Sender], ToName: names[transfer.Receiver]}
+		if transfer.TokenId != "" {
+			tj.TokenId = &transfer.TokenId
+		}
 		if token, ok := addr2Token[transfer.Contract]; ok {
 			tj.Decimals = &token.Decimals
 			tj.Symbol = token.Symbol
 			tj.Name = token.Name
 			tj.Category = token.Category
+			// nft has no fungible value
+			if token.Category == Eip20Token {
+				tj.ValueUSD = price.ValueUSD(transfer.Value, int(token.Decimals), book.At(ctx, price.ContractKey(transfer.Contract), int64(transfer.CreateAt)))
+			}
 		}
 		res = append(res, tj)
 	}
@@ -794,6 +1122,20 @@ func (a *ApiSrv) TokenHoldersCursor(ctx context.Context, address string, limit i
 	if q.Error != nil {
 		return nil, nil
 	}
+	var holders []string
+	for _, holder := range list {
+		holders = append(holders, holder.Holder)
+	}
+	names := PrimaryNames(ctx, holders)
+	for index := range list {
+		list[index].HolderName = names[list[index].Holder]
+	}
+	if token := ContractAddr2Token(ctx, []string{address})[address]; token.Category == Eip20Token {
+		latest := price.Latest(ctx, sg.db, price.ContractKey(address))
+		for index := range list {
+			list[index].ValueUSD = price.ValueUSD(list[index].Balance, int(token.Decimals), latest)
+		}
+	}
 	var hasPrev, hasNext bool
 	if before != nil && *before != "" {
 		hasPrev = len(list) > limit
@@ -820,3 +1162,49 @@ func (a *ApiSrv) TokenHoldersCursor(ctx context.Context, address string, limit i
 	}
 	return list, map[string]interface{}{"start_cursor": start, "end_cursor": end, "has_previous_page": hasPrev, "has_next_page": hasNext}
 }
+
+func (a *ApiSrv) AccountBalanceHistoryCursor(ctx context.Context, address string, limit int, before, after *uint) ([]BalanceHistoryJson, map[string]interface{}) {
+	var list []BalanceHistoryJson
+	fetch := limit + 1
+	q := sg.db.WithContext(ctx).Model(BalanceChange{}).
+		Select("block_num, max(block_timestamp) as block_timestamp, sum(delta) as delta, max(nonce) as nonce").
+		Where("address = ?", address).Group("block_num")
+	if after != nil && *after > 0 {
+		q = q.Where("block_num < ?", *after).Order("block_num desc")
+	} else if before != nil && *before > 0 {
+		q = q.Where("block_num > ?", *before).Order("block_num asc")
+	} else {
+		q = q.Order("block_num desc")
+	}
+	q = q.Limit(fetch).Scan(&list)
+	if q.Error != nil {
+		return nil, nil
+	}
+	var hasPrev, hasNext bool
+	if before != nil && *before > 0 {
+		hasPrev = len(list) > limit
+		if hasPrev {
+			list = list[:limit]
+		}
+		for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+			list[i], list[j] = list[j], list[i]
+		}
+		hasNext = true
+	} else {
+		hasNext = len(list) > limit
+		if hasNext {
+			list = list[:limit]
+		}
+		hasPrev = after != nil && *after > 0
+	}
+	var start, end *uint
+	if len(list) > 0 {
+		start = &list[0].BlockNum
+		end = &list[len(list)-1].BlockNum
+	}
+	return list, map[string]interface{}{"start_cursor": start, "end_cursor": end, "has_previous_page": hasPrev, "has_next_page": hasNext}
+}
+
+func (a *ApiSrv) API_BalanceHistory(ctx context.Context, address string, blockNum uint) (decimal.Decimal, error) {
+	return BalanceAt(ctx, address, blockNum)
+}
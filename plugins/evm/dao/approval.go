@@ -0,0 +1,114 @@
+package dao
+
+import (
+	"context"
+	"github.com/itering/subscan/plugins/evm/feature/erc20"
+	"github.com/itering/subscan/plugins/evm/feature/erc721"
+	"github.com/itering/subscan/share/web3"
+	"github.com/itering/subscan/util"
+	"github.com/itering/subscan/util/mq"
+	"github.com/shopspring/decimal"
+	"strings"
+)
+
+// TokenApproval outstanding erc20 allowance or erc721/erc1155 operator approval
+// allowance is varchar, unlimited approve is max uint256 with 78 digits, out of decimal(65) range
+type TokenApproval struct {
+	Id          uint            `json:"id" gorm:"primaryKey;autoIncrement;size:32"`
+	Contract    string          `json:"contract" gorm:"size:100;index:contract_owner_spender,unique"`
+	Owner       string          `json:"owner" gorm:"size:100;index:contract_owner_spender,unique;index:owner"`
+	Spender     string          `json:"spender" gorm:"size:100;index:contract_owner_spender,unique"`
+	Category    string          `json:"category" gorm:"size:70"`
+	Allowance   decimal.Decimal `json:"allowance" gorm:"default: 0;type:varchar(80);"`
+	ApprovedAll bool            `json:"approved_all"`
+	Hash        string          `json:"hash" gorm:"size:100"`
+	BlockNum    uint64          `json:"block_num" gorm:"size:32"`
+	UpdateAt    uint            `json:"update_at" gorm:"size:32"`
+}
+
+func (t *TokenApproval) TableName() string {
+	return "evm_token_approvals"
+}
+
+// ProcessApproval process erc20 Approval and erc721/erc1155 ApprovalForAll
+func (t *TransactionReceipt) ProcessApproval(ctx context.Context, category string) error {
+	topics := strings.Split(t.Topics, ",")
+	if len(topics) < 3 {
+		return nil
+	}
+	approval := TokenApproval{
+		Contract: t.Address,
+		Owner:    RemoveAddressPadded(topics[1]),
+		Spender:  RemoveAddressPadded(topics[2]),
+		Category: category,
+		Hash:     t.TransactionHash,
+		BlockNum: t.BlockNum,
+		UpdateAt: t.BlockTimestamp,
+	}
+	switch util.TrimHex(t.MethodHash) {
+	// Approval(address indexed _owner, address indexed _spender, uint256 _value)
+	// erc721 Approval has indexed token id and will be cleared after transfer, ignore it
+	case erc20.EventApproval:
+		if category != Eip20Token || len(topics) != 3 {
+			return nil
+		}
+		TouchToken(ctx, t.Address, Eip20Token)
+		approval.Allowance = util.EvmU256Decoder(t.Data)
+		if err := saveApproval(ctx, &approval); err != nil {
+			return err
+		}
+		// event value maybe outdated when syncing history block
+		return RefreshAllowance(ctx, approval.Contract, approval.Owner, approval.Spender)
+	// ApprovalForAll(address indexed _owner, address indexed _operator, bool _approved)
+	case erc721.EventApprovalForAll:
+		if category != Eip721Token && category != Eip1155Token {
+			return nil
+		}
+		approval.ApprovedAll = util.U256(t.Data).Sign() > 0
+		return saveApproval(ctx, &approval)
+	}
+	return nil
+}
+
+func saveApproval(ctx context.Context, approval *TokenApproval) error {
+	// revoked
+	if approval.Allowance.IsZero() && !approval.ApprovedAll {
+		return sg.db.WithContext(ctx).Where("contract = ? and owner = ? and spender = ?", approval.Contract, approval.Owner, approval.Spender).
+			Delete(TokenApproval{}).Error
+	}
+	return sg.AddOrUpdateItem(ctx, approval, []string{"contract", "owner", "spender"}, "allowance", "approved_all", "hash", "block_num", "update_at").Error
+}
+
+// RefreshAllowance sync erc20 allowance(owner, spender) from chain
+func RefreshAllowance(ctx context.Context, contract, owner, spender string) error {
+	allowance, err := erc20.Init(web3.RPC, contract).Allowance(ctx, owner, spender)
+	if err != nil {
+		return err
+	}
+	if allowance.IsZero() {
+		return sg.db.WithContext(ctx).Where("contract = ? and owner = ? and spender = ?", contract, owner, spender).
+			Delete(TokenApproval{}).Error
+	}
+	// row maybe deleted by outdated zero value event when syncing history block
+	approval := TokenApproval{Contract: contract, Owner: owner, Spender: spender, Category: Eip20Token, Allowance: allowance}
+	return sg.AddOrUpdateItem(ctx, &approval, []string{"contract", "owner", "spender"}, "allowance").Error
+}
+
+// refreshOwnerAllowances transferFrom spend allowance without Approval event
+func refreshOwnerAllowances(ctx context.Context, contract, owner string) {
+	if mq.Instant == nil {
+		return
+	}
+	var spenders []string
+	sg.db.WithContext(ctx).Model(TokenApproval{}).Where("contract = ? and owner = ?", contract, owner).Pluck("spender", &spenders)
+	for _, spender := range spenders {
+		_ = Publish(Eip20Token, "allowance", []string{contract, owner, spender})
+	}
+}
+
+type TokenApprovalJson struct {
+	TokenApproval
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals uint   `json:"decimals"`
+}
@@ -0,0 +1,170 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/itering/subscan-plugin/storage"
+	"github.com/itering/subscan/model"
+	"github.com/itering/subscan/share/web3"
+	"github.com/itering/subscan/util"
+	"github.com/shopspring/decimal"
+	"strings"
+)
+
+// ErrBalanceUnavailable historical balance needs archive node
+var ErrBalanceUnavailable = errors.New("historical balance unavailable, archive node required")
+
+const (
+	BalanceChangeTransfer  = "transfer"
+	BalanceChangeFee       = "fee"
+	BalanceChangeInternal  = "internal"
+	BalanceChangeSubstrate = "substrate"
+)
+
+// BalanceChange native balance change of evm account, source is unique for each account
+type BalanceChange struct {
+	Id             uint64          `json:"id" gorm:"primaryKey;autoIncrement;size:64"`
+	Address        string          `json:"address" gorm:"size:70;index:address_block,priority:1;index:address_source,unique,priority:1"`
+	Source         string          `json:"source" gorm:"size:100;index:address_source,unique,priority:2"`
+	Category       string          `json:"category" gorm:"size:20"`
+	BlockNum       uint            `json:"block_num" gorm:"size:32;index:address_block,priority:2"`
+	BlockTimestamp uint            `json:"block_timestamp" gorm:"size:32"`
+	ExtrinsicIndex string          `json:"extrinsic_index" gorm:"size:100;index:extrinsic_index"`
+	Delta          decimal.Decimal `json:"delta" gorm:"default: 0;type:decimal(65);"`
+	Nonce          uint            `json:"nonce" gorm:"size:32"`
+}
+
+func (b *BalanceChange) TableName() string {
+	return "evm_balance_changes"
+}
+
+// balanceChanges gas fee and value transfer of transaction, value is refunded if failed
+func (t *Transaction) balanceChanges() []BalanceChange {
+	base := BalanceChange{BlockNum: t.BlockNum, BlockTimestamp: t.BlockTimestamp, ExtrinsicIndex: t.ExtrinsicIndex}
+	var list []BalanceChange
+	if t.FromAddress != "" {
+		change := base
+		change.Address, change.Source, change.Category = t.FromAddress, "fee:"+t.Hash, BalanceChangeFee
+		change.Delta, change.Nonce = t.GasUsed.Mul(t.EffectiveGasPrice).Neg(), t.Nonce+1
+		list = append(list, change)
+	}
+	to := util.IfEmptyElse(t.ToAddress, t.Contract)
+	if t.Success && t.Value.IsPositive() && to != "" {
+		out, in := base, base
+		out.Address, out.Source, out.Category, out.Delta = t.FromAddress, "out:"+t.Hash, BalanceChangeTransfer, t.Value.Neg()
+		in.Address, in.Source, in.Category, in.Delta = to, "in:"+t.Hash, BalanceChangeTransfer, t.Value
+		list = append(list, out, in)
+	}
+	return list
+}
+
+// internalBalanceChanges value transfer of internal calls, reverted call and its sub calls are ignored
+func internalBalanceChanges(t *Transaction, list []InternalTransaction) []BalanceChange {
+	var (
+		changes  []BalanceChange
+		reverted []string
+	)
+	for _, internal := range list {
+		if internal.Error != "" {
+			reverted = append(reverted, internal.TraceAddress)
+		}
+		if internal.Depth == 0 || !internal.Value.IsPositive() || isRevertedCall(internal.TraceAddress, reverted) {
+			continue
+		}
+		switch internal.CallType {
+		case "CALL", "CREATE", "CREATE2", "SELFDESTRUCT":
+		default:
+			continue
+		}
+		base := BalanceChange{BlockNum: internal.BlockNum, BlockTimestamp: internal.BlockTimestamp, ExtrinsicIndex: t.ExtrinsicIndex, Category: BalanceChangeInternal}
+		out, in := base, base
+		out.Address, out.Source, out.Delta = internal.FromAddress, fmt.Sprintf("internal_out:%d", internal.Id), internal.Value.Neg()
+		in.Address, in.Source, in.Delta = internal.ToAddress, fmt.Sprintf("internal_in:%d", internal.Id), internal.Value
+		changes = append(changes, out, in)
+	}
+	return changes
+}
+
+func isRevertedCall(traceAddress string, reverted []string) bool {
+	for _, r := range reverted {
+		// root call reverted
+		if r == "" || traceAddress == r || strings.HasPrefix(traceAddress, r+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// saveBalanceChanges substrate transfer of ethereum transaction extrinsic will be replaced
+func saveBalanceChanges(ctx context.Context, list []BalanceChange) error {
+	if len(list) == 0 {
+		return nil
+	}
+	if extrinsicIndex := list[0].ExtrinsicIndex; extrinsicIndex != "" {
+		sg.db.WithContext(ctx).Where("extrinsic_index = ?", extrinsicIndex).Where("category = ?", BalanceChangeSubstrate).Delete(BalanceChange{})
+	}
+	return sg.db.WithContext(ctx).Scopes(model.IgnoreDuplicate).CreateInBatches(list, 1000).Error
+}
+
+// ProcessSubstrateTransfer balances.Transfer to/from account mapped to evm address
+func ProcessSubstrateTransfer(ctx context.Context, block *storage.Block, event *storage.Event) error {
+	var params []storage.EventParam
+	if err := util.UnmarshalAny(&params, event.Params); err != nil || len(params) < 3 {
+		return nil
+	}
+	extrinsicIndex := fmt.Sprintf("%d-%d", event.BlockNum, event.ExtrinsicIdx)
+	// value transfer of ethereum transaction has been recorded
+	var count int64
+	sg.db.WithContext(ctx).Model(Transaction{}).Where("extrinsic_index = ?", extrinsicIndex).Count(&count)
+	if count > 0 {
+		return nil
+	}
+	amount := util.DecimalFromInterface(params[2].Value)
+	base := BalanceChange{BlockNum: uint(event.BlockNum), BlockTimestamp: uint(block.BlockTimestamp), ExtrinsicIndex: extrinsicIndex, Category: BalanceChangeSubstrate}
+	var list []BalanceChange
+	for index, delta := range []decimal.Decimal{amount.Neg(), amount} {
+		if evmAccount := accountIdToEvmAccount(ctx, model.CheckoutParamValueAddress(params[index].Value)); evmAccount != "" {
+			change := base
+			change.Address, change.Delta = evmAccount, delta
+			change.Source = fmt.Sprintf("event_%d:%d-%d", index, event.BlockNum, event.EventIdx)
+			list = append(list, change)
+		}
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	return sg.db.WithContext(ctx).Scopes(model.IgnoreDuplicate).Create(&list).Error
+}
+
+// accountIdToEvmAccount mapped evm address of substrate account id
+func accountIdToEvmAccount(ctx context.Context, accountId string) string {
+	if accountId == "" {
+		return ""
+	}
+	if util.IsEvmChain {
+		return strings.ToLower(util.AddHex(accountId))
+	}
+	var account Account
+	if q := sg.db.WithContext(ctx).Where("address in ?", []string{util.TrimHex(accountId), util.AddHex(accountId)}).First(&account); q.Error != nil {
+		return ""
+	}
+	return account.EvmAccount
+}
+
+type BalanceHistoryJson struct {
+	BlockNum       uint            `json:"block_num"`
+	BlockTimestamp uint            `json:"block_timestamp"`
+	Delta          decimal.Decimal `json:"delta"`
+	Nonce          uint            `json:"nonce"`
+}
+
+// BalanceAt native balance at block from archive node.
+// indexed balance changes miss fee and history before indexed, sum of them is not a balance, so no fallback
+func BalanceAt(ctx context.Context, address string, blockNum uint) (decimal.Decimal, error) {
+	balance, err := web3.RPC.Eth.GetBalance(ctx, address, fmt.Sprintf("0x%x", blockNum))
+	if err != nil || balance == nil {
+		return decimal.Zero, ErrBalanceUnavailable
+	}
+	return decimal.NewFromBigInt(balance, 0), nil
+}
@@ -0,0 +1,36 @@
+package dao
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_BalanceChanges(t *testing.T) {
+	txn := Transaction{Hash: "0x01", FromAddress: "0xa", ToAddress: "0xb", Nonce: 4, Success: true,
+		Value: decimal.New(1, 18), GasUsed: decimal.New(21000, 0), EffectiveGasPrice: decimal.New(1, 9)}
+	list := txn.balanceChanges()
+	assert.Len(t, list, 3)
+	assert.Equal(t, BalanceChangeFee, list[0].Category)
+	assert.Equal(t, "-21000000000000", list[0].Delta.String())
+	assert.Equal(t, uint(5), list[0].Nonce)
+	assert.Equal(t, "0xb", list[2].Address)
+
+	// failed transaction only pay fee
+	txn.Success = false
+	assert.Len(t, txn.balanceChanges(), 1)
+
+	internals := []InternalTransaction{
+		{Id: 1, Depth: 0, CallType: "CALL", Value: decimal.New(1, 0)},
+		{Id: 2, Depth: 1, TraceAddress: "0", CallType: "CALL", FromAddress: "0xb", ToAddress: "0xc", Value: decimal.New(2, 0)},
+		{Id: 3, Depth: 1, TraceAddress: "1", CallType: "CALL", Error: "execution reverted", Value: decimal.New(3, 0)},
+		{Id: 4, Depth: 2, TraceAddress: "1_0", CallType: "CALL", Value: decimal.New(4, 0)},
+		{Id: 5, Depth: 1, TraceAddress: "2", CallType: "DELEGATECALL", Value: decimal.New(5, 0)},
+		{Id: 6, Depth: 1, TraceAddress: "10", CallType: "CALL", Value: decimal.New(6, 0)},
+	}
+	changes := internalBalanceChanges(&txn, internals)
+	assert.Len(t, changes, 4)
+	assert.Equal(t, "-2", changes[0].Delta.String())
+	assert.Equal(t, "0xc", changes[1].Address)
+	assert.Equal(t, "6", changes[3].Delta.String())
+}
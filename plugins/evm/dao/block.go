@@ -10,6 +10,7 @@ import (
 	"github.com/itering/subscan/util"
 	"github.com/itering/subscan/util/network"
 	"math/big"
+	"strings"
 	"sync"
 
 	"github.com/panjf2000/ants/v2"
@@ -84,31 +85,77 @@ func (s *Storage) processBlock(ctx context.Context, blockNum uint64, blockRaw *d
 		TransactionCount: len(blockRaw.Transactions),
 		BaseFeePerGas:    util.DecimalFromU256(blockRaw.BaseFeePerGas),
 	}
+	timer := newBlockTimer(blockNum)
 	hash2ExtrinsicIndex, err := findOutSubstrateExecutedEvent(ctx, uint(blockNum), blockRaw)
 	if err != nil {
 		return err
 	}
-	var wg sync.WaitGroup
+	ethReceipts, err := FetchBlockReceipts(ctx, blockNum, blockRaw.Transactions)
+	if err != nil {
+		return err
+	}
+	timer.observe("fetch_receipts")
 
+	var (
+		transactions []Transaction
+		receipts     [][]TransactionReceipt
+		logs         []TransactionReceipt
+	)
+	for index := range blockRaw.Transactions {
+		ethTransaction := &blockRaw.Transactions[index]
+		transaction, transactionLogs := NewTransactionByExecuted(block.Timestamp, ethTransaction,
+			ethReceipts[strings.ToLower(ethTransaction.Hash)], hash2ExtrinsicIndex[ethTransaction.Hash])
+		// Confirm whether this transaction is to create a contract, contract must be saved before event logs processed
+		if transaction.Contract != "" {
+			_ = transaction.NewContract(ctx)
+		}
+		transactions = append(transactions, *transaction)
+		receipts = append(receipts, transactionLogs)
+		logs = append(logs, transactionLogs...)
+	}
+	timer.observe("build")
+
+	if err = s.saveTransactions(ctx, transactions, logs); err != nil {
+		return err
+	}
+	timer.observe("write")
+
+	var wg sync.WaitGroup
 	cp, _ := ants.NewPoolWithFunc(5, func(i interface{}) {
-		transaction := i.(dto.BlockTransaction)
-		// override contract address
 		defer wg.Done()
-
-		if e := s.CreateTransactionByExecuted(ctx, block.Timestamp, &transaction, hash2ExtrinsicIndex[transaction.Hash]); e != nil {
+		index := i.(int)
+		if e := transactions[index].afterSaved(ctx, receipts[index]); e != nil {
 			err = e
 		}
 	})
 	defer cp.Release()
-	for _, t := range blockRaw.Transactions {
+	for index := range transactions {
 		wg.Add(1)
-		_ = cp.Invoke(t)
+		_ = cp.Invoke(index)
 	}
 	wg.Wait()
 	if err != nil {
 		return err
 	}
-	return s.AddOrUpdateItem(ctx, block, []string{"block_num"}, "transaction_count").Error
+	if err = MatchEventSubscriptions(ctx, logs); err != nil {
+		return err
+	}
+	if err = ProcessNameServiceLogs(ctx, logs); err != nil {
+		return err
+	}
+	if err = RefreshGasStat(ctx, block); err != nil {
+		return err
+	}
+	if err = SubscriptionBlockProcessed(ctx, block.BlockNum); err != nil {
+		return err
+	}
+	timer.observe("after_saved")
+	// block row marks block processed, write it last so that failed block will be retried
+	if err = s.AddOrUpdateItem(ctx, block, []string{"block_num"}, "transaction_count").Error; err != nil {
+		return err
+	}
+	timer.done(len(transactions))
+	return nil
 }
 
 func GetBlockByNum(ctx context.Context, blockNum int) *EvmBlock {
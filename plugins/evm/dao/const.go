@@ -13,6 +13,11 @@ var (
 	Eip20Token                  = "erc20"
 	Eip721Token                 = "erc721"
 	Eip1155Token                = "erc1155"
+	EvmTrace                    = "evm_trace"
+	EvmNftMetadata              = "evm_nft_metadata"
+	EvmSubscription             = "evm_subscription"
+	EvmProxy                    = "evm_proxy"
+	EvmContract                 = "evm_contract"
 	NullAddress                 = "0x0000000000000000000000000000000000000000"
 	Create                      = "CREATE"
 )
@@ -9,8 +9,6 @@ import (
 	"github.com/itering/subscan/model"
 	evmABI "github.com/itering/subscan/plugins/evm/abi"
 	evmContract "github.com/itering/subscan/plugins/evm/contract"
-	"github.com/itering/subscan/plugins/evm/feature/delegateProxy"
-	"github.com/itering/subscan/share/web3"
 	"github.com/itering/subscan/util"
 	"regexp"
 	"strconv"
@@ -55,6 +53,12 @@ type Contract struct {
 	ProxyImplementation  string `json:"proxy_implementation" gorm:"size:64"`
 	ConstructorArguments string `json:"constructor_arguments" gorm:"type:string"`
 	DeployCodeHash       string `json:"deploy_code_hash" gorm:"size:70;index:deploy_code_hash;default:'';not null"`
+
+	// CodeHash revive code hash of PolkaVM contract
+	CodeHash       string          `json:"code_hash,omitempty" gorm:"size:70;index:code_hash"`
+	StorageDeposit decimal.Decimal `json:"storage_deposit" gorm:"default: 0;type:decimal(65);"`
+
+	Facets []string `json:"facets,omitempty" gorm:"-"`
 }
 
 type ContractSampleJson struct {
@@ -83,6 +87,8 @@ type ContractListJson struct {
 const (
 	VerifyTypeSingleFile   = "SingleFile"
 	VerifyStandardJsonFile = "StandardJson"
+	// VerifySimilarMatch verified by other contract with same deploy code hash
+	VerifySimilarMatch = "SimilarMatch"
 )
 
 func (c *Contract) TableName() string {
@@ -105,6 +111,9 @@ func (c *Contract) VerifySuccess(ctx context.Context, verifyRes *evmContract.Ver
 	if verifyRes.CreationBytecodeLength > 0 && len(c.CreationCode) >= verifyRes.CreationBytecodeLength {
 		c.CreationBytecode = c.CreationCode[:verifyRes.CreationBytecodeLength]
 	}
+	if verifyRes.ConstructorArguments != "" {
+		c.ConstructorArguments = verifyRes.ConstructorArguments
+	}
 	c.ContractName = input.FormatContractName()
 	c.SourceCode = input.Sources.AsString()
 	c.VerifyType = VerifyType
@@ -136,24 +145,17 @@ func (c *Contract) VerifySuccess(ctx context.Context, verifyRes *evmContract.Ver
 	}
 	c.afterVerify(ctx)
 
-	return sg.db.Model(Contract{}).Where("address = ?", c.Address).Updates(c).Error
+	if err := sg.db.Model(Contract{}).Where("address = ?", c.Address).Updates(c).Error; err != nil {
+		return err
+	}
+	// contracts with same deploy code hash are verified in background
+	return Publish(EvmContract, "similar", c.Address)
 }
 
 func (c *Contract) afterVerify(ctx context.Context) {
 	_ = c.fetchAbiMapping(context.Background())
 	// check it is proxy contract
-	if c.hasEvent(ctx, delegateProxy.EventUpgraded) {
-		var proxy delegateProxy.IDelegateProxy
-		if c.hasStorage(ctx, "implementation") {
-			proxy = delegateProxy.Init897(web3.RPC, c.Address)
-		} else {
-			proxy = delegateProxy.Init1967(web3.RPC, c.Address)
-		}
-		if implementation, _ := proxy.Implementation(ctx); implementation != "" {
-			c.ProxyImplementation = implementation
-			c.EipStandard = proxy.Standard()
-		}
-	}
+	c.detectProxy(ctx)
 }
 
 func setContractProxyImplementation(ctx context.Context, contractAddress, implementation string) {
@@ -195,7 +197,11 @@ func (t *Transaction) NewContract(ctx context.Context) error {
 		ExtrinsicIndex: t.ExtrinsicIndex,
 		Precompile:     t.Precompile,
 	}
-	return sg.AddOrUpdateItem(ctx, contract, []string{"address"}, "creation_code", "deploy_at", "block_num", "deployer", "extrinsic_index", "precompile").Error
+	if err := sg.AddOrUpdateItem(ctx, contract, []string{"address"}, "creation_code", "deploy_at", "block_num", "deployer", "extrinsic_index", "precompile").Error; err != nil {
+		return err
+	}
+	// runtime code is fetched by queue, block ingest never waits for eth_getCode
+	return Publish(EvmContract, "code", t.Contract)
 }
 
 func ContractAddr(ctx context.Context) (list []string) {
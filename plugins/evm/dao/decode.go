@@ -0,0 +1,241 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/itering/subscan/util"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+type DecodedParam struct {
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Indexed bool        `json:"indexed,omitempty"`
+	Value   interface{} `json:"value"`
+}
+
+type DecodedEvent struct {
+	Name      string         `json:"name"`
+	Signature string         `json:"signature"`
+	Params    []DecodedParam `json:"params"`
+}
+
+type DecodedMethod struct {
+	Name      string         `json:"name"`
+	Signature string         `json:"signature"`
+	MethodId  string         `json:"method_id"`
+	Params    []DecodedParam `json:"params"`
+}
+
+// AbiDecoder decode event log and input with contract verified abi, fallback to signature database(AbiMapping)
+// abi will be cached in decoder, create one decoder for each request
+type AbiDecoder struct {
+	contracts map[string]*abi.ABI
+	mappings  map[string]*abi.ABI
+}
+
+func NewAbiDecoder() *AbiDecoder {
+	return &AbiDecoder{contracts: make(map[string]*abi.ABI), mappings: make(map[string]*abi.ABI)}
+}
+
+func (d *AbiDecoder) contractAbi(ctx context.Context, address string) *abi.ABI {
+	if value, ok := d.contracts[address]; ok {
+		return value
+	}
+	var value *abi.ABI
+	if contract := ContractsByAddr(ctx, address); contract != nil {
+		value = contractMergedAbi(ctx, contract)
+	}
+	d.contracts[address] = value
+	return value
+}
+
+func (d *AbiDecoder) mappingAbi(ctx context.Context, id string) *abi.ABI {
+	if value, ok := d.mappings[id]; ok {
+		return value
+	}
+	value := mappingAbiValue(GetAbiMapping(ctx, id))
+	d.mappings[id] = value
+	return value
+}
+
+func mappingAbiValue(mapping *AbiMapping) *abi.ABI {
+	if mapping == nil {
+		return nil
+	}
+	return parseAbi([]byte(fmt.Sprintf("[%s]", mapping.AbiFunc)))
+}
+
+func parseAbi(raw []byte) *abi.ABI {
+	var value abi.ABI
+	if err := value.UnmarshalJSON(raw); err != nil {
+		return nil
+	}
+	return &value
+}
+
+// DecodeLog decode receipt topics and data
+func (d *AbiDecoder) DecodeLog(ctx context.Context, r *TransactionReceipt) *DecodedEvent {
+	topics := strings.Split(r.Topics, ",")
+	if len(topics) == 0 || topics[0] == "" {
+		return nil
+	}
+	if contractAbi := d.contractAbi(ctx, r.Address); contractAbi != nil {
+		if decoded := decodeEvent(contractAbi, topics, r.Data); decoded != nil {
+			return decoded
+		}
+	}
+	if mappingAbi := d.mappingAbi(ctx, eventMappingId(topics[0], len(topics))); mappingAbi != nil {
+		return decodeEvent(mappingAbi, topics, r.Data)
+	}
+	// mapping saved before keyed by topics count
+	if mappingAbi := d.mappingAbi(ctx, strings.ToLower(topics[0])); mappingAbi != nil {
+		return decodeEvent(mappingAbi, topics, r.Data)
+	}
+	return nil
+}
+
+// DecodeInput decode transaction input data
+func (d *AbiDecoder) DecodeInput(ctx context.Context, to, input string) *DecodedMethod {
+	data := util.HexToBytes(input)
+	if len(data) < 4 {
+		return nil
+	}
+	if to != "" {
+		if contractAbi := d.contractAbi(ctx, to); contractAbi != nil {
+			if decoded := decodeMethod(contractAbi, data); decoded != nil {
+				return decoded
+			}
+		}
+	}
+	if mappingAbi := d.mappingAbi(ctx, util.AddHex(util.BytesToHex(data[:4]))); mappingAbi != nil {
+		return decodeMethod(mappingAbi, data)
+	}
+	return nil
+}
+
+func decodeEvent(contractAbi *abi.ABI, topics []string, data string) *DecodedEvent {
+	event, err := contractAbi.EventByID(common.HexToHash(topics[0]))
+	if err != nil {
+		return nil
+	}
+	inputs := namedArguments(event.Inputs)
+	var indexed abi.Arguments
+	for _, input := range inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	// erc20 and erc721 Transfer has same signature, but indexed count is different
+	if len(indexed) != len(topics)-1 {
+		return nil
+	}
+	var hashes []common.Hash
+	for _, topic := range topics[1:] {
+		hashes = append(hashes, common.HexToHash(topic))
+	}
+	values := make(map[string]interface{})
+	if err = abi.ParseTopicsIntoMap(values, indexed, hashes); err != nil {
+		return nil
+	}
+	if err = inputs.UnpackIntoMap(values, util.HexToBytes(data)); err != nil {
+		return nil
+	}
+	decoded := DecodedEvent{Name: event.Name, Signature: event.Sig}
+	for _, input := range inputs {
+		decoded.Params = append(decoded.Params, DecodedParam{
+			Name:    input.Name,
+			Type:    input.Type.String(),
+			Indexed: input.Indexed,
+			Value:   formatAbiValue(values[input.Name]),
+		})
+	}
+	return &decoded
+}
+
+func decodeMethod(contractAbi *abi.ABI, data []byte) *DecodedMethod {
+	method, err := contractAbi.MethodById(data[:4])
+	if err != nil {
+		return nil
+	}
+	inputs := namedArguments(method.Inputs)
+	values := make(map[string]interface{})
+	if err = inputs.UnpackIntoMap(values, data[4:]); err != nil {
+		return nil
+	}
+	decoded := DecodedMethod{Name: method.Name, Signature: method.Sig, MethodId: util.AddHex(util.BytesToHex(method.ID))}
+	for _, input := range inputs {
+		decoded.Params = append(decoded.Params, DecodedParam{
+			Name:  input.Name,
+			Type:  input.Type.String(),
+			Value: formatAbiValue(values[input.Name]),
+		})
+	}
+	return &decoded
+}
+
+// namedArguments unnamed argument will be named as arg{index}
+func namedArguments(args abi.Arguments) abi.Arguments {
+	named := make(abi.Arguments, len(args))
+	for index, arg := range args {
+		if arg.Name == "" {
+			arg.Name = fmt.Sprintf("arg%d", index)
+		}
+		named[index] = arg
+	}
+	return named
+}
+
+// formatAbiValue convert abi value to json friendly value, big int as string, bytes and address as hex
+func formatAbiValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case *big.Int:
+		return v.String()
+	case common.Address:
+		return strings.ToLower(v.Hex())
+	case common.Hash:
+		return v.Hex()
+	case []byte:
+		return util.AddHex(util.BytesToHex(v))
+	case string, bool:
+		return v
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Array:
+		// bytesN
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return util.AddHex(util.BytesToHex(b))
+		}
+		fallthrough
+	case reflect.Slice:
+		list := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			list = append(list, formatAbiValue(rv.Index(i).Interface()))
+		}
+		return list
+	case reflect.Struct:
+		// tuple
+		tuple := make(map[string]interface{})
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			name := field.Tag.Get("json")
+			if name == "" {
+				name = field.Name
+			}
+			tuple[name] = formatAbiValue(rv.Field(i).Interface())
+		}
+		return tuple
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprintf("%d", value)
+	}
+	return value
+}
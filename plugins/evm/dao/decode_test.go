@@ -0,0 +1,75 @@
+package dao
+
+import (
+	evmAbi "github.com/itering/subscan/plugins/evm/abi"
+	"github.com/itering/subscan/util"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_DecodeEvent(t *testing.T) {
+	erc20Abi := parseAbi([]byte(evmAbi.Erc20))
+	assert.NotNil(t, erc20Abi)
+	topics := []string{
+		"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+		"0x0000000000000000000000002cac6e4b11d6b58f6d3c1c9d5fe8faa89f60e5a2",
+		"0x00000000000000000000000066a1cba4138cbb5bcc3e3af1b10c7fd56b6aac0b",
+	}
+	decoded := decodeEvent(erc20Abi, topics, "0x00000000000000000000000000000000000000000000000000000000000003e8")
+	assert.NotNil(t, decoded)
+	assert.Equal(t, "Transfer", decoded.Name)
+	assert.Len(t, decoded.Params, 3)
+	assert.Equal(t, "0x2cac6e4b11d6b58f6d3c1c9d5fe8faa89f60e5a2", decoded.Params[0].Value)
+	assert.True(t, decoded.Params[0].Indexed)
+	assert.Equal(t, "1000", decoded.Params[2].Value)
+
+	// erc721 Transfer, indexed count mismatch
+	assert.Nil(t, decodeEvent(erc20Abi, append(topics, "0x01"), ""))
+
+	// transfer(address,uint256)
+	method := decodeMethod(erc20Abi, util.HexToBytes("0xa9059cbb00000000000000000000000066a1cba4138cbb5bcc3e3af1b10c7fd56b6aac0b00000000000000000000000000000000000000000000000000000000000003e8"))
+	assert.NotNil(t, method)
+	assert.Equal(t, "transfer", method.Name)
+	assert.Equal(t, "0xa9059cbb", method.MethodId)
+	assert.Equal(t, "1000", method.Params[1].Value)
+
+	assert.Equal(t, "0x0102", formatAbiValue([2]byte{1, 2}))
+	assert.Equal(t, []interface{}{"1", "2"}, formatAbiValue([]uint64{1, 2}))
+}
+
+func Test_AbiMappingsTransfer(t *testing.T) {
+	mappings := make(map[string]AbiMapping)
+	erc721Transfer := `[{"anonymous":false,"type":"event","name":"Transfer","inputs":[{"indexed":true,"name":"from","type":"address"},` +
+		`{"indexed":true,"name":"to","type":"address"},{"indexed":true,"name":"tokenId","type":"uint256"}]}]`
+	for _, raw := range []string{evmAbi.Erc20, erc721Transfer} {
+		list, err := AbiMappings([]byte(raw))
+		assert.NoError(t, err)
+		for _, mapping := range list {
+			mappings[mapping.Id] = mapping
+		}
+	}
+	decode := func(topics []string, data string) *DecodedEvent {
+		mapping, ok := mappings[eventMappingId(topics[0], len(topics))]
+		if !ok {
+			return nil
+		}
+		return decodeEvent(mappingAbiValue(&mapping), topics, data)
+	}
+	transfer := "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	from := "0x0000000000000000000000002cac6e4b11d6b58f6d3c1c9d5fe8faa89f60e5a2"
+	to := "0x00000000000000000000000066a1cba4138cbb5bcc3e3af1b10c7fd56b6aac0b"
+
+	// erc20 Transfer(address indexed from, address indexed to, uint256 value)
+	erc20 := decode([]string{transfer, from, to}, "0x00000000000000000000000000000000000000000000000000000000000003e8")
+	assert.NotNil(t, erc20)
+	assert.Equal(t, "Transfer", erc20.Name)
+	assert.False(t, erc20.Params[2].Indexed)
+	assert.Equal(t, "1000", erc20.Params[2].Value)
+
+	// erc721 Transfer(address indexed from, address indexed to, uint256 indexed tokenId)
+	erc721 := decode([]string{transfer, from, to, "0x0000000000000000000000000000000000000000000000000000000000000007"}, "0x")
+	assert.NotNil(t, erc721)
+	assert.Equal(t, "Transfer", erc721.Name)
+	assert.True(t, erc721.Params[2].Indexed)
+	assert.Equal(t, "7", erc721.Params[2].Value)
+}
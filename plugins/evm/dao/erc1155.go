@@ -0,0 +1,206 @@
+package dao
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"github.com/itering/subscan/model"
+	"github.com/itering/subscan/plugins/evm/feature/erc1155"
+	"github.com/itering/subscan/share/web3"
+	"github.com/itering/subscan/util"
+	"github.com/itering/subscan/util/mq"
+	"github.com/shopspring/decimal"
+	"strings"
+)
+
+// ERC1155Item token id of erc1155 contract
+type ERC1155Item struct {
+	Id          string          `json:"-" gorm:"primaryKey;size:100"`
+	Contract    string          `json:"contract" gorm:"index:contract_token_id;size:100"`
+	TokenId     string          `json:"token_id" gorm:"size:255;index:contract_token_id,length:100"`
+	Holders     uint            `json:"holders" gorm:"size:32"`
+	TotalSupply decimal.Decimal `json:"total_supply" gorm:"default: 0;type:decimal(65);"`
+	Uri         string          `json:"uri" gorm:"type:text"`
+	Metadata    Metadata        `json:"metadata" gorm:"type:json"`
+	StorageUrl  string          `json:"storage_url" gorm:"type:text"`
+}
+
+func (t *ERC1155Item) TableName() string {
+	return "evm_erc1155_items"
+}
+
+// ERC1155Holder balance of (contract, token id, holder)
+type ERC1155Holder struct {
+	Id       string          `json:"-" gorm:"primaryKey;size:100;index:balance_id,priority:2"`
+	Contract string          `json:"contract" gorm:"index:contract_token_id;index:contract_hold;size:100"`
+	Holder   string          `json:"holder" gorm:"index:hold;index:contract_hold;size:100"`
+	TokenId  string          `json:"token_id" gorm:"size:255;index:contract_token_id,length:100"`
+	Balance  decimal.Decimal `json:"balance" gorm:"default: 0;type:decimal(65);index:balance_id,priority:1"`
+}
+
+func (t ERC1155Holder) Cursor() string {
+	return util.Base64Encode(fmt.Sprintf("%s_%s", t.Balance.String(), t.Id))
+}
+
+func (t *ERC1155Holder) TableName() string {
+	return "evm_erc1155_holders"
+}
+
+type ERC1155HolderJson struct {
+	ERC1155Holder
+	Metadata   *Metadata `json:"metadata,omitempty"`
+	StorageUrl string    `json:"storage_url"`
+}
+
+func erc1155ItemId(contract, tokenId string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(fmt.Sprintf("%s%s", contract, tokenId))))
+}
+
+func erc1155HolderId(contract, holder, tokenId string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(fmt.Sprintf("%s%s%s", contract, holder, tokenId))))
+}
+
+// ProcessErc1155 process TransferSingle, TransferBatch and URI event
+func (t *TransactionReceipt) ProcessErc1155(ctx context.Context) error {
+	token := TouchToken(ctx, t.Address, Eip1155Token)
+	topics := strings.Split(t.Topics, ",")
+
+	if util.TrimHex(t.MethodHash) == erc1155.EventURI {
+		// URI(string _value, uint256 indexed _id)
+		if len(topics) < 2 {
+			return nil
+		}
+		tokenId := util.U256(topics[1]).String()
+		sg.db.WithContext(ctx).Model(ERC1155Item{}).Where("id = ?", erc1155ItemId(token.Contract, tokenId)).
+			Update("uri", util.AbiStringDecoder(t.Data))
+		// uri changed, cached metadata is stale
+		return PublishNftMetadata(token.Contract, tokenId, true)
+	}
+
+	// TransferSingle/TransferBatch(address indexed _operator, address indexed _from, address indexed _to, ...)
+	if len(topics) < 4 {
+		return nil
+	}
+	var (
+		tokenIds []string
+		values   []decimal.Decimal
+	)
+	switch util.TrimHex(t.MethodHash) {
+	case erc1155.EventTransferSingle:
+		tokenId, value := erc1155.DecodeTransferSingle(t.Data)
+		if tokenId == "" {
+			return nil
+		}
+		tokenIds, values = []string{tokenId}, []decimal.Decimal{value}
+	case erc1155.EventTransferBatch:
+		var err error
+		if tokenIds, values, err = erc1155.DecodeTransferBatch(t.Data); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	var transfers []TokensTransfers
+	for index := range tokenIds {
+		transfers = append(transfers, TokensTransfers{
+			TransferId: t.Id,
+			BatchIndex: uint(index),
+			Contract:   token.Contract,
+			Hash:       t.TransactionHash,
+			CreateAt:   t.BlockTimestamp,
+			Sender:     RemoveAddressPadded(topics[2]),
+			Receiver:   RemoveAddressPadded(topics[3]),
+			Value:      values[index],
+			TokenId:    tokenIds[index],
+			Category:   TransferCategoryErc1155,
+		})
+	}
+	if len(transfers) == 0 {
+		return nil
+	}
+	query := sg.db.Scopes(model.IgnoreDuplicate).Create(&transfers)
+	if query.RowsAffected > 0 {
+		token.incrTransferCount(ctx, int(query.RowsAffected))
+		if mq.Instant != nil {
+			for _, transfer := range transfers {
+				_ = Publish(Eip1155Token, "balance", []string{token.Contract, transfer.Sender, transfer.TokenId})
+				_ = Publish(Eip1155Token, "balance", []string{token.Contract, transfer.Receiver, transfer.TokenId})
+			}
+		}
+		return nil
+	}
+	return query.Error
+}
+
+// RefreshErc1155Holder refresh balance of token id, then item supply and token holders
+func RefreshErc1155Holder(ctx context.Context, contract, address, tokenId string) error {
+	token := GetTokenByContract(ctx, contract)
+	if token == nil || address == NullAddress {
+		return nil
+	}
+	balance, err := erc1155.Init(web3.RPC, contract).BalanceOfWithTokenId(ctx, address, tokenId)
+	if err != nil {
+		return err
+	}
+	if balance.IsNegative() {
+		return nil
+	}
+	id := erc1155HolderId(contract, address, tokenId)
+	if balance.IsZero() {
+		sg.db.WithContext(ctx).Where("id = ?", id).Delete(ERC1155Holder{})
+	} else {
+		q := sg.AddOrUpdateItem(ctx, &ERC1155Holder{Id: id, Contract: contract, Holder: address, TokenId: tokenId, Balance: balance}, []string{"id"}, "balance")
+		if q.Error != nil {
+			return q.Error
+		}
+	}
+	if err = token.RefreshErc1155Item(ctx, tokenId); err != nil {
+		return err
+	}
+	return RefreshHolder(ctx, contract, address, Eip1155Token)
+}
+
+// RefreshErc1155Item refresh holders and supply of token id, metadata will be fetched when item created
+func (c *Token) RefreshErc1155Item(ctx context.Context, tokenId string) error {
+	var stat struct {
+		Holders     uint
+		TotalSupply decimal.Decimal
+	}
+	sg.db.WithContext(ctx).Model(ERC1155Holder{}).Select("count(*) as holders, coalesce(sum(balance), 0) as total_supply").
+		Where("contract = ?", c.Contract).Where("token_id = ?", tokenId).Scan(&stat)
+	item := ERC1155Item{
+		Id:          erc1155ItemId(c.Contract, tokenId),
+		Contract:    c.Contract,
+		TokenId:     tokenId,
+		Holders:     stat.Holders,
+		TotalSupply: stat.TotalSupply,
+	}
+	q := sg.AddOrUpdateItem(ctx, &item, []string{"id"}, "holders", "total_supply")
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 1 {
+		// new item, total supply of token is token id count
+		var count int64
+		sg.db.WithContext(ctx).Model(ERC1155Item{}).Where("contract = ?", c.Contract).Count(&count)
+		sg.db.WithContext(ctx).Model(Token{}).Where("contract = ?", c.Contract).Update("total_supply", count)
+		return PublishNftMetadata(c.Contract, tokenId, false)
+	}
+	return nil
+}
+
+// ERC1155TokenIdsCount count of token ids held by account
+func ERC1155TokenIdsCount(ctx context.Context, contract, accountId string) int64 {
+	var count int64
+	sg.db.WithContext(ctx).Model(ERC1155Holder{}).Where("contract = ?", contract).Where("holder = ?", accountId).Where("balance > 0").Count(&count)
+	return count
+}
+
+func GetErc1155Item(ctx context.Context, contract, tokenId string) *ERC1155Item {
+	var item ERC1155Item
+	if query := sg.db.WithContext(ctx).Where("id = ?", erc1155ItemId(contract, tokenId)).First(&item); query.Error != nil {
+		return nil
+	}
+	return &item
+}
@@ -7,13 +7,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/itering/subscan/plugins/evm/feature/erc1155"
 	"github.com/itering/subscan/plugins/evm/feature/erc721"
 	"github.com/itering/subscan/share/web3"
 	"github.com/itering/subscan/util"
-	"github.com/itering/subscan/util/ipfs"
 	"github.com/shopspring/decimal"
-	"strings"
 
 	"gorm.io/gorm"
 )
@@ -100,7 +97,7 @@ func (c *Token) RefreshErc721Holders(ctx context.Context, tokenId string) error
 	}
 	id := fmt.Sprintf("%x", sha1.Sum([]byte(fmt.Sprintf("%s%s%s", c.Contract, to, tokenId))))
 	defer func() {
-		_ = c.refreshGetMetadata(context.Background(), c.Contract, tokenId)
+		_ = PublishNftMetadata(c.Contract, tokenId, false)
 	}()
 	if collectible == nil {
 		// refresh nft metadata
@@ -188,79 +185,3 @@ func RefreshBaseTokenUri(ctx context.Context, contract string) (string, error) {
 	sg.db.Model(Token{}).Where("contract = ?", contract).Update("base_token_uri", uri)
 	return uri, nil
 }
-
-func (c *Token) GetMetadata(ctx context.Context, contract, tokenId string) (*Metadata, string, error) {
-	var (
-		tokenUrl string
-		err      error
-	)
-	if c.BaseTokenUri != "" {
-		tokenUrl = fmt.Sprintf("%s%s", c.BaseTokenUri, tokenId)
-	} else {
-		// get token uri
-		if c.Category == Eip721Token {
-			ERC721 := erc721.Init(web3.RPC, contract)
-			tokenUrl, err = ERC721.TokenURI(ctx, tokenId)
-			if tokenUrl == "" {
-				return nil, "", err
-			}
-		} else if c.Category == Eip1155Token {
-			ERC1155 := erc1155.Init(web3.RPC, contract)
-			tokenUrl, err = ERC1155.Uri(ctx, tokenId)
-			if tokenUrl == "" {
-				return nil, "", err
-			}
-			if strings.Contains(tokenUrl, "{id}") {
-				tokenUrl = strings.ReplaceAll(tokenUrl, "{id}", tokenId)
-			}
-		} else {
-			return nil, "", fmt.Errorf("unsupported token category %s", c.Category)
-		}
-	}
-
-	var (
-		metadata Metadata
-		data     []byte
-	)
-	if strings.HasPrefix("/", tokenUrl) {
-		tokenUrl = tokenUrl[1:]
-	}
-	// ipfs,ar,http,base64 metadata json
-	switch {
-	case strings.HasPrefix(tokenUrl, "ipfs://") || strings.HasPrefix(tokenUrl, "https://ipfs.io"):
-		data, err = ipfs.OpenFile(ctx, ipfs.TrimMetadataUri(tokenUrl))
-	case strings.HasPrefix(tokenUrl, "ar://"):
-		data, err = ipfs.OpenArFile(ctx, ipfs.TrimMetadataUri(tokenUrl))
-		// ignore localhost/127.0.0.1
-	case strings.HasPrefix(tokenUrl, "http://localhost") || strings.HasPrefix(tokenUrl, "https://localhost") || strings.Contains(tokenUrl, "127.0.0.1"):
-		return nil, "", nil
-	case strings.HasPrefix(tokenUrl, "http") || strings.HasPrefix(tokenUrl, "https"):
-		data, err = util.HttpGet(ctx, tokenUrl)
-	case strings.HasPrefix(tokenUrl, "data:application/json;base64,"):
-		raw := util.Base64Decode(strings.ReplaceAll(tokenUrl, "data:application/json;base64,", ""))
-		data = []byte(raw)
-	case strings.HasPrefix(tokenUrl, "data:application/json;utf8,"):
-		data = []byte(strings.ReplaceAll(tokenUrl, "data:application/json;utf8,", ""))
-	case strings.HasPrefix(tokenUrl, "did:dkg"):
-		return nil, "", nil
-	default:
-		return nil, "", fmt.Errorf("unsupported token uri %s %s %s", tokenUrl, contract, tokenId)
-	}
-
-	if err != nil {
-		return nil, "", err
-	}
-	if util.UnmarshalAny(&metadata, data) == nil {
-		return &metadata, metadata.Image, nil
-	}
-	return nil, "", err
-}
-
-func (c *Token) refreshGetMetadata(ctx context.Context, contract string, tokenId string) error {
-	metadata, storageUri, err := c.GetMetadata(ctx, contract, tokenId)
-	if metadata != nil {
-		sg.db.Model(Erc721Holders{}).Where("contract = ?", c.Contract).Where("token_id =?", tokenId).
-			UpdateColumns(map[string]interface{}{"metadata": metadata, "storage_url": storageUri})
-	}
-	return err
-}
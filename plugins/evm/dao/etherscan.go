@@ -0,0 +1,268 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/itering/subscan/pkg/go-web3/complex/types"
+	"github.com/itering/subscan/pkg/go-web3/dto"
+	"github.com/itering/subscan/share/web3"
+	"github.com/itering/subscan/util"
+	"github.com/itering/substrate-api-rpc/rpc"
+	"github.com/shopspring/decimal"
+	"strings"
+)
+
+var ErrBlockPassed = errors.New("Error! Block number already pass")
+
+// blockTimeSample recent blocks used to estimate average block time
+const blockTimeSample = 100
+
+func decimalToHex(d decimal.Decimal) string {
+	return util.AddHex(d.BigInt().Text(16))
+}
+
+// API_EthSupply total issuance of native token
+func (a *ApiSrv) API_EthSupply(_ context.Context) (decimal.Decimal, error) {
+	raw, err := rpc.ReadStorage(nil, "Balances", "TotalIssuance", "")
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return raw.ToDecimal(), nil
+}
+
+func (a *ApiSrv) API_TokenSupply(ctx context.Context, contract string) *decimal.Decimal {
+	var token Token
+	if q := sg.db.WithContext(ctx).Where("contract = ?", contract).First(&token); q.Error != nil {
+		return nil
+	}
+	return &token.TotalSupply
+}
+
+func (a *ApiSrv) API_TokenBalance(ctx context.Context, contract, holder string) decimal.Decimal {
+	var tokenHolder TokenHolder
+	sg.db.WithContext(ctx).Where("contract = ?", contract).Where("holder = ?", holder).First(&tokenHolder)
+	return tokenHolder.Balance
+}
+
+// API_BlockNoByTime closest block before or after timestamp
+func (a *ApiSrv) API_BlockNoByTime(ctx context.Context, timestamp uint, closest string) *uint64 {
+	var block EvmBlock
+	q := sg.db.WithContext(ctx).Model(EvmBlock{})
+	if closest == "after" {
+		q = q.Where("timestamp >= ?", timestamp).Order("block_num asc")
+	} else {
+		q = q.Where("timestamp <= ?", timestamp).Order("block_num desc")
+	}
+	if q.Take(&block).Error != nil {
+		return nil
+	}
+	return &block.BlockNum
+}
+
+type EtherscanBlockRewardRes struct {
+	BlockNumber          string        `json:"blockNumber"`
+	TimeStamp            string        `json:"timeStamp"`
+	BlockMiner           string        `json:"blockMiner"`
+	BlockReward          string        `json:"blockReward"`
+	Uncles               []interface{} `json:"uncles"`
+	UncleInclusionReward string        `json:"uncleInclusionReward"`
+}
+
+// API_BlockReward block author has no issuance reward on substrate evm, reward is the sum of transaction fees
+func (a *ApiSrv) API_BlockReward(ctx context.Context, blockNum uint64) *EtherscanBlockRewardRes {
+	block := GetBlockByNum(ctx, int(blockNum))
+	if block == nil {
+		return nil
+	}
+	var reward decimal.Decimal
+	sg.db.WithContext(ctx).Model(Transaction{}).Select("coalesce(sum(gas_used * effective_gas_price), 0)").
+		Where("block_num = ?", blockNum).Scan(&reward)
+	return &EtherscanBlockRewardRes{
+		BlockNumber:          fmt.Sprintf("%d", block.BlockNum),
+		TimeStamp:            fmt.Sprintf("%d", block.Timestamp),
+		BlockMiner:           util.IfEmptyElse(block.Miner, block.Author),
+		BlockReward:          reward.String(),
+		Uncles:               []interface{}{},
+		UncleInclusionReward: "0",
+	}
+}
+
+type EtherscanBlockCountdownRes struct {
+	CurrentBlock      string `json:"CurrentBlock"`
+	CountdownBlock    string `json:"CountdownBlock"`
+	RemainingBlock    string `json:"RemainingBlock"`
+	EstimateTimeInSec string `json:"EstimateTimeInSec"`
+}
+
+// averageBlockTime blocks order by block_num desc
+func averageBlockTime(blocks []EvmBlock) decimal.Decimal {
+	if len(blocks) < 2 {
+		return decimal.Zero
+	}
+	latest, oldest := blocks[0], blocks[len(blocks)-1]
+	if latest.BlockNum <= oldest.BlockNum || latest.Timestamp <= oldest.Timestamp {
+		return decimal.Zero
+	}
+	return decimal.NewFromInt(int64(latest.Timestamp - oldest.Timestamp)).Div(decimal.NewFromInt(int64(latest.BlockNum - oldest.BlockNum)))
+}
+
+func blockCountdown(blocks []EvmBlock, target uint64) (*EtherscanBlockCountdownRes, error) {
+	if len(blocks) == 0 || target <= blocks[0].BlockNum {
+		return nil, ErrBlockPassed
+	}
+	remaining := target - blocks[0].BlockNum
+	return &EtherscanBlockCountdownRes{
+		CurrentBlock:      fmt.Sprintf("%d", blocks[0].BlockNum),
+		CountdownBlock:    fmt.Sprintf("%d", target),
+		RemainingBlock:    fmt.Sprintf("%d", remaining),
+		EstimateTimeInSec: averageBlockTime(blocks).Mul(decimal.NewFromInt(int64(remaining))).Round(1).String(),
+	}, nil
+}
+
+func (a *ApiSrv) API_BlockCountdown(ctx context.Context, blockNum uint64) (*EtherscanBlockCountdownRes, error) {
+	var blocks []EvmBlock
+	sg.db.WithContext(ctx).Model(EvmBlock{}).Select("block_num,timestamp").Order("block_num desc").Limit(blockTimeSample).Find(&blocks)
+	return blockCountdown(blocks, blockNum)
+}
+
+type EtherscanProxyTxnRes struct {
+	BlockHash            string  `json:"blockHash"`
+	BlockNumber          string  `json:"blockNumber"`
+	From                 string  `json:"from"`
+	Gas                  string  `json:"gas"`
+	GasPrice             string  `json:"gasPrice"`
+	MaxFeePerGas         string  `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string  `json:"maxPriorityFeePerGas,omitempty"`
+	Hash                 string  `json:"hash"`
+	Input                string  `json:"input"`
+	Nonce                string  `json:"nonce"`
+	To                   *string `json:"to"`
+	TransactionIndex     string  `json:"transactionIndex"`
+	Value                string  `json:"value"`
+	Type                 string  `json:"type"`
+	V                    string  `json:"v"`
+	R                    string  `json:"r"`
+	S                    string  `json:"s"`
+}
+
+// proxyTxnRes json-rpc format of indexed transaction
+func (t *Transaction) proxyTxnRes(blockHash string) EtherscanProxyTxnRes {
+	res := EtherscanProxyTxnRes{
+		BlockHash:        blockHash,
+		BlockNumber:      util.IntToHexNumber(uint64(t.BlockNum)),
+		From:             t.FromAddress,
+		Gas:              decimalToHex(t.GasLimit),
+		GasPrice:         decimalToHex(t.GasPrice),
+		Hash:             t.Hash,
+		Input:            util.IfEmptyElse(t.InputData, "0x"),
+		Nonce:            util.IntToHexNumber(uint64(t.Nonce)),
+		TransactionIndex: util.IntToHexNumber(t.TransactionIndex),
+		Value:            decimalToHex(t.Value),
+		Type:             util.IntToHexNumber(uint64(t.TxnType)),
+		V:                util.IntToHexNumber(uint64(t.V)),
+		R:                t.R,
+		S:                t.S,
+	}
+	// max_priority_fee_per_gas default -1 means legacy transaction
+	if !t.MaxPriorityFeePerGas.IsNegative() && t.MaxFeePerGas.IsPositive() {
+		res.MaxFeePerGas = decimalToHex(t.MaxFeePerGas)
+		res.MaxPriorityFeePerGas = decimalToHex(t.MaxPriorityFeePerGas)
+	}
+	if t.ToAddress != "" {
+		res.To = &t.ToAddress
+	}
+	return res
+}
+
+func (a *ApiSrv) API_ProxyBlockNumber(ctx context.Context) string {
+	return util.IntToHexNumber(uint64(latestBlockNum(ctx)))
+}
+
+func (a *ApiSrv) API_ProxyTransaction(ctx context.Context, hash string) *EtherscanProxyTxnRes {
+	transaction := GetTransactionByHash(ctx, hash)
+	if transaction == nil {
+		return nil
+	}
+	var blockHash string
+	if block := GetBlockByNum(ctx, int(transaction.BlockNum)); block != nil {
+		blockHash = block.BlockHash
+	}
+	res := transaction.proxyTxnRes(blockHash)
+	return &res
+}
+
+type EtherscanProxyBlockRes struct {
+	BaseFeePerGas    string        `json:"baseFeePerGas,omitempty"`
+	Difficulty       string        `json:"difficulty"`
+	ExtraData        string        `json:"extraData"`
+	GasLimit         string        `json:"gasLimit"`
+	GasUsed          string        `json:"gasUsed"`
+	Hash             string        `json:"hash"`
+	LogsBloom        string        `json:"logsBloom"`
+	Miner            string        `json:"miner"`
+	Number           string        `json:"number"`
+	ParentHash       string        `json:"parentHash"`
+	ReceiptsRoot     string        `json:"receiptsRoot"`
+	Sha3Uncles       string        `json:"sha3Uncles"`
+	Size             string        `json:"size"`
+	StateRoot        string        `json:"stateRoot"`
+	Timestamp        string        `json:"timestamp"`
+	TotalDifficulty  string        `json:"totalDifficulty"`
+	Transactions     []interface{} `json:"transactions"`
+	TransactionsRoot string        `json:"transactionsRoot"`
+	Uncles           []string      `json:"uncles"`
+}
+
+// API_ProxyBlock json-rpc format of indexed block, transactions are hashes or full objects
+func (a *ApiSrv) API_ProxyBlock(ctx context.Context, blockNum uint64, full bool) *EtherscanProxyBlockRes {
+	block := GetBlockByNum(ctx, int(blockNum))
+	if block == nil {
+		return nil
+	}
+	res := EtherscanProxyBlockRes{
+		Difficulty:       decimalToHex(block.Difficulty),
+		ExtraData:        util.IfEmptyElse(block.ExtraData, "0x"),
+		GasLimit:         decimalToHex(block.GasLimit),
+		GasUsed:          decimalToHex(block.GasUsed),
+		Hash:             block.BlockHash,
+		LogsBloom:        block.LogsBloom,
+		Miner:            util.IfEmptyElse(block.Miner, block.Author),
+		Number:           util.IntToHexNumber(block.BlockNum),
+		ParentHash:       block.ParentHash,
+		ReceiptsRoot:     block.ReceiptsRoot,
+		Sha3Uncles:       block.Sha3Uncles,
+		Size:             decimalToHex(block.BlockSize),
+		StateRoot:        block.StateRoot,
+		Timestamp:        util.IntToHexNumber(uint64(block.Timestamp)),
+		TotalDifficulty:  decimalToHex(block.TotalDifficulty),
+		Transactions:     []interface{}{},
+		TransactionsRoot: block.TransactionsRoot,
+		Uncles:           []string{},
+	}
+	if !block.BaseFeePerGas.IsZero() {
+		res.BaseFeePerGas = decimalToHex(block.BaseFeePerGas)
+	}
+	var transactions []Transaction
+	sg.db.WithContext(ctx).Model(Transaction{}).Where("block_num = ?", blockNum).Order("transaction_index asc").Find(&transactions)
+	for index := range transactions {
+		if full {
+			res.Transactions = append(res.Transactions, transactions[index].proxyTxnRes(block.BlockHash))
+		} else {
+			res.Transactions = append(res.Transactions, transactions[index].Hash)
+		}
+	}
+	return &res
+}
+
+// API_ProxyCall eth_call is proxied to rpc node, executed at the latest state
+func (a *ApiSrv) API_ProxyCall(ctx context.Context, to, data string) (string, error) {
+	result, err := web3.RPC.Eth.Call(ctx, &dto.TransactionParameters{
+		To:   strings.ToLower(to),
+		Data: types.ComplexString(data),
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.ToString()
+}
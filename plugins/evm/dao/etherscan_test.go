@@ -0,0 +1,46 @@
+package dao
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_BlockCountdown(t *testing.T) {
+	blocks := []EvmBlock{{BlockNum: 110, Timestamp: 1120}, {BlockNum: 105, Timestamp: 1060}, {BlockNum: 100, Timestamp: 1000}}
+	assert.Equal(t, "12", averageBlockTime(blocks).String())
+	assert.True(t, averageBlockTime(blocks[:1]).IsZero())
+
+	countdown, err := blockCountdown(blocks, 120)
+	assert.NoError(t, err)
+	assert.Equal(t, "110", countdown.CurrentBlock)
+	assert.Equal(t, "10", countdown.RemainingBlock)
+	assert.Equal(t, "120", countdown.EstimateTimeInSec)
+
+	_, err = blockCountdown(blocks, 110)
+	assert.ErrorIs(t, err, ErrBlockPassed)
+	_, err = blockCountdown(nil, 120)
+	assert.ErrorIs(t, err, ErrBlockPassed)
+}
+
+func Test_ProxyTxnRes(t *testing.T) {
+	txn := Transaction{Hash: "0x01", BlockNum: 255, FromAddress: "0xa", Nonce: 16, GasLimit: decimal.NewFromInt(21000),
+		GasPrice: decimal.New(1, 9), Value: decimal.New(1, 18), MaxPriorityFeePerGas: decimal.NewFromInt(-1), TxnType: 0, V: 27}
+	res := txn.proxyTxnRes("0xblock")
+	assert.Equal(t, "0xff", res.BlockNumber)
+	assert.Equal(t, "0x5208", res.Gas)
+	assert.Equal(t, "0xde0b6b3a7640000", res.Value)
+	assert.Equal(t, "0x", res.Input)
+	assert.Equal(t, "0x1b", res.V)
+	// contract creation and legacy transaction
+	assert.Nil(t, res.To)
+	assert.Empty(t, res.MaxFeePerGas)
+
+	txn.ToAddress, txn.TxnType = "0xb", 2
+	txn.MaxPriorityFeePerGas, txn.MaxFeePerGas = decimal.New(1, 9), decimal.New(2, 9)
+	res = txn.proxyTxnRes("0xblock")
+	assert.Equal(t, "0xb", *res.To)
+	assert.Equal(t, "0x2", res.Type)
+	assert.Equal(t, "0x77359400", res.MaxFeePerGas)
+	assert.Equal(t, "0x3b9aca00", res.MaxPriorityFeePerGas)
+}
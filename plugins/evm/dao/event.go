@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/itering/subscan/plugins/evm/abi"
 	"github.com/itering/subscan/plugins/evm/feature/delegateProxy"
+	"github.com/itering/subscan/plugins/evm/feature/erc1155"
 	"github.com/itering/subscan/plugins/evm/feature/erc20"
 	"github.com/itering/subscan/plugins/evm/feature/erc721"
 	"github.com/itering/subscan/share/web3"
@@ -46,21 +47,43 @@ func (t TransactionReceipt) EventProcess(ctx context.Context) error {
 		address := util.AddHex(abi.DecodeAddress(log2[1]))
 		_ = Publish("erc20", "balance", []string{t.Address, address})
 
+	// erc20 allowance, erc721 Approval has 4 topics
+	case erc20.EventApproval:
+		if len(strings.Split(t.Topics, ",")) != 3 {
+			return nil
+		}
+		if token := GetTokenByContract(ctx, t.Address); token == nil || token.Category == Eip20Token {
+			_ = Publish(Eip20Token, "approval", t)
+		}
+
+	// erc721/erc1155 operator approval
+	case erc721.EventApprovalForAll:
+		if token := GetTokenByContract(ctx, t.Address); token != nil && token.Category != Eip20Token {
+			_ = Publish(token.Category, "approval", t)
+		}
+
 	// proxy
 	case delegateProxy.EventUpgraded:
 		if topics := strings.Split(t.Topics, ","); len(topics) > 1 {
 			setContractProxyImplementation(ctx, t.Address, util.AddHex(abi.DecodeAddress(topics[1])))
 		}
+		// proxy initialized after deployed
+		if contract := GetContract(ctx, t.Address); contract != nil && contract.ProxyImplementation == "" {
+			_ = Publish(EvmProxy, "detect", t.Address)
+		}
+	case delegateProxy.EventBeaconUpgraded, delegateProxy.EventDiamondCut:
+		_ = Publish(EvmProxy, "detect", t.Address)
 
-		// case erc1155.EventTransferBatch, erc1155.EventTransferSingle, erc1155.EventURI:
-		// 	if token := GetTokenByContract(ctx, t.Address); token == nil {
-		// 		erc1155token := erc1155.Init(web3.RPC, t.Address)
-		// 		if result, _ := erc1155token.SupportsInterface(ctx); result {
-		// 			return Publish(Eip1155Token, Eip1155Token, t)
-		// 		}
-		// 	} else if token.Category == Eip1155Token {
-		// 		_ = Publish(token.Category, Eip1155Token, t)
-		// 	}
+	// erc1155, register token by supportsInterface
+	case erc1155.EventTransferBatch, erc1155.EventTransferSingle, erc1155.EventURI:
+		if token := GetTokenByContract(ctx, t.Address); token == nil {
+			erc1155token := erc1155.Init(web3.RPC, t.Address)
+			if result, _ := erc1155token.SupportsInterface(ctx); result {
+				return Publish(Eip1155Token, "transfer", t)
+			}
+		} else if token.Category == Eip1155Token {
+			_ = Publish(token.Category, "transfer", t)
+		}
 	}
 	return nil
 }
@@ -0,0 +1,226 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"github.com/shopspring/decimal"
+	"sort"
+)
+
+const (
+	// GasOracleWindow recent blocks of gas oracle
+	GasOracleWindow = 20
+	// baseFeeChangeDenominator eip-1559 BASE_FEE_MAX_CHANGE_DENOMINATOR
+	baseFeeChangeDenominator = 8
+	// elasticityMultiplier eip-1559 ELASTICITY_MULTIPLIER
+	elasticityMultiplier = 2
+)
+
+var ErrGasPriceTooLow = errors.New("gas price too low to be included in recent blocks")
+
+// EvmGasStat gas usage and priority fee percentiles of block
+type EvmGasStat struct {
+	BlockNum         uint64          `json:"block_num" gorm:"primaryKey;autoIncrement:false;size:64"`
+	Timestamp        uint            `json:"timestamp" gorm:"size:64"`
+	BaseFeePerGas    decimal.Decimal `json:"base_fee_per_gas" gorm:"default: 0;type:decimal(65);"`
+	GasUsed          decimal.Decimal `json:"gas_used" gorm:"default: 0;type:decimal(65);"`
+	GasLimit         decimal.Decimal `json:"gas_limit" gorm:"default: 0;type:decimal(65);"`
+	Utilization      decimal.Decimal `json:"utilization" gorm:"default: 0;type:decimal(10,4);"`
+	TransactionCount int             `json:"transaction_count" gorm:"size:32"`
+	MinPriorityFee   decimal.Decimal `json:"min_priority_fee" gorm:"default: 0;type:decimal(40);"`
+	PriorityFeeP25   decimal.Decimal `json:"priority_fee_p25" gorm:"default: 0;type:decimal(40);"`
+	PriorityFeeP50   decimal.Decimal `json:"priority_fee_p50" gorm:"default: 0;type:decimal(40);"`
+	PriorityFeeP75   decimal.Decimal `json:"priority_fee_p75" gorm:"default: 0;type:decimal(40);"`
+}
+
+func (g *EvmGasStat) TableName() string {
+	return "evm_gas_stats"
+}
+
+// NewGasStat stat block gas with effective gas price of transactions
+func NewGasStat(block *EvmBlock, effectiveGasPrices []decimal.Decimal) *EvmGasStat {
+	stat := EvmGasStat{
+		BlockNum:         block.BlockNum,
+		Timestamp:        block.Timestamp,
+		BaseFeePerGas:    block.BaseFeePerGas,
+		GasUsed:          block.GasUsed,
+		GasLimit:         block.GasLimit,
+		TransactionCount: len(effectiveGasPrices),
+	}
+	if block.GasLimit.IsPositive() {
+		stat.Utilization = block.GasUsed.Div(block.GasLimit).Round(4)
+	}
+	var fees []decimal.Decimal
+	for _, price := range effectiveGasPrices {
+		// legacy chain without base fee, priority fee is gas price
+		fee := price.Sub(block.BaseFeePerGas)
+		if fee.IsNegative() {
+			fee = decimal.Zero
+		}
+		fees = append(fees, fee)
+	}
+	sort.Slice(fees, func(i, j int) bool { return fees[i].LessThan(fees[j]) })
+	if len(fees) > 0 {
+		stat.MinPriorityFee = fees[0]
+		stat.PriorityFeeP25 = percentile(fees, 25)
+		stat.PriorityFeeP50 = percentile(fees, 50)
+		stat.PriorityFeeP75 = percentile(fees, 75)
+	}
+	return &stat
+}
+
+// percentile nearest-rank percentile of sorted values
+func percentile(sorted []decimal.Decimal, p int) decimal.Decimal {
+	if len(sorted) == 0 {
+		return decimal.Zero
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// NextBaseFee eip-1559 base fee of next block
+func NextBaseFee(baseFee, gasUsed, gasLimit decimal.Decimal) decimal.Decimal {
+	target := gasLimit.Div(decimal.NewFromInt(elasticityMultiplier)).Floor()
+	if baseFee.IsZero() || !target.IsPositive() {
+		return baseFee
+	}
+	delta := baseFee.Mul(gasUsed.Sub(target)).Div(target).Div(decimal.NewFromInt(baseFeeChangeDenominator)).Truncate(0)
+	if gasUsed.GreaterThan(target) && delta.LessThan(decimal.NewFromInt(1)) {
+		delta = decimal.NewFromInt(1)
+	}
+	if next := baseFee.Add(delta); next.IsPositive() {
+		return next
+	}
+	return decimal.Zero
+}
+
+// RefreshGasStat save gas stat after block transactions processed
+func RefreshGasStat(ctx context.Context, block *EvmBlock) error {
+	var prices []decimal.Decimal
+	sg.db.WithContext(ctx).Model(Transaction{}).Where("block_num = ?", block.BlockNum).Pluck("effective_gas_price", &prices)
+	stat := NewGasStat(block, prices)
+	return sg.AddOrUpdateItem(ctx, stat, []string{"block_num"}, "base_fee_per_gas", "gas_used", "gas_limit", "utilization",
+		"transaction_count", "min_priority_fee", "priority_fee_p25", "priority_fee_p50", "priority_fee_p75").Error
+}
+
+func recentGasStats(ctx context.Context, window int) (list []EvmGasStat) {
+	sg.db.WithContext(ctx).Model(EvmGasStat{}).Order("block_num desc").Limit(window).Find(&list)
+	return
+}
+
+type GasConsumerJson struct {
+	Address          string          `json:"address"`
+	ContractName     string          `json:"contract_name"`
+	GasUsed          decimal.Decimal `json:"gas_used"`
+	TransactionCount int             `json:"transaction_count"`
+}
+
+type GasTrackerJson struct {
+	LastBlock    uint64            `json:"last_block"`
+	Safe         decimal.Decimal   `json:"safe"`
+	Propose      decimal.Decimal   `json:"propose"`
+	Fast         decimal.Decimal   `json:"fast"`
+	BaseFee      decimal.Decimal   `json:"base_fee"`
+	NextBaseFee  decimal.Decimal   `json:"next_base_fee"`
+	Utilization  decimal.Decimal   `json:"utilization"`
+	BlockTime    decimal.Decimal   `json:"block_time"`
+	Blocks       []EvmGasStat      `json:"blocks,omitempty"`
+	TopConsumers []GasConsumerJson `json:"top_consumers,omitempty"`
+}
+
+// GasOracle safe/propose/fast priority fee are median of p25/p50/p75 in window, suggest gas price is next base fee add priority fee
+func GasOracle(stats []EvmGasStat) *GasTrackerJson {
+	if len(stats) == 0 {
+		return nil
+	}
+	latest := stats[0]
+	tracker := GasTrackerJson{
+		LastBlock:   latest.BlockNum,
+		Blocks:      stats,
+		BaseFee:     latest.BaseFeePerGas,
+		NextBaseFee: NextBaseFee(latest.BaseFeePerGas, latest.GasUsed, latest.GasLimit),
+	}
+	var p25, p50, p75 []decimal.Decimal
+	utilization := decimal.Zero
+	for _, stat := range stats {
+		utilization = utilization.Add(stat.Utilization)
+		// empty block has no priority fee sample
+		if stat.TransactionCount == 0 {
+			continue
+		}
+		p25, p50, p75 = append(p25, stat.PriorityFeeP25), append(p50, stat.PriorityFeeP50), append(p75, stat.PriorityFeeP75)
+	}
+	tracker.Utilization = utilization.Div(decimal.NewFromInt(int64(len(stats)))).Round(4)
+	for _, values := range [][]decimal.Decimal{p25, p50, p75} {
+		sort.Slice(values, func(i, j int) bool { return values[i].LessThan(values[j]) })
+	}
+	tracker.Safe = tracker.NextBaseFee.Add(percentile(p25, 50))
+	tracker.Propose = tracker.NextBaseFee.Add(percentile(p50, 50))
+	tracker.Fast = tracker.NextBaseFee.Add(percentile(p75, 50))
+	if oldest := stats[len(stats)-1]; len(stats) > 1 && latest.Timestamp > oldest.Timestamp {
+		tracker.BlockTime = decimal.NewFromInt(int64(latest.Timestamp - oldest.Timestamp)).Div(decimal.NewFromInt(int64(len(stats) - 1))).Round(2)
+	}
+	return &tracker
+}
+
+// GasEstimate estimated confirmation seconds, gas price is included by the block if not less than min priority fee of block
+func GasEstimate(stats []EvmGasStat, gasPrice decimal.Decimal) (int64, error) {
+	tracker := GasOracle(stats)
+	if tracker == nil {
+		return 0, ErrGasPriceTooLow
+	}
+	priorityFee := gasPrice.Sub(tracker.NextBaseFee)
+	if priorityFee.IsNegative() {
+		return 0, ErrGasPriceTooLow
+	}
+	var included int
+	for _, stat := range stats {
+		if stat.TransactionCount == 0 || stat.MinPriorityFee.LessThanOrEqual(priorityFee) {
+			included++
+		}
+	}
+	if included == 0 {
+		return 0, ErrGasPriceTooLow
+	}
+	// expected blocks = 1 / probability of included
+	return tracker.BlockTime.Mul(decimal.NewFromInt(int64(len(stats)))).Div(decimal.NewFromInt(int64(included))).Ceil().IntPart(), nil
+}
+
+// topGasConsumers contracts used most gas since block
+func topGasConsumers(ctx context.Context, sinceBlock uint64, limit int) []GasConsumerJson {
+	var list []GasConsumerJson
+	sg.db.WithContext(ctx).Model(Transaction{}).
+		Select("to_address as address, sum(gas_used) as gas_used, count(*) as transaction_count").
+		Where("block_num >= ?", sinceBlock).Where("to_address IN (?)", sg.db.Model(Contract{}).Select("address")).
+		Group("to_address").Order("gas_used desc").Limit(limit).Scan(&list)
+	var addresses []string
+	for _, consumer := range list {
+		addresses = append(addresses, consumer.Address)
+	}
+	names := GetContractName(ctx, addresses)
+	for index := range list {
+		list[index].ContractName = names[list[index].Address]
+	}
+	return list
+}
+
+func (a *ApiSrv) GasTracker(ctx context.Context, window int) *GasTrackerJson {
+	stats := recentGasStats(ctx, window)
+	tracker := GasOracle(stats)
+	if tracker == nil {
+		return nil
+	}
+	tracker.TopConsumers = topGasConsumers(ctx, stats[len(stats)-1].BlockNum, 10)
+	return tracker
+}
+
+func (a *ApiSrv) API_GasOracle(ctx context.Context) *GasTrackerJson {
+	return GasOracle(recentGasStats(ctx, GasOracleWindow))
+}
+
+func (a *ApiSrv) API_GasEstimate(ctx context.Context, gasPrice decimal.Decimal) (int64, error) {
+	return GasEstimate(recentGasStats(ctx, GasOracleWindow), gasPrice)
+}
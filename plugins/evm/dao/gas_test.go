@@ -0,0 +1,56 @@
+package dao
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_NewGasStat(t *testing.T) {
+	block := EvmBlock{BlockNum: 1, BaseFeePerGas: decimal.New(10, 9), GasUsed: decimal.NewFromInt(7500000), GasLimit: decimal.NewFromInt(30000000)}
+	stat := NewGasStat(&block, []decimal.Decimal{decimal.New(13, 9), decimal.New(11, 9), decimal.New(12, 9), decimal.New(9, 9)})
+	assert.Equal(t, 4, stat.TransactionCount)
+	assert.Equal(t, "0.25", stat.Utilization.String())
+	// priority fee is not negative
+	assert.True(t, stat.MinPriorityFee.IsZero())
+	assert.Equal(t, "0", stat.PriorityFeeP25.String())
+	assert.Equal(t, "1000000000", stat.PriorityFeeP50.String())
+	assert.Equal(t, "2000000000", stat.PriorityFeeP75.String())
+}
+
+func Test_NextBaseFee(t *testing.T) {
+	baseFee, limit := decimal.NewFromInt(1000000000), decimal.NewFromInt(30000000)
+	assert.Equal(t, "1000000000", NextBaseFee(baseFee, decimal.NewFromInt(15000000), limit).String())
+	assert.Equal(t, "1125000000", NextBaseFee(baseFee, limit, limit).String())
+	assert.Equal(t, "875000000", NextBaseFee(baseFee, decimal.Zero, limit).String())
+	// legacy chain
+	assert.True(t, NextBaseFee(decimal.Zero, limit, limit).IsZero())
+}
+
+func Test_GasOracle(t *testing.T) {
+	assert.Nil(t, GasOracle(nil))
+	half, limit := decimal.NewFromInt(15000000), decimal.NewFromInt(30000000)
+	stats := []EvmGasStat{
+		{BlockNum: 3, Timestamp: 18, BaseFeePerGas: decimal.NewFromInt(100), GasUsed: half, GasLimit: limit, Utilization: decimal.NewFromFloat(0.5), TransactionCount: 2,
+			MinPriorityFee: decimal.NewFromInt(5), PriorityFeeP25: decimal.NewFromInt(5), PriorityFeeP50: decimal.NewFromInt(8), PriorityFeeP75: decimal.NewFromInt(10)},
+		{BlockNum: 2, Timestamp: 12, BaseFeePerGas: decimal.NewFromInt(100), Utilization: decimal.NewFromFloat(0.1)},
+		{BlockNum: 1, Timestamp: 6, BaseFeePerGas: decimal.NewFromInt(100), Utilization: decimal.NewFromFloat(0.9), TransactionCount: 1,
+			MinPriorityFee: decimal.NewFromInt(1), PriorityFeeP25: decimal.NewFromInt(1), PriorityFeeP50: decimal.NewFromInt(1), PriorityFeeP75: decimal.NewFromInt(1)},
+	}
+	tracker := GasOracle(stats)
+	assert.Equal(t, uint64(3), tracker.LastBlock)
+	assert.Equal(t, "101", tracker.Safe.String())
+	assert.Equal(t, "101", tracker.Propose.String())
+	assert.Equal(t, "101", tracker.Fast.String())
+	assert.Equal(t, "0.5", tracker.Utilization.String())
+	assert.Equal(t, "6", tracker.BlockTime.String())
+
+	// priority fee 1 is included by blocks 1 and 2(empty)
+	seconds, err := GasEstimate(stats, decimal.NewFromInt(101))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), seconds)
+	seconds, _ = GasEstimate(stats, decimal.NewFromInt(105))
+	assert.Equal(t, int64(6), seconds)
+	_, err = GasEstimate(stats, decimal.NewFromInt(99))
+	assert.ErrorIs(t, err, ErrGasPriceTooLow)
+}
@@ -0,0 +1,124 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"github.com/itering/subscan/pkg/go-web3/dto"
+	"github.com/itering/subscan/share/metrics"
+	"github.com/itering/subscan/share/web3"
+	"github.com/itering/subscan/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// receiptsBatchSize max eth_getTransactionReceipt requests in one json-rpc batch
+const receiptsBatchSize = 100
+
+// blockReceiptsUnsupported node not support eth_getBlockReceipts, always use batch eth_getTransactionReceipt
+var blockReceiptsUnsupported atomic.Bool
+
+// FetchBlockReceipts receipts of block transactions by hash, use eth_getBlockReceipts if supported,
+// fallback to batch eth_getTransactionReceipt
+func FetchBlockReceipts(ctx context.Context, blockNum uint64, transactions []dto.BlockTransaction) (map[string]*dto.TransactionReceipt, error) {
+	receipts := make(map[string]*dto.TransactionReceipt)
+	if len(transactions) == 0 {
+		return receipts, nil
+	}
+	if !blockReceiptsUnsupported.Load() {
+		list, err := web3.RPC.Eth.GetBlockReceipts(ctx, new(big.Int).SetUint64(blockNum))
+		if err == nil && len(list) == len(transactions) {
+			for index := range list {
+				receipts[strings.ToLower(list[index].TransactionHash)] = &list[index]
+			}
+			if missingReceipt(receipts, transactions) == "" {
+				metrics.EvmReceiptsFetch.WithLabelValues("eth_getBlockReceipts").Inc()
+				return receipts, nil
+			}
+		}
+		if err != nil && isMethodUnsupported(err) {
+			blockReceiptsUnsupported.Store(true)
+			util.Logger().Warning("eth_getBlockReceipts is not supported by node, fallback to batch eth_getTransactionReceipt")
+		}
+	}
+	receipts = make(map[string]*dto.TransactionReceipt)
+	for start := 0; start < len(transactions); start += receiptsBatchSize {
+		end := min(start+receiptsBatchSize, len(transactions))
+		var hashes []string
+		for _, transaction := range transactions[start:end] {
+			hashes = append(hashes, transaction.Hash)
+		}
+		list, err := web3.RPC.Eth.GetTransactionReceipts(ctx, hashes)
+		if err != nil {
+			return nil, err
+		}
+		for index, receipt := range list {
+			receipts[strings.ToLower(hashes[index])] = receipt
+		}
+	}
+	if hash := missingReceipt(receipts, transactions); hash != "" {
+		return nil, fmt.Errorf("missing receipt of transaction %s", hash)
+	}
+	metrics.EvmReceiptsFetch.WithLabelValues("eth_getTransactionReceipt").Inc()
+	return receipts, nil
+}
+
+func missingReceipt(receipts map[string]*dto.TransactionReceipt, transactions []dto.BlockTransaction) string {
+	for _, transaction := range transactions {
+		if receipts[strings.ToLower(transaction.Hash)] == nil {
+			return transaction.Hash
+		}
+	}
+	return ""
+}
+
+// saveTransactions write transactions and event logs in one db transaction, hooks are skipped and run by afterSaved.
+// block row is written after all follow-up processing succeeded, AddEvmBlock skip block which row exists
+func (s *Storage) saveTransactions(ctx context.Context, transactions []Transaction, receipts []TransactionReceipt) error {
+	return s.db.WithContext(ctx).Session(&gorm.Session{SkipHooks: true}).Transaction(func(tx *gorm.DB) error {
+		if len(receipts) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(receipts, 3000).Error; err != nil {
+				return err
+			}
+		}
+		if len(transactions) > 0 {
+			return tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "hash"}},
+				DoUpdates: clause.AssignmentColumns([]string{"transaction_index"}),
+			}).CreateInBatches(transactions, 1000).Error
+		}
+		return nil
+	})
+}
+
+// blockTimer observe cost of each stage of processing block
+type blockTimer struct {
+	blockNum uint64
+	start    time.Time
+	last     time.Time
+	stages   []string
+}
+
+func newBlockTimer(blockNum uint64) *blockTimer {
+	now := time.Now()
+	return &blockTimer{blockNum: blockNum, start: now, last: now}
+}
+
+func (b *blockTimer) observe(stage string) {
+	now := time.Now()
+	cost := now.Sub(b.last)
+	b.last = now
+	metrics.EvmBlockProcessCost.WithLabelValues(stage).Observe(cost.Seconds())
+	b.stages = append(b.stages, fmt.Sprintf("%s=%s", stage, cost.Round(time.Millisecond)))
+}
+
+func (b *blockTimer) done(transactionCount int) {
+	total := time.Since(b.start)
+	metrics.EvmBlockProcessCost.WithLabelValues("total").Observe(total.Seconds())
+	metrics.EvmBlockTransactions.Observe(float64(transactionCount))
+	util.Logger().Debug(fmt.Sprintf("evm block %d processed, %d transactions, total=%s %s",
+		b.blockNum, transactionCount, total.Round(time.Millisecond), strings.Join(b.stages, " ")))
+}
@@ -0,0 +1,92 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	w3 "github.com/itering/subscan/pkg/go-web3"
+	"github.com/itering/subscan/pkg/go-web3/dto"
+	"github.com/itering/subscan/pkg/go-web3/providers"
+	"github.com/itering/subscan/share/web3"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func mockReceipt(hash string) string {
+	return fmt.Sprintf(`{"transactionHash":"%s","transactionIndex":"0x0","blockNumber":"0x1","cumulativeGasUsed":"0x5208","effectiveGasPrice":"0x3b9aca00","gasUsed":"0x5208","status":"0x1","logs":[{"address":"0x01","topics":["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"],"data":"0x","blockNumber":"0x1","transactionIndex":"0x0","logIndex":"0x0"}]}`, hash)
+}
+
+func Test_FetchBlockReceipts(t *testing.T) {
+	var (
+		methods          []string
+		supportBlockCall = true
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		// batch request
+		if strings.HasPrefix(string(body), "[") {
+			var batch []struct {
+				ID     int      `json:"id"`
+				Method string   `json:"method"`
+				Params []string `json:"params"`
+			}
+			_ = json.Unmarshal(body, &batch)
+			var res []string
+			// response in reverse order
+			for index := len(batch) - 1; index >= 0; index-- {
+				methods = append(methods, batch[index].Method)
+				res = append(res, fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":%s}`, batch[index].ID, mockReceipt(batch[index].Params[0])))
+			}
+			_, _ = w.Write([]byte("[" + strings.Join(res, ",") + "]"))
+			return
+		}
+		methods = append(methods, "eth_getBlockReceipts")
+		if !supportBlockCall {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"Method not found"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":[%s,%s]}`, mockReceipt("0xAA"), mockReceipt("0xbb"))))
+	}))
+	defer server.Close()
+	origin := web3.RPC
+	web3.RPC = w3.NewWeb3(providers.NewHTTPProvider(server.URL, 10, false))
+	defer func() {
+		web3.RPC = origin
+		blockReceiptsUnsupported.Store(false)
+	}()
+
+	ctx := context.TODO()
+	transactions := []dto.BlockTransaction{{Hash: "0xaa"}, {Hash: "0xbb"}}
+	receipts, err := FetchBlockReceipts(ctx, 1, transactions)
+	assert.NoError(t, err)
+	assert.Len(t, receipts, 2)
+	assert.Equal(t, []string{"eth_getBlockReceipts"}, methods)
+	assert.Equal(t, "", missingReceipt(receipts, transactions))
+
+	// fallback to batch receipts and remember node not support eth_getBlockReceipts
+	methods, supportBlockCall = nil, false
+	receipts, err = FetchBlockReceipts(ctx, 1, transactions)
+	assert.NoError(t, err)
+	assert.Equal(t, "0xbb", receipts["0xbb"].TransactionHash)
+	assert.Equal(t, []string{"eth_getBlockReceipts", "eth_getTransactionReceipt", "eth_getTransactionReceipt"}, methods)
+	assert.True(t, blockReceiptsUnsupported.Load())
+
+	methods = nil
+	_, err = FetchBlockReceipts(ctx, 1, transactions)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"eth_getTransactionReceipt", "eth_getTransactionReceipt"}, methods)
+
+	// transaction and event logs
+	transaction, logs := NewTransactionByExecuted(100, &dto.BlockTransaction{Hash: "0xbb", BlockNumber: "0x1", TransactionIndex: "0x1", Creates: "0xcc"}, receipts["0xbb"], "1-2")
+	assert.True(t, transaction.Success)
+	assert.Equal(t, "21000", transaction.GasUsed.String())
+	assert.Equal(t, "0xcc", transaction.Contract)
+	assert.Equal(t, "1-2", transaction.ExtrinsicIndex)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, transaction.TransactionId*TxnReceiptLimit, logs[0].Id)
+	assert.Equal(t, uint64(1), logs[0].TransactionIndex)
+}
@@ -0,0 +1,379 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/itering/subscan/pkg/go-web3/complex/types"
+	"github.com/itering/subscan/pkg/go-web3/dto"
+	"github.com/itering/subscan/share/web3"
+	"github.com/itering/subscan/util"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrContractNotFound    = errors.New("contract not found")
+	ErrContractNotVerified = errors.New("contract not verified")
+	ErrMethodNotFound      = errors.New("method not found")
+)
+
+type AbiParamJson struct {
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Components []AbiParamJson `json:"components,omitempty"`
+}
+
+type AbiFunctionJson struct {
+	Name            string         `json:"name"`
+	Signature       string         `json:"signature"`
+	MethodId        string         `json:"method_id"`
+	StateMutability string         `json:"state_mutability"`
+	Inputs          []AbiParamJson `json:"inputs"`
+	Outputs         []AbiParamJson `json:"outputs"`
+}
+
+type ContractFunctionsJson struct {
+	Address        string            `json:"address"`
+	Implementation string            `json:"implementation,omitempty"`
+	Read           []AbiFunctionJson `json:"read"`
+	Write          []AbiFunctionJson `json:"write"`
+}
+
+// ContractCallData calldata of state-changing function, sign and send by wallet
+type ContractCallData struct {
+	To        string `json:"to"`
+	Data      string `json:"data"`
+	Value     string `json:"value"`
+	Method    string `json:"method"`
+	Signature string `json:"signature"`
+}
+
+// interactionAbi verified abi of contract, or abi of proxy implementation if asProxy
+func interactionAbi(ctx context.Context, address string, asProxy bool) (*abi.ABI, string, error) {
+	contract := GetContract(ctx, address)
+	if contract == nil {
+		return nil, "", ErrContractNotFound
+	}
+	if asProxy {
+		if contract.EipStandard == "" {
+			return nil, "", errors.New("contract is not a proxy")
+		}
+		// implementation and diamond facets
+		if contractAbi := proxyAbi(ctx, contract); contractAbi != nil {
+			return contractAbi, contract.ProxyImplementation, nil
+		}
+		return nil, "", fmt.Errorf("implementation of %s not verified", contract.Address)
+	}
+	if contract.VerifyStatus == "" {
+		return nil, "", ErrContractNotVerified
+	}
+	contractAbi := parseAbi(contract.Abi)
+	if contractAbi == nil {
+		return nil, "", ErrContractNotVerified
+	}
+	return contractAbi, contract.Address, nil
+}
+
+func abiParamsJson(args abi.Arguments) []AbiParamJson {
+	params := make([]AbiParamJson, 0, len(args))
+	for index, arg := range namedArguments(args) {
+		params = append(params, abiParamJson(arg.Name, args[index].Type))
+	}
+	return params
+}
+
+func abiParamJson(name string, t abi.Type) AbiParamJson {
+	param := AbiParamJson{Name: name, Type: t.String()}
+	for index, elem := range t.TupleElems {
+		param.Components = append(param.Components, abiParamJson(t.TupleRawNames[index], *elem))
+	}
+	return param
+}
+
+// ContractFunctions list view/pure functions as read, others as write
+func ContractFunctions(ctx context.Context, address string, asProxy bool) (*ContractFunctionsJson, error) {
+	contractAbi, source, err := interactionAbi(ctx, address, asProxy)
+	if err != nil {
+		return nil, err
+	}
+	res := ContractFunctionsJson{Address: address, Read: []AbiFunctionJson{}, Write: []AbiFunctionJson{}}
+	if asProxy {
+		res.Implementation = source
+	}
+	var methods []abi.Method
+	for _, method := range contractAbi.Methods {
+		methods = append(methods, method)
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].Sig < methods[j].Sig })
+	for _, method := range methods {
+		function := AbiFunctionJson{
+			Name:            method.RawName,
+			Signature:       method.Sig,
+			MethodId:        util.AddHex(util.BytesToHex(method.ID)),
+			StateMutability: method.StateMutability,
+			Inputs:          abiParamsJson(method.Inputs),
+			Outputs:         abiParamsJson(method.Outputs),
+		}
+		if method.IsConstant() {
+			res.Read = append(res.Read, function)
+		} else {
+			res.Write = append(res.Write, function)
+		}
+	}
+	return &res, nil
+}
+
+// findMethod find method by signature, method id or name, overloaded name must use signature
+func findMethod(contractAbi *abi.ABI, name string) (*abi.Method, error) {
+	var found []abi.Method
+	for _, method := range contractAbi.Methods {
+		if method.Sig == name || strings.EqualFold(util.AddHex(util.BytesToHex(method.ID)), name) {
+			return &method, nil
+		}
+		if method.RawName == name {
+			found = append(found, method)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, ErrMethodNotFound
+	case 1:
+		return &found[0], nil
+	}
+	return nil, fmt.Errorf("method %s is overloaded, use signature instead", name)
+}
+
+// EncodeMethodCall encode calldata with json arguments
+func EncodeMethodCall(method *abi.Method, args []interface{}) ([]byte, error) {
+	if len(args) != len(method.Inputs) {
+		return nil, fmt.Errorf("method %s expects %d arguments, got %d", method.Sig, len(method.Inputs), len(args))
+	}
+	values := make([]interface{}, 0, len(args))
+	for index, input := range method.Inputs {
+		value, err := AbiArgument(input.Type, args[index])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d(%s): %w", index, input.Type.String(), err)
+		}
+		values = append(values, value)
+	}
+	data, err := method.Inputs.Pack(values...)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, method.ID...), data...), nil
+}
+
+// ContractRead call view/pure function by eth_call and decode outputs
+func ContractRead(ctx context.Context, address string, asProxy bool, name string, args []interface{}, from string) ([]DecodedParam, error) {
+	contractAbi, _, err := interactionAbi(ctx, address, asProxy)
+	if err != nil {
+		return nil, err
+	}
+	method, err := findMethod(contractAbi, name)
+	if err != nil {
+		return nil, err
+	}
+	if !method.IsConstant() {
+		return nil, fmt.Errorf("method %s is not view or pure function", method.Sig)
+	}
+	data, err := EncodeMethodCall(method, args)
+	if err != nil {
+		return nil, err
+	}
+	result, err := web3.RPC.Eth.Call(ctx, &dto.TransactionParameters{
+		From: from,
+		To:   address,
+		Data: types.ComplexString(util.AddHex(util.BytesToHex(data))),
+	})
+	if err != nil {
+		return nil, err
+	}
+	output, err := result.ToString()
+	if err != nil {
+		return nil, err
+	}
+	values, err := method.Outputs.UnpackValues(util.HexToBytes(output))
+	if err != nil {
+		return nil, err
+	}
+	var decoded []DecodedParam
+	for index, arg := range namedArguments(method.Outputs) {
+		decoded = append(decoded, DecodedParam{Name: arg.Name, Type: arg.Type.String(), Value: formatAbiValue(values[index])})
+	}
+	return decoded, nil
+}
+
+// ContractEncodeCall encode calldata of state-changing function
+func ContractEncodeCall(ctx context.Context, address string, asProxy bool, name string, args []interface{}, value string) (*ContractCallData, error) {
+	contractAbi, _, err := interactionAbi(ctx, address, asProxy)
+	if err != nil {
+		return nil, err
+	}
+	method, err := findMethod(contractAbi, name)
+	if err != nil {
+		return nil, err
+	}
+	if value = util.IfEmptyElse(value, "0"); value != "0" && !method.Payable {
+		return nil, fmt.Errorf("method %s is not payable", method.Sig)
+	}
+	data, err := EncodeMethodCall(method, args)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractCallData{
+		To:        address,
+		Data:      util.AddHex(util.BytesToHex(data)),
+		Value:     value,
+		Method:    method.RawName,
+		Signature: method.Sig,
+	}, nil
+}
+
+// AbiArgument convert json value to go value accepted by abi.Pack
+func AbiArgument(t abi.Type, value interface{}) (interface{}, error) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := abiBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		if t.T == abi.UintTy && (n.Sign() < 0 || n.BitLen() > t.Size) {
+			return nil, fmt.Errorf("%s out of range", n.String())
+		}
+		if t.T == abi.IntTy {
+			limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+			if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+				return nil, fmt.Errorf("%s out of range", n.String())
+			}
+		}
+		goType := t.GetType()
+		if goType.Kind() == reflect.Ptr {
+			return n, nil
+		}
+		if t.T == abi.UintTy {
+			return reflect.ValueOf(n.Uint64()).Convert(goType).Interface(), nil
+		}
+		return reflect.ValueOf(n.Int64()).Convert(goType).Interface(), nil
+	case abi.BoolTy:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+	case abi.StringTy:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	case abi.AddressTy:
+		if v, ok := value.(string); ok && common.IsHexAddress(v) {
+			return common.HexToAddress(v), nil
+		}
+		return nil, fmt.Errorf("invalid address %v", value)
+	case abi.BytesTy:
+		if v, ok := value.(string); ok {
+			return util.HexToBytes(v), nil
+		}
+	case abi.FixedBytesTy:
+		if v, ok := value.(string); ok {
+			b := util.HexToBytes(v)
+			if len(b) > t.Size {
+				return nil, fmt.Errorf("bytes%d overflow", t.Size)
+			}
+			fixed := reflect.New(t.GetType()).Elem()
+			reflect.Copy(fixed, reflect.ValueOf(b))
+			return fixed.Interface(), nil
+		}
+	case abi.SliceTy, abi.ArrayTy:
+		list, err := abiList(value)
+		if err != nil {
+			return nil, err
+		}
+		if t.T == abi.ArrayTy && len(list) != t.Size {
+			return nil, fmt.Errorf("array length should be %d", t.Size)
+		}
+		var values reflect.Value
+		if t.T == abi.ArrayTy {
+			values = reflect.New(t.GetType()).Elem()
+		} else {
+			values = reflect.MakeSlice(t.GetType(), len(list), len(list))
+		}
+		for index, item := range list {
+			elem, err := AbiArgument(*t.Elem, item)
+			if err != nil {
+				return nil, err
+			}
+			values.Index(index).Set(reflect.ValueOf(elem))
+		}
+		return values.Interface(), nil
+	case abi.TupleTy:
+		tuple := reflect.New(t.GetType()).Elem()
+		var list []interface{}
+		if fields, ok := value.(map[string]interface{}); ok {
+			for _, name := range t.TupleRawNames {
+				list = append(list, fields[name])
+			}
+		} else {
+			var err error
+			if list, err = abiList(value); err != nil {
+				return nil, err
+			}
+		}
+		if len(list) != len(t.TupleElems) {
+			return nil, fmt.Errorf("tuple should have %d fields", len(t.TupleElems))
+		}
+		for index, elem := range t.TupleElems {
+			field, err := AbiArgument(*elem, list[index])
+			if err != nil {
+				return nil, err
+			}
+			tuple.Field(index).Set(reflect.ValueOf(field))
+		}
+		return tuple.Interface(), nil
+	}
+	return nil, fmt.Errorf("invalid value %v", value)
+}
+
+// abiBigInt decimal/hex string or json number
+func abiBigInt(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case float64:
+		if v != float64(int64(v)) {
+			return nil, fmt.Errorf("invalid integer %v", v)
+		}
+		return big.NewInt(int64(v)), nil
+	case json.Number:
+		value = v.String()
+	}
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %v", value)
+	}
+	n, ok := new(big.Int).SetString(strings.TrimSpace(s), 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %s", s)
+	}
+	return n, nil
+}
+
+// abiList json array, or json array encoded as string
+func abiList(value interface{}) ([]interface{}, error) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, nil
+	case string:
+		var list []interface{}
+		if err := json.Unmarshal([]byte(v), &list); err != nil {
+			return nil, err
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("invalid array %v", value)
+}
@@ -0,0 +1,63 @@
+package dao
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	evmAbi "github.com/itering/subscan/plugins/evm/abi"
+	"github.com/itering/subscan/util"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func Test_EncodeMethodCall(t *testing.T) {
+	erc20Abi := parseAbi([]byte(evmAbi.Erc20))
+	// transfer is overloaded
+	_, err := findMethod(erc20Abi, "transfer")
+	assert.Error(t, err)
+	method, err := findMethod(erc20Abi, "transfer(address,uint256)")
+	assert.NoError(t, err)
+	data, err := EncodeMethodCall(method, []interface{}{"0x66a1cba4138cbb5bcc3e3af1b10c7fd56b6aac0b", "1000"})
+	assert.NoError(t, err)
+	assert.Equal(t, "a9059cbb00000000000000000000000066a1cba4138cbb5bcc3e3af1b10c7fd56b6aac0b00000000000000000000000000000000000000000000000000000000000003e8", util.BytesToHex(data))
+
+	method, err = findMethod(erc20Abi, "0xa9059cbb")
+	assert.NoError(t, err)
+	assert.Equal(t, "transfer(address,uint256)", method.Sig)
+
+	_, err = EncodeMethodCall(method, []interface{}{"0x01", "1000"})
+	assert.Error(t, err)
+	_, err = EncodeMethodCall(method, []interface{}{"0x66a1cba4138cbb5bcc3e3af1b10c7fd56b6aac0b"})
+	assert.Error(t, err)
+	_, err = findMethod(erc20Abi, "notExist")
+	assert.ErrorIs(t, err, ErrMethodNotFound)
+}
+
+func Test_AbiArgument(t *testing.T) {
+	uint8Ty, _ := abi.NewType("uint8", "", nil)
+	value, err := AbiArgument(uint8Ty, float64(255))
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(255), value)
+	_, err = AbiArgument(uint8Ty, "256")
+	assert.Error(t, err)
+
+	int256Ty, _ := abi.NewType("int256", "", nil)
+	value, err = AbiArgument(int256Ty, "-0x10")
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(-16), value)
+
+	arrayTy, _ := abi.NewType("uint256[2]", "", nil)
+	value, err = AbiArgument(arrayTy, `["1", "2"]`)
+	assert.NoError(t, err)
+	assert.Equal(t, [2]*big.Int{big.NewInt(1), big.NewInt(2)}, value)
+
+	tupleTy, _ := abi.NewType("tuple", "", []abi.ArgumentMarshaling{{Name: "amount", Type: "uint256"}, {Name: "flag", Type: "bool"}})
+	_, err = AbiArgument(tupleTy, map[string]interface{}{"amount": "1", "flag": true})
+	assert.NoError(t, err)
+	_, err = AbiArgument(tupleTy, []interface{}{"1"})
+	assert.Error(t, err)
+
+	bytes4Ty, _ := abi.NewType("bytes4", "", nil)
+	value, err = AbiArgument(bytes4Ty, "0xa9059cbb")
+	assert.NoError(t, err)
+	assert.Equal(t, [4]byte{0xa9, 0x05, 0x9c, 0xbb}, value)
+}
@@ -0,0 +1,290 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/itering/subscan/model"
+	"github.com/itering/subscan/pkg/go-web3/dto"
+	"github.com/itering/subscan/share/web3"
+	"github.com/itering/subscan/util"
+	"github.com/shopspring/decimal"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// InternalTransaction call frame of transaction trace, depth 0 is the transaction itself
+type InternalTransaction struct {
+	Id             uint64          `json:"id" gorm:"primaryKey;autoIncrement:false"`
+	Hash           string          `json:"hash" gorm:"size:70;index:hash"`
+	BlockNum       uint            `json:"block_num" gorm:"size:32;index:block_num"`
+	BlockTimestamp uint            `json:"block_timestamp" gorm:"size:32"`
+	TraceAddress   string          `json:"trace_address" gorm:"size:255"`
+	Depth          uint            `json:"depth" gorm:"size:32"`
+	CallType       string          `json:"call_type" gorm:"size:20"`
+	FromAddress    string          `json:"from_address" gorm:"size:70;index:from_address"`
+	ToAddress      string          `json:"to_address" gorm:"size:70;index:to_address"`
+	Value          decimal.Decimal `json:"value" gorm:"default: 0;type:decimal(65);"`
+	Gas            decimal.Decimal `json:"gas" gorm:"default: 0;type:decimal(40);"`
+	GasUsed        decimal.Decimal `json:"gas_used" gorm:"default: 0;type:decimal(40);"`
+	Input          string          `json:"input" gorm:"type:text"`
+	Output         string          `json:"output" gorm:"type:text"`
+	Error          string          `json:"error" gorm:"type:text"`
+	RevertReason   string          `json:"revert_reason" gorm:"type:text"`
+}
+
+func (i *InternalTransaction) TableName() string {
+	return "evm_internal_transactions"
+}
+
+type InternalCallJson struct {
+	TraceAddress string              `json:"trace_address"`
+	CallType     string              `json:"call_type"`
+	From         string              `json:"from"`
+	To           string              `json:"to"`
+	Value        decimal.Decimal     `json:"value"`
+	Gas          decimal.Decimal     `json:"gas"`
+	GasUsed      decimal.Decimal     `json:"gas_used"`
+	Input        string              `json:"input"`
+	Output       string              `json:"output"`
+	Error        string              `json:"error,omitempty"`
+	RevertReason string              `json:"revert_reason,omitempty"`
+	Calls        []*InternalCallJson `json:"calls,omitempty"`
+}
+
+func (i *InternalTransaction) AsCallJson() *InternalCallJson {
+	return &InternalCallJson{
+		TraceAddress: i.TraceAddress,
+		CallType:     i.CallType,
+		From:         i.FromAddress,
+		To:           i.ToAddress,
+		Value:        i.Value,
+		Gas:          i.Gas,
+		GasUsed:      i.GasUsed,
+		Input:        i.Input,
+		Output:       i.Output,
+		Error:        i.Error,
+		RevertReason: i.RevertReason,
+	}
+}
+
+var (
+	ErrTraceUnsupported = errors.New("debug_traceTransaction and trace_transaction are not supported")
+	// traceUnsupported node not support trace rpc, skip all trace jobs
+	traceUnsupported atomic.Bool
+)
+
+// TraceTransaction fetch call frames by debug_traceTransaction(callTracer), fallback to trace_transaction
+func TraceTransaction(ctx context.Context, hash string) (*dto.TracerTransactionResponse, error) {
+	frame, err := web3.RPC.Eth.DebugTraceTransaction(ctx, hash)
+	if err == nil {
+		return frame, nil
+	}
+	traces, traceErr := web3.RPC.Eth.TraceTransaction(ctx, hash)
+	if traceErr != nil {
+		if isMethodUnsupported(err) && isMethodUnsupported(traceErr) {
+			return nil, ErrTraceUnsupported
+		}
+		return nil, err
+	}
+	return ParityToCallFrame(traces), nil
+}
+
+func isMethodUnsupported(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, keyword := range []string{"method not found", "does not exist", "not available", "not supported"} {
+		if strings.Contains(msg, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParityToCallFrame convert flat trace_transaction list(depth first order) to callTracer frame tree
+func ParityToCallFrame(traces []dto.TraceResponse) *dto.TracerTransactionResponse {
+	if len(traces) == 0 {
+		return nil
+	}
+	frame, _ := buildParityFrame(traces, 0)
+	return &frame
+}
+
+func buildParityFrame(traces []dto.TraceResponse, index int) (dto.TracerTransactionResponse, int) {
+	trace := traces[index]
+	frame := dto.TracerTransactionResponse{
+		Type:  strings.ToUpper(trace.Type),
+		From:  trace.Action.From,
+		To:    trace.Action.To,
+		Gas:   trace.Action.Gas,
+		Input: trace.Action.Input,
+		Value: trace.Action.Value,
+	}
+	switch trace.Type {
+	case "call":
+		frame.Type = strings.ToUpper(trace.Action.CallType)
+	case "create":
+		frame.Input = trace.Action.Init
+	case "suicide":
+		frame.Type = "SELFDESTRUCT"
+		frame.From = trace.Action.Address
+		frame.To = trace.Action.RefundAddress
+		frame.Value = trace.Action.Balance
+	}
+	if trace.Result != nil {
+		frame.GasUsed = trace.Result.GasUsed
+		output := trace.Result.Output
+		if trace.Type == "create" {
+			frame.To = trace.Result.Address
+			output = trace.Result.Code
+		}
+		frame.Output = &output
+	}
+	if trace.Error != "" {
+		errMsg := trace.Error
+		frame.Error = &errMsg
+	}
+	next := index + 1
+	for i := 0; i < trace.Subtraces && next < len(traces); i++ {
+		var child dto.TracerTransactionResponse
+		child, next = buildParityFrame(traces, next)
+		frame.Calls = append(frame.Calls, child)
+	}
+	return frame, next
+}
+
+// DecodeRevertReason decode Error(string) or Panic(uint256) revert output
+func DecodeRevertReason(output string) string {
+	data := util.HexToBytes(output)
+	if len(data) < 4 {
+		return ""
+	}
+	reason, err := abi.UnpackRevert(data)
+	if err != nil {
+		return ""
+	}
+	return reason
+}
+
+// FlattenCallFrame flatten call frame tree to internal transactions by depth first order
+func FlattenCallFrame(t *Transaction, root *dto.TracerTransactionResponse) (list []InternalTransaction) {
+	var walk func(frame *dto.TracerTransactionResponse, traceAddress []string)
+	walk = func(frame *dto.TracerTransactionResponse, traceAddress []string) {
+		// keep id unique in transaction
+		if len(list) >= TxnReceiptLimit {
+			return
+		}
+		internal := InternalTransaction{
+			Id:             t.TransactionId*TxnReceiptLimit + uint64(len(list)),
+			Hash:           t.Hash,
+			BlockNum:       t.BlockNum,
+			BlockTimestamp: t.BlockTimestamp,
+			TraceAddress:   strings.Join(traceAddress, "_"),
+			Depth:          uint(len(traceAddress)),
+			CallType:       frame.Type,
+			FromAddress:    strings.ToLower(frame.From),
+			ToAddress:      strings.ToLower(frame.To),
+			Value:          util.DecimalFromU256(frame.Value),
+			Gas:            util.DecimalFromU256(frame.Gas),
+			GasUsed:        util.DecimalFromU256(frame.GasUsed),
+			Input:          frame.Input,
+		}
+		if frame.Output != nil {
+			internal.Output = *frame.Output
+		}
+		if frame.Error != nil {
+			internal.Error = *frame.Error
+			if frame.RevertReason != nil {
+				internal.RevertReason = *frame.RevertReason
+			} else {
+				internal.RevertReason = DecodeRevertReason(internal.Output)
+			}
+		}
+		list = append(list, internal)
+		for index := range frame.Calls {
+			childAddress := append(append([]string{}, traceAddress...), strconv.Itoa(index))
+			walk(&frame.Calls[index], childAddress)
+		}
+	}
+	walk(root, nil)
+	return
+}
+
+// BuildCallTree rebuild call tree from internal transactions ordered by id
+func BuildCallTree(list []InternalTransaction) *InternalCallJson {
+	var (
+		root  *InternalCallJson
+		stack []*InternalCallJson
+	)
+	for index := range list {
+		node := list[index].AsCallJson()
+		depth := int(list[index].Depth)
+		if depth == 0 {
+			root = node
+			stack = []*InternalCallJson{node}
+			continue
+		}
+		if root == nil || depth > len(stack) {
+			continue
+		}
+		stack = stack[:depth]
+		stack[depth-1].Calls = append(stack[depth-1].Calls, node)
+		stack = append(stack, node)
+	}
+	return root
+}
+
+// needTrace simple transfer to EOA has no internal call
+func (t *Transaction) needTrace(ctx context.Context) bool {
+	if t.Contract != "" || !t.Success {
+		return true
+	}
+	if t.InputData != "" && t.InputData != "0x" {
+		return true
+	}
+	return IsContract(ctx, t.ToAddress)
+}
+
+// ProcessInternalTransactions trace transaction and save call frames
+func ProcessInternalTransactions(ctx context.Context, hash string) error {
+	if traceUnsupported.Load() {
+		return nil
+	}
+	transaction := GetTransactionByHash(ctx, hash)
+	if transaction == nil {
+		return nil
+	}
+	frame, err := TraceTransaction(ctx, hash)
+	if errors.Is(err, ErrTraceUnsupported) {
+		traceUnsupported.Store(true)
+		util.Logger().Warning("EVM internal transaction trace is disabled because the node does not support trace rpc")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// no internal call and not reverted
+	if frame == nil || (len(frame.Calls) == 0 && frame.Error == nil) {
+		return nil
+	}
+	list := FlattenCallFrame(transaction, frame)
+	if err = sg.db.WithContext(ctx).Scopes(model.IgnoreDuplicate).CreateInBatches(list, 1000).Error; err != nil {
+		return err
+	}
+	return saveBalanceChanges(ctx, internalBalanceChanges(transaction, list))
+}
+
+func InternalTransactionsByHash(ctx context.Context, hash string) (list []InternalTransaction) {
+	sg.db.WithContext(ctx).Where("hash = ?", hash).Order("id asc").Find(&list)
+	return
+}
+
+// TraceErrorMsg revert reason of transaction, fallback to vm error
+func TraceErrorMsg(list []InternalTransaction) string {
+	for _, v := range list {
+		if v.Depth == 0 {
+			return util.IfEmptyElse(v.RevertReason, v.Error)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,47 @@
+package dao_test
+
+import (
+	"github.com/itering/subscan/pkg/go-web3/dto"
+	"github.com/itering/subscan/plugins/evm/dao"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_InternalTransaction(t *testing.T) {
+	// Error(string) "insufficient balance"
+	revert := "0x08c379a00000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000014" +
+		"696e73756666696369656e742062616c616e6365000000000000000000000000"
+	assert.Equal(t, "insufficient balance", dao.DecodeRevertReason(revert))
+	assert.Equal(t, "", dao.DecodeRevertReason("0x"))
+
+	var traces []dto.TraceResponse
+	traces = append(traces, dto.TraceResponse{Type: "call", Subtraces: 2})
+	traces[0].Action.CallType = "call"
+	traces[0].Action.From = "0x2cac6e4b11d6b58f6d3c1c9d5fe8faa89f60e5a2"
+	traces[0].Action.To = "0x66a1cba4138cbb5bcc3e3af1b10c7fd56b6aac0b"
+	traces = append(traces, dto.TraceResponse{Type: "call", Subtraces: 1, TraceAddress: []int{0}})
+	traces[1].Action.CallType = "delegatecall"
+	traces = append(traces, dto.TraceResponse{Type: "call", TraceAddress: []int{0, 0}})
+	traces[2].Action.CallType = "staticcall"
+	traces = append(traces, dto.TraceResponse{Type: "create", TraceAddress: []int{1}, Error: "Reverted"})
+
+	frame := dao.ParityToCallFrame(traces)
+	assert.Equal(t, "CALL", frame.Type)
+	assert.Len(t, frame.Calls, 2)
+	assert.Equal(t, "DELEGATECALL", frame.Calls[0].Type)
+	assert.Equal(t, "STATICCALL", frame.Calls[0].Calls[0].Type)
+	assert.Equal(t, "CREATE", frame.Calls[1].Type)
+
+	list := dao.FlattenCallFrame(&dao.Transaction{Hash: "0x01", TransactionId: 1}, frame)
+	assert.Len(t, list, 4)
+	assert.Equal(t, uint64(10_000), list[0].Id)
+	assert.Equal(t, "0_0", list[2].TraceAddress)
+	assert.Equal(t, uint(2), list[2].Depth)
+	assert.Equal(t, "Reverted", list[3].Error)
+
+	tree := dao.BuildCallTree(list)
+	assert.Len(t, tree.Calls, 2)
+	assert.Len(t, tree.Calls[0].Calls, 1)
+	assert.Equal(t, "1", tree.Calls[1].TraceAddress)
+}
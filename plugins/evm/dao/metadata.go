@@ -0,0 +1,343 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/itering/subscan/plugins/evm/feature/erc1155"
+	"github.com/itering/subscan/plugins/evm/feature/erc721"
+	"github.com/itering/subscan/share/web3"
+	"github.com/itering/subscan/util"
+	"github.com/itering/subscan/util/ipfs"
+)
+
+const (
+	NftMetadataPending  = "pending"
+	NftMetadataSuccess  = "success"
+	NftMetadataRetrying = "retrying"
+	NftMetadataFailed   = "failed"
+	NftMetadataSkipped  = "skipped"
+)
+
+var (
+	// nftMetadataCacheDir local copy of metadata json and thumbnail, disabled if empty
+	nftMetadataCacheDir = os.Getenv("NFT_METADATA_CACHE_DIR")
+	// nftThumbnailEnabled download image of metadata to cache dir
+	nftThumbnailEnabled = os.Getenv("NFT_THUMBNAIL") == "true"
+	// nftMetadataMaxAttempts fetch attempts before marked as failed
+	nftMetadataMaxAttempts = util.StringToInt(util.GetEnv("NFT_METADATA_MAX_ATTEMPTS", "5"))
+	// nftMetadataRetryBase first retry delay, doubled every attempt
+	nftMetadataRetryBase = 30 * time.Second
+	nftMetadataRetryMax  = time.Hour
+	// nftMetadataRefreshCooldown min interval of forced refresh of same token id
+	nftMetadataRefreshCooldown = 10 * time.Minute
+)
+
+var (
+	ErrNftMetadataSkipped = errors.New("metadata uri skipped")
+	ErrNotNftToken        = errors.New("not erc721 or erc1155 token")
+)
+
+// NftMetadata fetch status of nft metadata
+type NftMetadata struct {
+	Id        string `json:"-" gorm:"primaryKey;size:100"`
+	Contract  string `json:"contract" gorm:"size:100;index:contract_status,priority:1"`
+	TokenId   string `json:"token_id" gorm:"size:255"`
+	TokenUri  string `json:"token_uri" gorm:"type:text"`
+	Status    string `json:"status" gorm:"size:20;index:contract_status,priority:2"`
+	Error     string `json:"error" gorm:"type:text"`
+	Attempts  int    `json:"attempts" gorm:"size:32"`
+	Thumbnail string `json:"thumbnail" gorm:"type:text"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+func (n *NftMetadata) TableName() string {
+	return "evm_nft_metadata"
+}
+
+// NftMetadataArgs args of metadata queue job
+type NftMetadataArgs struct {
+	Contract string `json:"contract"`
+	TokenId  string `json:"token_id"`
+	Attempt  int    `json:"attempt"`
+	Force    bool   `json:"force"`
+}
+
+func GetNftMetadata(ctx context.Context, contract, tokenId string) *NftMetadata {
+	var record NftMetadata
+	if q := sg.db.WithContext(ctx).Where("id = ?", erc1155ItemId(contract, tokenId)).First(&record); q.Error != nil {
+		return nil
+	}
+	return &record
+}
+
+func saveNftMetadataStatus(ctx context.Context, record *NftMetadata) error {
+	record.Id = erc1155ItemId(record.Contract, record.TokenId)
+	record.UpdatedAt = time.Now().Unix()
+	return sg.AddOrUpdateItem(ctx, record, []string{"id"}, "token_uri", "status", "error", "attempts", "thumbnail", "updated_at").Error
+}
+
+// PublishNftMetadata enqueue metadata fetch of token id
+func PublishNftMetadata(contract, tokenId string, force bool) error {
+	return Publish(EvmNftMetadata, "fetch", NftMetadataArgs{Contract: contract, TokenId: tokenId, Force: force})
+}
+
+// nftMetadataBackoff delay before retry attempt
+func nftMetadataBackoff(attempt int) time.Duration {
+	delay := nftMetadataRetryBase
+	for i := 0; i < attempt && delay < nftMetadataRetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, nftMetadataRetryMax)
+}
+
+// tokenUri metadata uri of token id
+func (c *Token) tokenUri(ctx context.Context, tokenId string) (string, error) {
+	var (
+		tokenUrl string
+		err      error
+	)
+	switch {
+	case c.BaseTokenUri != "":
+		tokenUrl = fmt.Sprintf("%s%s", c.BaseTokenUri, tokenId)
+	case c.Category == Eip721Token:
+		tokenUrl, err = erc721.Init(web3.RPC, c.Contract).TokenURI(ctx, tokenId)
+	case c.Category == Eip1155Token:
+		tokenUrl, err = erc1155.Init(web3.RPC, c.Contract).Uri(ctx, tokenId)
+		tokenUrl = strings.ReplaceAll(tokenUrl, "{id}", tokenId)
+	default:
+		return "", ErrNotNftToken
+	}
+	if tokenUrl == "" && err == nil {
+		err = errors.New("empty token uri")
+	}
+	return strings.TrimPrefix(tokenUrl, "/"), err
+}
+
+// openTokenUri ipfs,ar,http,base64 metadata json
+func openTokenUri(ctx context.Context, tokenUrl string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(tokenUrl, "http://localhost") || strings.HasPrefix(tokenUrl, "https://localhost") || strings.Contains(tokenUrl, "127.0.0.1"):
+		return nil, fmt.Errorf("%w: localhost %s", ErrNftMetadataSkipped, tokenUrl)
+	case strings.HasPrefix(tokenUrl, "did:dkg"):
+		return nil, fmt.Errorf("%w: unsupported did %s", ErrNftMetadataSkipped, tokenUrl)
+	case strings.HasPrefix(tokenUrl, "data:application/json;base64,"):
+		return []byte(util.Base64Decode(strings.TrimPrefix(tokenUrl, "data:application/json;base64,"))), nil
+	case strings.HasPrefix(tokenUrl, "data:application/json;utf8,"):
+		return []byte(strings.TrimPrefix(tokenUrl, "data:application/json;utf8,")), nil
+	case strings.HasPrefix(tokenUrl, "ipfs://"), strings.HasPrefix(tokenUrl, "ar://"), strings.HasPrefix(tokenUrl, "http"):
+		data, err := ipfs.Open(ctx, tokenUrl)
+		if errors.Is(err, ipfs.ErrPrivateAddress) {
+			return nil, fmt.Errorf("%w: %v", ErrNftMetadataSkipped, err)
+		}
+		return data, err
+	}
+	return nil, fmt.Errorf("%w: unsupported token uri %s", ErrNftMetadataSkipped, tokenUrl)
+}
+
+func decodeMetadata(data []byte) (*Metadata, error) {
+	var metadata Metadata
+	if err := util.UnmarshalAny(&metadata, data); err != nil {
+		return nil, fmt.Errorf("invalid metadata json: %w", err)
+	}
+	return &metadata, nil
+}
+
+// GetMetadata fetch metadata of token id, storage url is image of metadata
+func (c *Token) GetMetadata(ctx context.Context, _, tokenId string) (*Metadata, string, error) {
+	tokenUrl, err := c.tokenUri(ctx, tokenId)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := openTokenUri(ctx, tokenUrl)
+	if err != nil {
+		return nil, "", err
+	}
+	metadata, err := decodeMetadata(data)
+	if err != nil {
+		return nil, "", err
+	}
+	return metadata, metadata.Image, nil
+}
+
+func (c *Token) saveMetadata(ctx context.Context, tokenId string, metadata *Metadata) {
+	updates := map[string]interface{}{"metadata": metadata, "storage_url": metadata.Image}
+	switch c.Category {
+	case Eip721Token:
+		sg.db.WithContext(ctx).Model(Erc721Holders{}).Where("contract = ?", c.Contract).Where("token_id =?", tokenId).UpdateColumns(updates)
+	case Eip1155Token:
+		sg.db.WithContext(ctx).Model(ERC1155Item{}).Where("id = ?", erc1155ItemId(c.Contract, tokenId)).UpdateColumns(updates)
+	}
+}
+
+func nftCachePath(contract, tokenId, ext string) string {
+	return filepath.Join(nftMetadataCacheDir, contract, fmt.Sprintf("%s.%s", filepath.Base(tokenId), ext))
+}
+
+// readMetadataCache cached copy of metadata on local disk
+func readMetadataCache(contract, tokenId string) *Metadata {
+	if nftMetadataCacheDir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(nftCachePath(contract, tokenId, "json"))
+	if err != nil {
+		return nil
+	}
+	metadata, _ := decodeMetadata(data)
+	return metadata
+}
+
+func writeMetadataCache(contract, tokenId string, metadata *Metadata) error {
+	if nftMetadataCacheDir == "" {
+		return nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	filename := nftCachePath(contract, tokenId, "json")
+	if err = os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+func thumbnailExt(image string) string {
+	if u, err := url.Parse(image); err == nil {
+		if ext := strings.TrimPrefix(path.Ext(u.Path), "."); util.StringInSliceFold(ext, []string{"svg", "png", "jpg", "jpeg", "bmp", "gif", "webp"}) {
+			return strings.ToLower(ext)
+		}
+	}
+	return "png"
+}
+
+// writeThumbnail download image of metadata, return local file path
+func writeThumbnail(ctx context.Context, contract, tokenId, image string) (string, error) {
+	if nftMetadataCacheDir == "" || !nftThumbnailEnabled || image == "" || strings.HasPrefix(image, "data:") {
+		return "", nil
+	}
+	data, err := ipfs.OpenImage(ctx, image)
+	if err != nil {
+		return "", err
+	}
+	filename := nftCachePath(contract, tokenId, thumbnailExt(image))
+	if err = os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return "", err
+	}
+	return filename, os.WriteFile(filename, data, 0644)
+}
+
+// FetchNftMetadata metadata queue job, failed fetch will be retried with backoff until max attempts
+func FetchNftMetadata(ctx context.Context, args *NftMetadataArgs) error {
+	token := GetTokenByContract(ctx, args.Contract)
+	if token == nil || (token.Category != Eip721Token && token.Category != Eip1155Token) {
+		return nil
+	}
+	record := GetNftMetadata(ctx, args.Contract, args.TokenId)
+	if record == nil {
+		record = &NftMetadata{Contract: args.Contract, TokenId: args.TokenId}
+	} else if record.Status == NftMetadataSuccess && !args.Force {
+		return nil
+	}
+	if args.Force && args.Attempt == 0 {
+		record.Attempts = 0
+	}
+	metadata := readMetadataCache(args.Contract, args.TokenId)
+	if metadata == nil || args.Force {
+		var (
+			data []byte
+			err  error
+		)
+		record.TokenUri, err = token.tokenUri(ctx, args.TokenId)
+		if err == nil {
+			data, err = openTokenUri(ctx, record.TokenUri)
+		}
+		if err == nil {
+			metadata, err = decodeMetadata(data)
+		}
+		if err != nil {
+			record.Attempts++
+			record.Error = err.Error()
+			switch {
+			case errors.Is(err, ErrNftMetadataSkipped):
+				record.Status = NftMetadataSkipped
+			case record.Attempts < nftMetadataMaxAttempts:
+				record.Status = NftMetadataRetrying
+				retry := NftMetadataArgs{Contract: args.Contract, TokenId: args.TokenId, Attempt: args.Attempt + 1, Force: args.Force}
+				if mqErr := PublishIn(EvmNftMetadata, "fetch", nftMetadataBackoff(args.Attempt), retry); mqErr != nil {
+					util.Logger().Error(fmt.Errorf("publish nft metadata retry %s/%s failed: %v", args.Contract, args.TokenId, mqErr))
+				}
+			default:
+				record.Status = NftMetadataFailed
+			}
+			return saveNftMetadataStatus(ctx, record)
+		}
+		util.Logger().Error(writeMetadataCache(args.Contract, args.TokenId, metadata))
+	}
+	token.saveMetadata(ctx, args.TokenId, metadata)
+	record.Status, record.Error = NftMetadataSuccess, ""
+	if thumbnail, err := writeThumbnail(ctx, args.Contract, args.TokenId, metadata.Image); err != nil {
+		// thumbnail is optional, metadata is still available
+		record.Error = fmt.Sprintf("thumbnail: %s", err.Error())
+	} else if thumbnail != "" {
+		record.Thumbnail = thumbnail
+	}
+	return saveNftMetadataStatus(ctx, record)
+}
+
+type NftMetadataStatusJson struct {
+	Contract string           `json:"contract"`
+	Status   map[string]int64 `json:"status"`
+	Failures []NftMetadata    `json:"failures"`
+}
+
+// NftMetadataStatus metadata fetch status count and recent failures of collection
+func (a *ApiSrv) NftMetadataStatus(ctx context.Context, contract string) *NftMetadataStatusJson {
+	var counts []struct {
+		Status string
+		Count  int64
+	}
+	sg.db.WithContext(ctx).Model(NftMetadata{}).Select("status, count(*) as count").Where("contract = ?", contract).Group("status").Scan(&counts)
+	res := NftMetadataStatusJson{Contract: contract, Status: make(map[string]int64), Failures: []NftMetadata{}}
+	for _, count := range counts {
+		res.Status[count.Status] = count.Count
+	}
+	sg.db.WithContext(ctx).Where("contract = ?", contract).Where("status in ?", []string{NftMetadataFailed, NftMetadataRetrying, NftMetadataSkipped}).
+		Order("updated_at desc").Limit(20).Find(&res.Failures)
+	return &res
+}
+
+// nftMetadataRefreshable not queued or retrying, and not refreshed within cooldown
+func nftMetadataRefreshable(record *NftMetadata, now time.Time) bool {
+	if record.Status == NftMetadataPending || record.Status == NftMetadataRetrying {
+		return false
+	}
+	return now.Sub(time.Unix(record.UpdatedAt, 0)) >= nftMetadataRefreshCooldown
+}
+
+// RefreshNftMetadata enqueue forced metadata fetch of token id
+func (a *ApiSrv) RefreshNftMetadata(ctx context.Context, contract, tokenId string) (*NftMetadata, error) {
+	token := GetTokenByContract(ctx, contract)
+	if token == nil || (token.Category != Eip721Token && token.Category != Eip1155Token) {
+		return nil, ErrNotNftToken
+	}
+	record := GetNftMetadata(ctx, contract, tokenId)
+	if record == nil {
+		record = &NftMetadata{Contract: contract, TokenId: tokenId}
+	} else if !nftMetadataRefreshable(record, time.Now()) {
+		return record, nil
+	}
+	record.Status, record.Attempts = NftMetadataPending, 0
+	if err := saveNftMetadataStatus(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, PublishNftMetadata(contract, tokenId, true)
+}
@@ -0,0 +1,67 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func Test_nftMetadataBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, nftMetadataBackoff(0))
+	assert.Equal(t, 2*time.Minute, nftMetadataBackoff(2))
+	assert.Equal(t, time.Hour, nftMetadataBackoff(10))
+}
+
+func Test_nftMetadataRefreshable(t *testing.T) {
+	now := time.Now()
+	updated := now.Add(-time.Hour).Unix()
+	assert.True(t, nftMetadataRefreshable(&NftMetadata{Status: NftMetadataSuccess, UpdatedAt: updated}, now))
+	assert.True(t, nftMetadataRefreshable(&NftMetadata{Status: NftMetadataFailed, UpdatedAt: updated}, now))
+	assert.False(t, nftMetadataRefreshable(&NftMetadata{Status: NftMetadataPending, UpdatedAt: updated}, now))
+	assert.False(t, nftMetadataRefreshable(&NftMetadata{Status: NftMetadataRetrying, UpdatedAt: updated}, now))
+	assert.False(t, nftMetadataRefreshable(&NftMetadata{Status: NftMetadataSuccess, UpdatedAt: now.Add(-time.Minute).Unix()}, now))
+}
+
+func Test_openTokenUri(t *testing.T) {
+	ctx := context.TODO()
+	for _, uri := range []string{"http://localhost:8080/1.json", "https://127.0.0.1/1.json", "did:dkg:otp/0x1/1", "unknown://1"} {
+		_, err := openTokenUri(ctx, uri)
+		assert.True(t, errors.Is(err, ErrNftMetadataSkipped), uri)
+	}
+	data, err := openTokenUri(ctx, `data:application/json;utf8,{"name":"a"}`)
+	assert.NoError(t, err)
+	metadata, err := decodeMetadata(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", metadata.Name)
+
+	data, err = openTokenUri(ctx, "data:application/json;base64,eyJuYW1lIjoiYiJ9")
+	assert.NoError(t, err)
+	metadata, err = decodeMetadata(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "b", metadata.Name)
+
+	_, err = decodeMetadata([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func Test_metadataCache(t *testing.T) {
+	origin := nftMetadataCacheDir
+	defer func() { nftMetadataCacheDir = origin }()
+
+	nftMetadataCacheDir = ""
+	assert.NoError(t, writeMetadataCache("0x1", "1", &Metadata{Name: "a"}))
+	assert.Nil(t, readMetadataCache("0x1", "1"))
+
+	nftMetadataCacheDir = t.TempDir()
+	assert.NoError(t, writeMetadataCache("0x1", "1", &Metadata{Name: "a", Image: "ipfs://cid/1.png"}))
+	metadata := readMetadataCache("0x1", "1")
+	assert.NotNil(t, metadata)
+	assert.Equal(t, "ipfs://cid/1.png", metadata.Image)
+	// token id can not escape cache dir
+	assert.Equal(t, nftCachePath("0x1", "../../1", "json"), nftCachePath("0x1", "1", "json"))
+
+	assert.Equal(t, "gif", thumbnailExt("https://example.com/a/1.GIF?v=1"))
+	assert.Equal(t, "png", thumbnailExt("ipfs://cid"))
+}
@@ -3,12 +3,18 @@ package dao
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/itering/subscan/model"
+	evmAbi "github.com/itering/subscan/plugins/evm/abi"
 	"github.com/itering/subscan/util"
 	"gorm.io/datatypes"
+	"os"
+	"strings"
 )
 
+// AbiMapping method is keyed by selector, event is keyed by topic0 and topics count (see eventMappingId),
+// erc20 and erc721 Transfer has same topic0 but different indexed arguments
 type AbiMapping struct {
 	Id      string         `json:"id" gorm:"primaryKey;autoIncrement:false;size:255"`
 	AbiFunc datatypes.JSON `json:"abi_func" es:"type:flattened"`
@@ -27,10 +33,20 @@ const (
 )
 
 func (c *Contract) fetchAbiMapping(ctx context.Context) error {
+	mappings, err := AbiMappings(c.Abi)
+	if err != nil {
+		return err
+	}
+	q := sg.db.WithContext(ctx).Scopes(model.IgnoreDuplicate).CreateInBatches(&mappings, 200)
+	return q.Error
+}
+
+// AbiMappings split abi json to method and event signature mappings
+func AbiMappings(abiRaw []byte) ([]AbiMapping, error) {
 	var mappings []AbiMapping
 	var abiValue abi.ABI
-	if err := abiValue.UnmarshalJSON(c.Abi); err != nil {
-		return err
+	if err := abiValue.UnmarshalJSON(abiRaw); err != nil {
+		return nil, err
 	}
 	type Argument struct {
 		Name    string `json:"name"`
@@ -101,13 +117,63 @@ func (c *Contract) fetchAbiMapping(ctx context.Context) error {
 			Anonymous: &e.Anonymous,
 		}
 		abiFunc, _ := json.Marshal(f)
+		indexed := 0
+		for _, input := range event.Inputs {
+			if input.Indexed {
+				indexed++
+			}
+		}
 		abiMapping := AbiMapping{
-			Id:      event.ID.Hex(),
+			Id:      eventMappingId(event.ID.Hex(), indexed+1),
 			AbiFunc: abiFunc,
 			AbiType: MethodTypeEvent,
 		}
 		mappings = append(mappings, abiMapping)
 	}
+	return mappings, nil
+}
+
+// SeedAbiMappings seed signature database with standard token abi
+func SeedAbiMappings(ctx context.Context) {
+	for _, abiRaw := range []string{evmAbi.Erc20, evmAbi.Erc721, evmAbi.Erc1155} {
+		mappings, err := AbiMappings([]byte(abiRaw))
+		if err != nil {
+			util.Logger().Error(err)
+			continue
+		}
+		sg.db.WithContext(ctx).Scopes(model.IgnoreDuplicate).CreateInBatches(&mappings, 200)
+	}
+}
+
+// ImportAbiMappings import signatures from abi json file, support abi array or artifact with abi field
+func ImportAbiMappings(ctx context.Context, file string) (int, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	var artifact struct {
+		Abi json.RawMessage `json:"abi"`
+	}
+	if json.Unmarshal(raw, &artifact) == nil && len(artifact.Abi) > 0 {
+		raw = artifact.Abi
+	}
+	mappings, err := AbiMappings(raw)
+	if err != nil {
+		return 0, err
+	}
 	q := sg.db.WithContext(ctx).Scopes(model.IgnoreDuplicate).CreateInBatches(&mappings, 200)
-	return q.Error
+	return int(q.RowsAffected), q.Error
+}
+
+// eventMappingId topic0 with topics count of log, e.g. 0xddf252ad..._3
+func eventMappingId(topic0 string, topics int) string {
+	return fmt.Sprintf("%s_%d", strings.ToLower(topic0), topics)
+}
+
+func GetAbiMapping(ctx context.Context, id string) *AbiMapping {
+	var mapping AbiMapping
+	if q := sg.db.WithContext(ctx).Where("id = ?", id).First(&mapping); q.Error != nil {
+		return nil
+	}
+	return &mapping
 }
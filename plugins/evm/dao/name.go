@@ -0,0 +1,312 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/itering/subscan/util"
+	"golang.org/x/net/idna"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"os"
+	"strings"
+	"unicode"
+)
+
+var (
+	// nameRegistry ENS-compatible registry contract, name service is disabled if empty
+	nameRegistry = strings.ToLower(os.Getenv("ENS_REGISTRY"))
+	// nameResolver resolver contract of forward address and reverse name records
+	nameResolver = strings.ToLower(os.Getenv("ENS_RESOLVER"))
+
+	ErrNameServiceDisabled = errors.New("name service is disabled")
+	ErrNameNotFound        = errors.New("name not found")
+	ErrNameInvalid         = errors.New("name is not normalized")
+)
+
+var (
+	ensNewOwner    = eventTopic("NewOwner(bytes32,bytes32,address)")
+	ensTransfer    = eventTopic("Transfer(bytes32,address)")
+	ensNewResolver = eventTopic("NewResolver(bytes32,address)")
+	ensAddrChanged = eventTopic("AddrChanged(bytes32,address)")
+	ensNameChanged = eventTopic("NameChanged(bytes32,string)")
+)
+
+const reverseSuffix = "addr.reverse"
+
+func eventTopic(signature string) string {
+	return util.AddHex(util.BytesToHex(crypto.Keccak256([]byte(signature))))
+}
+
+// NameRecord node of registry, name is known once it is set as a reverse name.
+// each field keeps receipt id of the log set it, blocks are processed concurrently and an older log never overwrites a newer one
+type NameRecord struct {
+	Node            string `json:"node" gorm:"primaryKey;autoIncrement:false;size:70"`
+	Parent          string `json:"parent" gorm:"size:70"`
+	LabelHash       string `json:"label_hash" gorm:"size:70"`
+	Name            string `json:"name" gorm:"size:255;index:name"`
+	Owner           string `json:"owner" gorm:"size:70;index:owner"`
+	OwnerReceipt    uint64 `json:"-"`
+	Resolver        string `json:"resolver" gorm:"size:70"`
+	ResolverReceipt uint64 `json:"-"`
+	Address         string `json:"address" gorm:"size:70;index:address"`
+	AddressReceipt  uint64 `json:"-"`
+	BlockNum        uint64 `json:"block_num"`
+}
+
+func (n *NameRecord) TableName() string {
+	return "evm_name_records"
+}
+
+// NameReverseRecord name set on resolver, node of address is namehash("<hex address>.addr.reverse")
+type NameReverseRecord struct {
+	Node      string `json:"node" gorm:"primaryKey;autoIncrement:false;size:70"`
+	Name      string `json:"name" gorm:"size:255"`
+	BlockNum  uint64 `json:"block_num"`
+	ReceiptId uint64 `json:"-"`
+}
+
+func (n *NameReverseRecord) TableName() string {
+	return "evm_name_reverse_records"
+}
+
+func NameServiceEnabled() bool {
+	return nameRegistry != ""
+}
+
+var nameProfile = idna.New(idna.MapForLookup(), idna.Transitional(false), idna.StrictDomainName(false), idna.CheckHyphens(false), idna.BidiRule())
+
+// NormalizeName ENSIP-15 normalization, UTS-46 non-transitional mapping with ENS label rules,
+// names must be normalized before namehash
+func NormalizeName(name string) (string, error) {
+	// punycode label is not decoded, ENS names are unicode
+	for _, label := range strings.Split(name, ".") {
+		if len(label) >= 4 && label[2:4] == "--" && isASCII(label) {
+			return "", ErrNameInvalid
+		}
+	}
+	normalized, err := nameProfile.ToUnicode(name)
+	if err != nil || normalized == "" {
+		return "", ErrNameInvalid
+	}
+	for _, label := range strings.Split(normalized, ".") {
+		if label == "" || (len(label) >= 4 && label[2:4] == "--" && isASCII(label)) {
+			return "", ErrNameInvalid
+		}
+		// underscore is allowed only at the beginning of label
+		leading := len(label) - len(strings.TrimLeft(label, "_"))
+		for index, r := range label {
+			if unicode.IsSpace(r) || unicode.IsControl(r) || (r == '_' && index >= leading) {
+				return "", ErrNameInvalid
+			}
+		}
+	}
+	return normalized, nil
+}
+
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// Namehash EIP-137 namehash of normalized name
+func Namehash(name string) string {
+	node := make([]byte, 32)
+	if name != "" {
+		labels := strings.Split(name, ".")
+		for i := len(labels) - 1; i >= 0; i-- {
+			node = crypto.Keccak256(node, crypto.Keccak256([]byte(labels[i])))
+		}
+	}
+	return util.AddHex(util.BytesToHex(node))
+}
+
+func reverseNode(address string) string {
+	return Namehash(strings.ToLower(util.TrimHex(address)) + "." + reverseSuffix)
+}
+
+func subNode(node, labelHash string) string {
+	return util.AddHex(util.BytesToHex(crypto.Keccak256(util.HexToBytes(node), util.HexToBytes(labelHash))))
+}
+
+// dataAddress address of first abi word
+func dataAddress(data string) string {
+	data = util.TrimHex(data)
+	if len(data) < 64 {
+		return ""
+	}
+	return util.AddHex(strings.ToLower(data[24:64]))
+}
+
+func dataString(data string) string {
+	stringType, _ := abi.NewType("string", "", nil)
+	values, err := abi.Arguments{{Type: stringType}}.Unpack(util.HexToBytes(data))
+	if err != nil || len(values) == 0 {
+		return ""
+	}
+	return util.ToString(values[0])
+}
+
+// saveNameField set field of node record if log is newer than the one set it, receipt id is ordered by (block_num, log index)
+func saveNameField(ctx context.Context, record *NameRecord, field string, value string, receiptId uint64) error {
+	if q := sg.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(record); q.Error != nil || q.RowsAffected > 0 {
+		return q.Error
+	}
+	return sg.db.WithContext(ctx).Model(NameRecord{}).Where("node = ?", record.Node).Where(field+"_receipt < ?", receiptId).
+		Updates(map[string]interface{}{field: value, field + "_receipt": receiptId, "block_num": gorm.Expr("GREATEST(block_num, ?)", record.BlockNum)}).Error
+}
+
+// learnName name of forward node is learned from reverse name, only normalized name is kept
+func learnName(ctx context.Context, name string) {
+	normalized, err := NormalizeName(name)
+	if err != nil || normalized != name {
+		return
+	}
+	sg.db.WithContext(ctx).Model(NameRecord{}).Where("node = ?", Namehash(normalized)).Where("name = ''").Update("name", normalized)
+}
+
+// processNameLog apply registry and resolver event, logs of different blocks may be applied out of order
+func processNameLog(ctx context.Context, r *TransactionReceipt) error {
+	topics := strings.Split(strings.ToLower(r.Topics), ",")
+	if len(topics) < 2 {
+		return nil
+	}
+	node := topics[1]
+	switch address := strings.ToLower(r.Address); {
+	case address == nameRegistry && topics[0] == ensNewOwner && len(topics) >= 3:
+		record := NameRecord{Node: subNode(node, topics[2]), Parent: node, LabelHash: topics[2], Owner: dataAddress(r.Data), OwnerReceipt: r.Id, BlockNum: r.BlockNum}
+		if err := saveNameField(ctx, &record, "owner", record.Owner, r.Id); err != nil {
+			return err
+		}
+		// parent and label of node never change
+		return sg.db.WithContext(ctx).Model(NameRecord{}).Where("node = ?", record.Node).Where("label_hash = ''").
+			Updates(map[string]interface{}{"parent": record.Parent, "label_hash": record.LabelHash}).Error
+	case address == nameRegistry && topics[0] == ensTransfer:
+		record := NameRecord{Node: node, Owner: dataAddress(r.Data), OwnerReceipt: r.Id, BlockNum: r.BlockNum}
+		return saveNameField(ctx, &record, "owner", record.Owner, r.Id)
+	case address == nameRegistry && topics[0] == ensNewResolver:
+		record := NameRecord{Node: node, Resolver: dataAddress(r.Data), ResolverReceipt: r.Id, BlockNum: r.BlockNum}
+		return saveNameField(ctx, &record, "resolver", record.Resolver, r.Id)
+	case address == nameResolver && topics[0] == ensAddrChanged:
+		record := NameRecord{Node: node, Address: dataAddress(r.Data), AddressReceipt: r.Id, BlockNum: r.BlockNum}
+		return saveNameField(ctx, &record, "address", record.Address, r.Id)
+	case address == nameResolver && topics[0] == ensNameChanged:
+		name := dataString(r.Data)
+		record := NameReverseRecord{Node: node, Name: name, BlockNum: r.BlockNum, ReceiptId: r.Id}
+		q := sg.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if q.Error != nil {
+			return q.Error
+		}
+		if q.RowsAffected == 0 {
+			if err := sg.db.WithContext(ctx).Model(NameReverseRecord{}).Where("node = ?", node).Where("receipt_id < ?", r.Id).
+				Updates(map[string]interface{}{"name": name, "block_num": r.BlockNum, "receipt_id": r.Id}).Error; err != nil {
+				return err
+			}
+		}
+		learnName(ctx, name)
+	}
+	return nil
+}
+
+// ProcessNameServiceLogs index registry and resolver logs of block
+func ProcessNameServiceLogs(ctx context.Context, logs []TransactionReceipt) error {
+	if !NameServiceEnabled() {
+		return nil
+	}
+	for index := range logs {
+		if err := processNameLog(ctx, &logs[index]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrimaryNames reverse names of addresses, name is primary only if it resolves to the address
+func PrimaryNames(ctx context.Context, addresses []string) map[string]string {
+	names := make(map[string]string)
+	if !NameServiceEnabled() || len(addresses) == 0 {
+		return names
+	}
+	node2Address := make(map[string]string)
+	for _, address := range addresses {
+		if address != "" {
+			node2Address[reverseNode(address)] = strings.ToLower(address)
+		}
+	}
+	var nodes []string
+	for node := range node2Address {
+		nodes = append(nodes, node)
+	}
+	var reverses []NameReverseRecord
+	sg.db.WithContext(ctx).Where("node in ?", nodes).Where("name != ''").Find(&reverses)
+	if len(reverses) == 0 {
+		return names
+	}
+	// name not normalized is never primary
+	normalized := make(map[string]string)
+	var forwardNodes []string
+	for _, reverse := range reverses {
+		if name, err := NormalizeName(reverse.Name); err == nil && name == reverse.Name {
+			normalized[reverse.Node] = name
+			forwardNodes = append(forwardNodes, Namehash(name))
+		}
+	}
+	if len(forwardNodes) == 0 {
+		return names
+	}
+	var records []NameRecord
+	sg.db.WithContext(ctx).Where("node in ?", forwardNodes).Find(&records)
+	resolved := make(map[string]string)
+	for _, record := range records {
+		resolved[record.Node] = record.Address
+	}
+	for node, name := range normalized {
+		if address := node2Address[node]; resolved[Namehash(name)] == address {
+			names[address] = name
+		}
+	}
+	return names
+}
+
+type NameReverseJson struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+}
+
+// ResolveName forward lookup of name
+func (a *ApiSrv) ResolveName(ctx context.Context, name string) (*NameRecord, error) {
+	if !NameServiceEnabled() {
+		return nil, ErrNameServiceDisabled
+	}
+	name, err := NormalizeName(strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+	var record NameRecord
+	if q := sg.db.WithContext(ctx).Where("node = ?", Namehash(name)).First(&record); q.Error != nil {
+		return nil, ErrNameNotFound
+	}
+	// label of node is unknown until its name is indexed, the looked up name is only returned
+	if record.Name == "" {
+		record.Name = name
+	}
+	return &record, nil
+}
+
+// ReverseName primary name of address
+func (a *ApiSrv) ReverseName(ctx context.Context, address string) (*NameReverseJson, error) {
+	if !NameServiceEnabled() {
+		return nil, ErrNameServiceDisabled
+	}
+	address = strings.ToLower(address)
+	name, ok := PrimaryNames(ctx, []string{address})[address]
+	if !ok {
+		return nil, ErrNameNotFound
+	}
+	return &NameReverseJson{Address: address, Name: name}, nil
+}
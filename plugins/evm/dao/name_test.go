@@ -0,0 +1,46 @@
+package dao
+
+import (
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/itering/subscan/util"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_Namehash(t *testing.T) {
+	assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000000", Namehash(""))
+	assert.Equal(t, "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae", Namehash("eth"))
+	assert.Equal(t, "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f", Namehash("foo.eth"))
+	labelHash := util.AddHex(util.BytesToHex(crypto.Keccak256([]byte("foo"))))
+	assert.Equal(t, Namehash("foo.eth"), subNode(Namehash("eth"), labelHash))
+	assert.Equal(t, Namehash("1111111111111111111111111111111111111111.addr.reverse"), reverseNode("0x1111111111111111111111111111111111111111"))
+}
+
+func Test_NameLogData(t *testing.T) {
+	assert.Equal(t, "0xce0457fe73731f824cc272376169235128c118b49d344817417c6d108d155e82", ensNewOwner)
+	assert.Equal(t, "0x1111111111111111111111111111111111111111", dataAddress("0x0000000000000000000000001111111111111111111111111111111111111111"))
+	assert.Equal(t, "", dataAddress("0x00"))
+	// abi encoded "alice.eth"
+	data := "0x0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000009" +
+		"616c6963652e6574680000000000000000000000000000000000000000000000"
+	assert.Equal(t, "alice.eth", dataString(data))
+	assert.Equal(t, "", dataString("0x"))
+}
+
+func Test_NormalizeName(t *testing.T) {
+	for name, want := range map[string]string{
+		"Foo.eth":   "foo.eth",
+		"ＡＬＩＣＥ.eth": "alice.eth",
+		"🚀.eth":     "🚀.eth",
+		"_a.eth":    "_a.eth",
+	} {
+		normalized, err := NormalizeName(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, normalized)
+	}
+	for _, name := range []string{"", "a..eth", "a b.eth", "a_b.eth", "xn--ls8h.eth", "ab\u200d.eth"} {
+		_, err := NormalizeName(name)
+		assert.ErrorIs(t, err, ErrNameInvalid, name)
+	}
+}
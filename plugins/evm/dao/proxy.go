@@ -0,0 +1,141 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/itering/subscan/model"
+	"github.com/itering/subscan/plugins/evm/feature/delegateProxy"
+	"github.com/itering/subscan/share/web3"
+	"gorm.io/gorm"
+)
+
+// ContractFacet facet of EIP-2535 diamond
+type ContractFacet struct {
+	Id      uint   `json:"-" gorm:"primaryKey;autoIncrement;size:32"`
+	Address string `json:"address" gorm:"size:100;index:address_facet,unique"`
+	Facet   string `json:"facet" gorm:"size:100;index:address_facet,unique"`
+}
+
+func (c *ContractFacet) TableName() string {
+	return "evm_contract_facets"
+}
+
+// detectProxy detect proxy standard and implementation, diamond facets will be saved
+func (c *Contract) detectProxy(ctx context.Context) {
+	if standard, implementation := delegateProxy.Detect(ctx, web3.RPC, c.Address); implementation != "" {
+		c.EipStandard = standard
+		c.ProxyImplementation = implementation
+		return
+	}
+	if facets, _ := refreshDiamondFacets(ctx, c.Address); len(facets) > 0 {
+		c.EipStandard = delegateProxy.EIP2535Standard
+	}
+}
+
+// RefreshProxy refresh proxy implementation, run by evm_proxy queue after deployed code has DELEGATECALL
+// or Upgraded/BeaconUpgraded/DiamondCut emitted
+func RefreshProxy(ctx context.Context, address string) error {
+	contract := GetContract(ctx, address)
+	if contract == nil {
+		return nil
+	}
+	contract.detectProxy(ctx)
+	if contract.EipStandard == "" {
+		return nil
+	}
+	return sg.db.WithContext(ctx).Model(Contract{}).Where("address = ?", address).
+		UpdateColumns(map[string]interface{}{"eip_standard": contract.EipStandard, "proxy_implementation": contract.ProxyImplementation}).Error
+}
+
+func refreshDiamondFacets(ctx context.Context, address string) ([]string, error) {
+	facets, err := delegateProxy.Init2535(web3.RPC, address).Facets(ctx)
+	if err != nil || len(facets) == 0 {
+		return nil, err
+	}
+	var list []ContractFacet
+	for _, facet := range facets {
+		list = append(list, ContractFacet{Address: address, Facet: facet})
+	}
+	return facets, sg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err = tx.Where("address = ?", address).Delete(ContractFacet{}).Error; err != nil {
+			return err
+		}
+		return tx.Scopes(model.IgnoreDuplicate).Create(&list).Error
+	})
+}
+
+func ContractFacets(ctx context.Context, address string) (facets []string) {
+	sg.db.WithContext(ctx).Model(ContractFacet{}).Where("address = ?", address).Order("id asc").Pluck("facet", &facets)
+	return
+}
+
+// proxyAbi merged abi of verified implementation and diamond facets
+func proxyAbi(ctx context.Context, c *Contract) *abi.ABI {
+	var abis []*abi.ABI
+	addresses := ContractFacets(ctx, c.Address)
+	if c.ProxyImplementation != "" {
+		addresses = append([]string{c.ProxyImplementation}, addresses...)
+	}
+	for _, address := range addresses {
+		if implementation := GetContract(ctx, address); implementation != nil && implementation.VerifyStatus != "" {
+			abis = append(abis, parseAbi(implementation.Abi))
+		}
+	}
+	return mergeAbi(abis...)
+}
+
+// contractMergedAbi abi of contract merged with proxy abi
+func contractMergedAbi(ctx context.Context, c *Contract) *abi.ABI {
+	var own *abi.ABI
+	if len(c.Abi) > 0 {
+		own = parseAbi(c.Abi)
+	}
+	if c.EipStandard == "" {
+		return own
+	}
+	return mergeAbi(own, proxyAbi(ctx, c))
+}
+
+// mergeAbi merge methods and events, same signature will keep the first one
+func mergeAbi(abis ...*abi.ABI) *abi.ABI {
+	var merged *abi.ABI
+	methodSigs, eventSigs := make(map[string]bool), make(map[string]bool)
+	for _, value := range abis {
+		if value == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &abi.ABI{Constructor: value.Constructor, Fallback: value.Fallback, Receive: value.Receive,
+				Methods: make(map[string]abi.Method), Events: make(map[string]abi.Event), Errors: make(map[string]abi.Error)}
+		}
+		for name, method := range value.Methods {
+			if methodSigs[method.Sig] {
+				continue
+			}
+			methodSigs[method.Sig] = true
+			merged.Methods[uniqueAbiKey(name, func(key string) bool { _, ok := merged.Methods[key]; return ok })] = method
+		}
+		for name, event := range value.Events {
+			if eventSigs[event.Sig] {
+				continue
+			}
+			eventSigs[event.Sig] = true
+			merged.Events[uniqueAbiKey(name, func(key string) bool { _, ok := merged.Events[key]; return ok })] = event
+		}
+		for name, abiError := range value.Errors {
+			if _, ok := merged.Errors[name]; !ok {
+				merged.Errors[name] = abiError
+			}
+		}
+	}
+	return merged
+}
+
+func uniqueAbiKey(name string, exists func(string) bool) string {
+	key := name
+	for index := 0; exists(key); index++ {
+		key = fmt.Sprintf("%s%d", name, index)
+	}
+	return key
+}
@@ -0,0 +1,24 @@
+package dao
+
+import (
+	evmAbi "github.com/itering/subscan/plugins/evm/abi"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_MergeAbi(t *testing.T) {
+	proxy := parseAbi([]byte(evmAbi.EIP1967))
+	implementation := parseAbi([]byte(evmAbi.Erc20))
+	merged := mergeAbi(proxy, nil, implementation)
+	assert.NotNil(t, merged)
+	// upgradeTo is in proxy abi, transfer in implementation abi
+	_, err := findMethod(merged, "upgradeTo(address)")
+	assert.NoError(t, err)
+	_, err = findMethod(merged, "transfer(address,uint256)")
+	assert.NoError(t, err)
+	assert.Len(t, merged.Events, len(proxy.Events)+len(implementation.Events))
+
+	// same signature keep once
+	assert.Len(t, mergeAbi(implementation, implementation).Methods, len(implementation.Methods))
+	assert.Nil(t, mergeAbi(nil))
+}
@@ -70,3 +70,40 @@ func SplitReceiptData(abiStr string, method, data string) []interface{} {
 	}
 	return events
 }
+
+type ReceiptJson struct {
+	Id              uint64        `json:"id"`
+	Address         string        `json:"address"`
+	Topics          []string      `json:"topics"`
+	Data            string        `json:"data"`
+	Index           int           `json:"index"`
+	BlockNum        uint64        `json:"block_num"`
+	BlockTimestamp  uint          `json:"block_timestamp"`
+	TransactionHash string        `json:"transaction_hash"`
+	Decoded         *DecodedEvent `json:"decoded,omitempty"`
+}
+
+// DecodeReceipts receipts with decoded event
+func DecodeReceipts(ctx context.Context, decoder *AbiDecoder, list []TransactionReceipt) []ReceiptJson {
+	var res []ReceiptJson
+	for index := range list {
+		r := list[index]
+		res = append(res, ReceiptJson{
+			Id:              r.Id,
+			Address:         r.Address,
+			Topics:          strings.Split(r.Topics, ","),
+			Data:            util.AddHex(r.Data),
+			Index:           r.Index,
+			BlockNum:        r.BlockNum,
+			BlockTimestamp:  r.BlockTimestamp,
+			TransactionHash: r.TransactionHash,
+			Decoded:         decoder.DecodeLog(ctx, &r),
+		})
+	}
+	return res
+}
+
+func ReceiptsByHash(ctx context.Context, hash string) (list []TransactionReceipt) {
+	sg.db.WithContext(ctx).Where("transaction_hash = ?", hash).Order("id asc").Find(&list)
+	return
+}
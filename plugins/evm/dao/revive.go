@@ -0,0 +1,176 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/itering/subscan-plugin/storage"
+	"github.com/itering/subscan/model"
+	evmContract "github.com/itering/subscan/plugins/evm/contract"
+	"github.com/itering/subscan/share/web3"
+	"github.com/itering/subscan/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+)
+
+var ErrReviveCodeNotFound = errors.New("revive code not found")
+
+// ReviveCode PolkaVM code blob uploaded to revive pallet
+type ReviveCode struct {
+	CodeHash       string          `json:"code_hash" gorm:"primaryKey;autoIncrement:false;size:70"`
+	Uploader       string          `json:"uploader" gorm:"size:100;index:uploader"`
+	Deposit        decimal.Decimal `json:"deposit" gorm:"default: 0;type:decimal(65);"`
+	BlobSize       uint            `json:"blob_size"`
+	BlockNum       uint            `json:"block_num"`
+	BlockTimestamp uint            `json:"block_timestamp"`
+	ExtrinsicIndex string          `json:"extrinsic_index" gorm:"size:100"`
+	Removed        bool            `json:"removed"`
+}
+
+func (c *ReviveCode) TableName() string {
+	return "evm_revive_codes"
+}
+
+type ReviveCodeJson struct {
+	ReviveCode
+	ContractCount int64 `json:"contract_count"`
+}
+
+// reviveAddress h160 of revive account, account id mapped from h160 is padded with 0xee
+func reviveAddress(value interface{}) string {
+	account := strings.ToLower(util.TrimHex(util.ToString(value)))
+	if len(account) == 64 && strings.HasSuffix(account, strings.Repeat("e", 24)) {
+		account = account[:40]
+	}
+	if len(account) == 40 {
+		return util.AddHex(account)
+	}
+	return model.CheckoutParamValueAddress(value)
+}
+
+func reviveCodeHash(value interface{}) string {
+	return util.AddHex(strings.ToLower(util.TrimHex(util.ToString(value))))
+}
+
+func reviveParam(params []storage.EventParam, index int) interface{} {
+	if index < len(params) {
+		return params[index].Value
+	}
+	return nil
+}
+
+// ProcessReviveEvent index contract instantiation, code upload and storage deposit from revive events
+func ProcessReviveEvent(ctx context.Context, block *storage.Block, event *storage.Event) error {
+	var params []storage.EventParam
+	if err := util.UnmarshalAny(&params, event.Params); err != nil || len(params) == 0 {
+		return nil
+	}
+	extrinsicIndex := fmt.Sprintf("%d-%d", event.BlockNum, event.ExtrinsicIdx)
+	switch event.EventId {
+	// [deployer, contract]
+	case "Instantiated":
+		return reviveInstantiated(ctx, block, extrinsicIndex, reviveAddress(reviveParam(params, 0)), reviveAddress(reviveParam(params, 1)))
+	// [code_hash, deposit_held, uploader]
+	case "CodeStored":
+		code := ReviveCode{
+			CodeHash:       reviveCodeHash(params[0].Value),
+			Deposit:        util.DecimalFromInterface(reviveParam(params, 1)),
+			BlockNum:       uint(block.BlockNum),
+			BlockTimestamp: uint(block.BlockTimestamp),
+			ExtrinsicIndex: extrinsicIndex,
+		}
+		if uploader := reviveParam(params, 2); uploader != nil {
+			code.Uploader = reviveAddress(uploader)
+		}
+		return sg.AddOrUpdateItem(ctx, &code, []string{"code_hash"}, "uploader", "deposit", "block_num", "block_timestamp", "extrinsic_index", "removed").Error
+	// [code_hash, deposit_released, remover]
+	case "CodeRemoved":
+		return sg.db.WithContext(ctx).Model(ReviveCode{}).Where("code_hash = ?", reviveCodeHash(params[0].Value)).
+			Updates(map[string]interface{}{"removed": true, "deposit": 0}).Error
+	// [contract, new_code_hash, old_code_hash]
+	case "ContractCodeUpdated":
+		return sg.db.WithContext(ctx).Model(Contract{}).Where("address = ?", reviveAddress(params[0].Value)).
+			Update("code_hash", reviveCodeHash(reviveParam(params, 1))).Error
+	// [from, to, amount], contract is the receiver of held deposit
+	case "StorageDepositTransferredAndHeld":
+		return incrStorageDeposit(ctx, reviveAddress(reviveParam(params, 1)), util.DecimalFromInterface(reviveParam(params, 2)))
+	// [from, to, amount], contract is the sender of released deposit
+	case "StorageDepositTransferredAndReleased":
+		return incrStorageDeposit(ctx, reviveAddress(params[0].Value), util.DecimalFromInterface(reviveParam(params, 2)).Neg())
+	// [contract, data, topics]
+	case "ContractEmitted":
+		return ensureReviveContract(ctx, reviveAddress(params[0].Value))
+	}
+	return nil
+}
+
+// reviveInstantiated contract instantiated by substrate extrinsic or ethereum transaction
+func reviveInstantiated(ctx context.Context, block *storage.Block, extrinsicIndex, deployer, address string) error {
+	if address == "" {
+		return nil
+	}
+	contract := &Contract{
+		Address:        address,
+		Deployer:       deployer,
+		BlockNum:       uint(block.BlockNum),
+		DeployAt:       uint(block.BlockTimestamp),
+		ExtrinsicIndex: extrinsicIndex,
+	}
+	if err := sg.AddOrUpdateItem(ctx, contract, []string{"address"}, "deployer", "block_num", "deploy_at", "extrinsic_index").Error; err != nil {
+		return err
+	}
+	return Publish(EvmContract, "revive_code", address)
+}
+
+// ensureReviveContract contract instantiated before indexed, deploy info is unknown
+func ensureReviveContract(ctx context.Context, address string) error {
+	if address == "" || GetContract(ctx, address) != nil {
+		return nil
+	}
+	if err := sg.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&Contract{Address: address}).Error; err != nil {
+		return err
+	}
+	return Publish(EvmContract, "revive_code", address)
+}
+
+// RefreshReviveCode evm_contract queue job, code hash of PolkaVM contract is keccak256 of blob, same as deploy code hash
+func RefreshReviveCode(ctx context.Context, address string) error {
+	code, err := web3.RPC.Eth.GetCode(ctx, address, "latest")
+	if err != nil {
+		return err
+	}
+	if !evmContract.IsPvmBlob(code) {
+		return nil
+	}
+	codeHash := DeployCodeHash(code)
+	q := sg.db.WithContext(ctx).Model(Contract{}).Where("address = ?", address).
+		Updates(map[string]interface{}{"code_hash": codeHash, "deploy_code_hash": codeHash})
+	if q.Error != nil {
+		return q.Error
+	}
+	sg.db.WithContext(ctx).Model(ReviveCode{}).Where("code_hash = ?", codeHash).Where("blob_size = 0").
+		Update("blob_size", len(util.TrimHex(code))/2)
+	if contract := GetContract(ctx, address); contract != nil {
+		return contract.matchVerifiedSimilar(ctx)
+	}
+	return nil
+}
+
+func incrStorageDeposit(ctx context.Context, address string, amount decimal.Decimal) error {
+	if address == "" || amount.IsZero() {
+		return nil
+	}
+	return sg.db.WithContext(ctx).Model(Contract{}).Where("address = ?", address).
+		UpdateColumn("storage_deposit", gorm.Expr("storage_deposit + ?", amount)).Error
+}
+
+func (a *ApiSrv) ReviveCode(ctx context.Context, codeHash string) (*ReviveCodeJson, error) {
+	var code ReviveCodeJson
+	if q := sg.db.WithContext(ctx).Where("code_hash = ?", reviveCodeHash(codeHash)).First(&code.ReviveCode); q.Error != nil {
+		return nil, ErrReviveCodeNotFound
+	}
+	sg.db.WithContext(ctx).Model(Contract{}).Where("code_hash = ?", code.CodeHash).Count(&code.ContractCount)
+	return &code, nil
+}
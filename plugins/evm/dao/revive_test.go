@@ -0,0 +1,17 @@
+package dao
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_ReviveAddress(t *testing.T) {
+	h160 := "0x8eb0d8d4b9b8c5da8e6bd3e3ff1c9ed1c6e0e6ed"
+	assert.Equal(t, h160, reviveAddress("0x8EB0D8D4B9B8C5DA8E6BD3E3FF1C9ED1C6E0E6ED"))
+	assert.Equal(t, h160, reviveAddress(reviveAccount(h160)))
+	assert.Equal(t, "0xabcd", reviveCodeHash("ABCD"))
+}
+
+func Test_DeployCodeHashPvm(t *testing.T) {
+	assert.NotEqual(t, DeployCodeHash("0x50564d0001a10000000004"), DeployCodeHash("0x50564d0001a1ffffff0004"))
+}
@@ -0,0 +1,147 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/crypto"
+	evmContract "github.com/itering/subscan/plugins/evm/contract"
+	"github.com/itering/subscan/plugins/evm/feature/delegateProxy"
+	"github.com/itering/subscan/share/web3"
+	"github.com/itering/subscan/util"
+	"strings"
+	"time"
+)
+
+// DeployCodeHash keccak256 of runtime bytecode without cbor metadata,
+// contracts compiled from same source with different metadata have same hash.
+// PolkaVM blob is hashed as a whole, same as revive code hash
+func DeployCodeHash(code string) string {
+	trimmed := evmContract.TrimBytecodeMetadata(code)
+	if evmContract.IsPvmBlob(code) {
+		trimmed = util.TrimHex(code)
+	}
+	if trimmed == "" {
+		return ""
+	}
+	return util.AddHex(util.BytesToHex(crypto.Keccak256(util.HexToBytes(trimmed))))
+}
+
+func (c *Contract) refreshDeployCodeHash(ctx context.Context) error {
+	code, err := web3.RPC.Eth.GetCode(ctx, c.Address, "latest")
+	if err != nil {
+		return err
+	}
+	c.DeployCodeHash = DeployCodeHash(code)
+	// proxy is detected only if bytecode can delegate call
+	if delegateProxy.HasDelegateCall(code) {
+		_ = Publish(EvmProxy, "detect", c.Address)
+	}
+	return sg.db.WithContext(ctx).Model(Contract{}).Where("address = ?", c.Address).Update("deploy_code_hash", c.DeployCodeHash).Error
+}
+
+// matchVerifiedSimilar verify new contract by verified contract with same deploy code hash
+func (c *Contract) matchVerifiedSimilar(ctx context.Context) error {
+	if c.DeployCodeHash == "" || c.VerifyStatus != "" {
+		return nil
+	}
+	var source Contract
+	query := sg.db.WithContext(ctx).Where("deploy_code_hash = ?", c.DeployCodeHash).Where("verify_status != ''").
+		Where("verify_type != ?", VerifySimilarMatch).First(&source)
+	if query.Error != nil {
+		return nil
+	}
+	return c.verifyBySimilar(ctx, &source)
+}
+
+// ProcessContractCode evm_contract queue job of new contract, fetch runtime code hash and match verified contract with same hash
+func ProcessContractCode(ctx context.Context, address string) error {
+	c := GetContract(ctx, address)
+	if c == nil {
+		return nil
+	}
+	if c.DeployCodeHash == "" {
+		if err := c.refreshDeployCodeHash(ctx); err != nil {
+			return err
+		}
+	}
+	return c.matchVerifiedSimilar(ctx)
+}
+
+// ProcessSimilarContracts evm_contract queue job after contract verified
+func ProcessSimilarContracts(ctx context.Context, address string) error {
+	c := GetContract(ctx, address)
+	if c == nil || c.VerifyStatus == "" {
+		return nil
+	}
+	if c.DeployCodeHash == "" {
+		if err := c.refreshDeployCodeHash(ctx); err != nil {
+			return err
+		}
+	}
+	return c.verifySimilarContracts(ctx)
+}
+
+// verifySimilarContracts mark unverified contracts with same deploy code hash as similar match
+func (c *Contract) verifySimilarContracts(ctx context.Context) error {
+	if c.DeployCodeHash == "" {
+		return nil
+	}
+	var list []Contract
+	sg.db.WithContext(ctx).Where("deploy_code_hash = ?", c.DeployCodeHash).Where("verify_status = ''").
+		Where("address != ?", c.Address).Find(&list)
+	// one failed contract does not stop the others
+	var err error
+	for index := range list {
+		if e := list[index].verifyBySimilar(ctx, c); e != nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// verifyBySimilar copy abi, source code and compiler settings from verified contract
+func (c *Contract) verifyBySimilar(ctx context.Context, source *Contract) error {
+	c.Abi = source.Abi
+	c.SourceCode = source.SourceCode
+	c.ContractName = source.ContractName
+	c.MethodIdentifiers = source.MethodIdentifiers
+	c.CompilerVersion = source.CompilerVersion
+	c.EvmVersion = source.EvmVersion
+	c.ExternalLibraries = source.ExternalLibraries
+	c.Optimize = source.Optimize
+	c.OptimizationRuns = source.OptimizationRuns
+	c.CompileSettings = source.CompileSettings
+	c.VerifyType = VerifySimilarMatch
+	c.VerifyStatus = evmContract.VerifiedPartial
+	c.VerifyTime = uint(time.Now().Unix())
+	// constructor arguments is the remaining of creation code
+	if source.CreationBytecode != "" && strings.HasPrefix(c.CreationCode, source.CreationBytecode) {
+		c.CreationBytecode = source.CreationBytecode
+		c.ConstructorArguments = strings.TrimPrefix(c.CreationCode, source.CreationBytecode)
+	}
+	c.afterVerify(ctx)
+	return sg.db.WithContext(ctx).Model(Contract{}).Where("address = ?", c.Address).Updates(c).Error
+}
+
+// RefreshSimilarMatch backfill deploy code hash of contracts, then verify similar contracts
+func RefreshSimilarMatch(ctx context.Context) error {
+	var addresses []string
+	sg.db.WithContext(ctx).Model(Contract{}).Where("deploy_code_hash = ''").Pluck("address", &addresses)
+	for _, address := range addresses {
+		if contract := GetContract(ctx, address); contract != nil {
+			if err := contract.refreshDeployCodeHash(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	var verified []Contract
+	sg.db.WithContext(ctx).Where("verify_status != ''").Where("verify_type != ?", VerifySimilarMatch).
+		Where("deploy_code_hash != ''").Find(&verified)
+	for index := range verified {
+		if err := verified[index].verifySimilarContracts(ctx); err != nil {
+			return err
+		}
+	}
+	util.Logger().Info(fmt.Sprintf("refreshed %d contracts deploy code hash", len(addresses)))
+	return nil
+}
@@ -0,0 +1,13 @@
+package dao
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_DeployCodeHash(t *testing.T) {
+	// metadata hash is ignored
+	assert.Equal(t, DeployCodeHash("0x6080a10000000004"), DeployCodeHash("0x6080a1ffffff0004"))
+	assert.NotEqual(t, DeployCodeHash("0x6080a10000000004"), DeployCodeHash("0x6081a10000000004"))
+	assert.Equal(t, "", DeployCodeHash("0x"))
+}
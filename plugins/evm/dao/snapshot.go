@@ -0,0 +1,214 @@
+package dao
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/itering/subscan/plugins/evm/feature"
+	"github.com/itering/subscan/share/web3"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"io"
+	"sort"
+)
+
+// HolderSnapshotMaxRow max holders of one page of HolderSnapshotPage, full snapshot only exported by EvmHolderSnapshot command
+const HolderSnapshotMaxRow = 100
+
+var (
+	ErrTokenNotFound       = errors.New("token not found")
+	ErrSnapshotUnsupported = errors.New("consistency check only support erc20 and erc721 token")
+)
+
+// HolderBalance balance of holder reconstructed from transfers
+type HolderBalance struct {
+	Holder  string          `json:"holder"`
+	Balance decimal.Decimal `json:"balance"`
+}
+
+type HolderSnapshotJson struct {
+	Contract string          `json:"contract"`
+	Category string          `json:"category"`
+	BlockNum uint64          `json:"block_num"`
+	Total    decimal.Decimal `json:"total"`
+	Count    int64           `json:"count"`
+	Holders  []HolderBalance `json:"holders"`
+}
+
+// HolderMismatch reconstructed balance different from on-chain balanceOf
+type HolderMismatch struct {
+	Holder        string          `json:"holder"`
+	Reconstructed decimal.Decimal `json:"reconstructed"`
+	OnChain       decimal.Decimal `json:"on_chain"`
+}
+
+// transferIdUpperBound transfers of block num are less than, transfer id is receipt id
+func transferIdUpperBound(blockNum uint64) uint64 {
+	return (blockNum + 1) * TransactionIdGenerateCoefficient * TxnReceiptLimit
+}
+
+type holderDelta struct {
+	Holder string
+	Amount decimal.Decimal
+}
+
+// mergeHolderDeltas received minus sent, mint from and burn to null address are excluded
+func mergeHolderDeltas(received, sent []holderDelta) []HolderBalance {
+	balances := make(map[string]decimal.Decimal)
+	for _, r := range received {
+		balances[r.Holder] = balances[r.Holder].Add(r.Amount)
+	}
+	for _, s := range sent {
+		balances[s.Holder] = balances[s.Holder].Sub(s.Amount)
+	}
+	list := []HolderBalance{}
+	for holder, balance := range balances {
+		if holder == NullAddress || !balance.IsPositive() {
+			continue
+		}
+		list = append(list, HolderBalance{Holder: holder, Balance: balance})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if c := list[i].Balance.Cmp(list[j].Balance); c != 0 {
+			return c > 0
+		}
+		return list[i].Holder < list[j].Holder
+	})
+	return list
+}
+
+// HolderSnapshot holders and balances of token at block num, erc721 balance is tokens count,
+// erc1155 balance is sum of all token ids if tokenId is empty
+func HolderSnapshot(ctx context.Context, contract, tokenId string, blockNum uint64) (*HolderSnapshotJson, error) {
+	token := GetTokenByContract(ctx, contract)
+	if token == nil {
+		return nil, ErrTokenNotFound
+	}
+	sum := func(column string) ([]holderDelta, error) {
+		var list []holderDelta
+		q := sg.db.WithContext(ctx).Model(TokensTransfers{}).Select(fmt.Sprintf("%s as holder, sum(value) as amount", column)).
+			Where("contract = ?", contract).Where("transfer_id < ?", transferIdUpperBound(blockNum))
+		if tokenId != "" {
+			q = q.Where("token_id = ?", tokenId)
+		}
+		return list, q.Group(column).Scan(&list).Error
+	}
+	received, err := sum("receiver")
+	if err != nil {
+		return nil, err
+	}
+	sent, err := sum("sender")
+	if err != nil {
+		return nil, err
+	}
+	snapshot := HolderSnapshotJson{Contract: contract, Category: token.Category, BlockNum: blockNum, Holders: mergeHolderDeltas(received, sent)}
+	for _, holder := range snapshot.Holders {
+		snapshot.Total = snapshot.Total.Add(holder.Balance)
+	}
+	snapshot.Count = int64(len(snapshot.Holders))
+	return &snapshot, nil
+}
+
+// holderSnapshotQuery holder balances at block num aggregated by database, received minus sent
+func holderSnapshotQuery(contract, tokenId string, blockNum uint64) *gorm.DB {
+	delta := func(column, amount string) *gorm.DB {
+		q := sg.db.Model(TokensTransfers{}).Select(fmt.Sprintf("%s as holder, %s as amount", column, amount)).
+			Where("contract = ?", contract).Where("transfer_id < ?", transferIdUpperBound(blockNum))
+		if tokenId != "" {
+			q = q.Where("token_id = ?", tokenId)
+		}
+		return q
+	}
+	return sg.db.Table("(?) as deltas", sg.db.Raw("? UNION ALL ?", delta("receiver", "value"), delta("sender", "-value"))).
+		Select("holder, sum(amount) as balance").Where("holder <> ?", NullAddress).Group("holder").Having("sum(amount) > 0")
+}
+
+// HolderSnapshotPage one page of HolderSnapshot sorted by balance desc, total and count are of all holders
+func HolderSnapshotPage(ctx context.Context, contract, tokenId string, blockNum uint64, page, row int) (*HolderSnapshotJson, error) {
+	token := GetTokenByContract(ctx, contract)
+	if token == nil {
+		return nil, ErrTokenNotFound
+	}
+	if row <= 0 || row > HolderSnapshotMaxRow {
+		row = HolderSnapshotMaxRow
+	}
+	snapshot := HolderSnapshotJson{Contract: contract, Category: token.Category, BlockNum: blockNum, Holders: []HolderBalance{}}
+	var summary struct {
+		Count int64
+		Total decimal.NullDecimal
+	}
+	if err := sg.db.WithContext(ctx).Table("(?) as balances", holderSnapshotQuery(contract, tokenId, blockNum)).
+		Select("count(*) as count, sum(balance) as total").Scan(&summary).Error; err != nil {
+		return nil, err
+	}
+	snapshot.Count, snapshot.Total = summary.Count, summary.Total.Decimal
+	if err := sg.db.WithContext(ctx).Table("(?) as balances", holderSnapshotQuery(contract, tokenId, blockNum)).
+		Order("balance desc").Order("holder asc").Offset(page * row).Limit(row).Scan(&snapshot.Holders).Error; err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// WriteHolderSnapshotCsv export snapshot as csv with header holder,balance
+func WriteHolderSnapshotCsv(w io.Writer, snapshot *HolderSnapshotJson) error {
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"holder", "balance"})
+	for _, holder := range snapshot.Holders {
+		if err := writer.Write([]string{holder.Holder, holder.Balance.String()}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// CheckHolderSnapshot compare balances reconstructed at latest indexed block with on-chain balanceOf,
+// holders with balance moved after latest indexed block may be reported
+func CheckHolderSnapshot(ctx context.Context, contract string) ([]HolderMismatch, error) {
+	token := GetTokenByContract(ctx, contract)
+	if token == nil {
+		return nil, ErrTokenNotFound
+	}
+	if token.Category != Eip20Token && token.Category != Eip721Token {
+		return nil, ErrSnapshotUnsupported
+	}
+	snapshot, err := HolderSnapshot(ctx, contract, "", uint64(latestBlockNum(ctx)))
+	if err != nil {
+		return nil, err
+	}
+	reconstructed := make(map[string]decimal.Decimal)
+	for _, holder := range snapshot.Holders {
+		reconstructed[holder.Holder] = holder.Balance
+	}
+	// holders emptied by transfers should be zero on chain too
+	var indexed []string
+	sg.db.WithContext(ctx).Model(TokenHolder{}).Where("contract = ?", contract).Where("balance > 0").Pluck("holder", &indexed)
+	var holders []string
+	for holder := range reconstructed {
+		holders = append(holders, holder)
+	}
+	for _, holder := range indexed {
+		if _, ok := reconstructed[holder]; !ok {
+			holders = append(holders, holder)
+		}
+	}
+	sort.Strings(holders)
+
+	instance := feature.InitToken(web3.RPC, token.Category, contract)
+	var mismatches []HolderMismatch
+	for _, holder := range holders {
+		onChain, err := instance.BalanceOf(ctx, holder)
+		if err != nil {
+			return mismatches, err
+		}
+		if !onChain.Equal(reconstructed[holder]) {
+			mismatches = append(mismatches, HolderMismatch{Holder: holder, Reconstructed: reconstructed[holder], OnChain: onChain})
+		}
+	}
+	return mismatches, nil
+}
+
+func (a *ApiSrv) HolderSnapshotPage(ctx context.Context, contract, tokenId string, blockNum uint64, page, row int) (*HolderSnapshotJson, error) {
+	return HolderSnapshotPage(ctx, contract, tokenId, blockNum, page, row)
+}
@@ -0,0 +1,31 @@
+package dao
+
+import (
+	"bytes"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_MergeHolderDeltas(t *testing.T) {
+	alice, bob := "0x1111111111111111111111111111111111111111", "0x2222222222222222222222222222222222222222"
+	received := []holderDelta{{Holder: alice, Amount: decimal.NewFromInt(100)}, {Holder: bob, Amount: decimal.NewFromInt(30)}, {Holder: NullAddress, Amount: decimal.NewFromInt(5)}}
+	sent := []holderDelta{{Holder: NullAddress, Amount: decimal.NewFromInt(130)}, {Holder: alice, Amount: decimal.NewFromInt(30)}, {Holder: bob, Amount: decimal.NewFromInt(30)}}
+	list := mergeHolderDeltas(received, sent)
+	assert.Equal(t, []HolderBalance{{Holder: alice, Balance: decimal.NewFromInt(70)}}, list)
+	assert.Equal(t, []HolderBalance{}, mergeHolderDeltas(nil, nil))
+}
+
+func Test_TransferIdUpperBound(t *testing.T) {
+	transfer := TokensTransfers{TransferId: transferIdUpperBound(10) - 1}
+	assert.Equal(t, uint64(10), transfer.BlockNum())
+	transfer.TransferId = transferIdUpperBound(10)
+	assert.Equal(t, uint64(11), transfer.BlockNum())
+}
+
+func Test_WriteHolderSnapshotCsv(t *testing.T) {
+	var buf bytes.Buffer
+	snapshot := HolderSnapshotJson{Holders: []HolderBalance{{Holder: "0x1111111111111111111111111111111111111111", Balance: decimal.RequireFromString("1.5")}}}
+	assert.NoError(t, WriteHolderSnapshotCsv(&buf, &snapshot))
+	assert.Equal(t, "holder,balance\n0x1111111111111111111111111111111111111111,1.5\n", buf.String())
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/itering/subscan/util/mq"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"time"
 )
 
 type Storage struct {
@@ -54,9 +55,22 @@ func (s *Storage) Tables() []interface{} {
 		&EvmBlock{},
 		&Erc721Holders{},
 		&AbiMapping{},
-		// &ERC1155Item{},
-		// &ERC1155Holder{},
+		&ERC1155Item{},
+		&ERC1155Holder{},
 		&Account{},
+		&InternalTransaction{},
+		&TokenApproval{},
+		&ContractFacet{},
+		&BalanceChange{},
+		&EvmGasStat{},
+		&NftMetadata{},
+		&EventSubscription{},
+		&SubscriptionEvent{},
+		&SubscriptionCursor{},
+		&ProcessedBlock{},
+		&ReviveCode{},
+		&NameRecord{},
+		&NameReverseRecord{},
 	}
 
 }
@@ -68,6 +82,13 @@ func Publish(queue, class string, args interface{}) error {
 	return mq.Instant.Publish(queue, class, args)
 }
 
+func PublishIn(queue, class string, delay time.Duration, args interface{}) error {
+	if mq.Instant == nil {
+		return nil
+	}
+	return mq.Instant.PublishIn(queue, class, delay, args)
+}
+
 func RefreshMetadata(ctx context.Context) {
 	db := sg.db
 	var count int64
@@ -0,0 +1,34 @@
+package dao
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"github.com/itering/subscan/plugins/evm/feature"
+	"github.com/itering/subscan/share/web3"
+	"github.com/itering/subscan/util"
+	"strings"
+)
+
+var ErrInvalidRawTransaction = errors.New("invalid signed raw transaction")
+
+// rawTransactionHash hash of signed raw transaction, empty if it can not be decoded
+func rawTransactionHash(ctx context.Context, raw string) string {
+	if _, err := hex.DecodeString(util.TrimHex(raw)); err != nil {
+		return ""
+	}
+	return strings.ToLower(feature.CalHashByTxRaw(ctx, raw))
+}
+
+// SubmitRawTransaction relay signed raw transaction to rpc node, hash is computed before sent
+// so that invalid transaction is rejected without node round trip
+func (a *ApiSrv) SubmitRawTransaction(ctx context.Context, raw string) (string, error) {
+	hash := rawTransactionHash(ctx, raw)
+	if hash == "" {
+		return "", ErrInvalidRawTransaction
+	}
+	if _, err := web3.RPC.Eth.SendRawTransaction(ctx, util.AddHex(util.TrimHex(raw))); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
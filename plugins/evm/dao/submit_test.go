@@ -0,0 +1,15 @@
+package dao
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_rawTransactionHash(t *testing.T) {
+	ctx := context.TODO()
+	rawTxHex := "0x02f8d384190f1b45388203e88203e8861a9a5c267c5c943057843059c48e75681442408c8d629ccb481b5b80b8647c9cd2bc000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000036173640000000000000000000000000000000000000000000000000000000000c080a09f8bfed4eacda701a2538340d96b5d74ac1ca7ba37072f98f20879faaf6bf761a05587e3e3a196155b7ebede1bc53f026e20218b49c81a585d844c09b1dbd43207"
+	assert.Equal(t, "0xccf4ea834566a91f28ba901897e53331b6ae60e4bc24d7e2c1df43edd75b8914", rawTransactionHash(ctx, rawTxHex))
+	assert.Equal(t, "", rawTransactionHash(ctx, "0xzz"))
+	assert.Equal(t, "", rawTransactionHash(ctx, "0x1234"))
+}
@@ -0,0 +1,440 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/itering/subscan/util"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// subscriptionCacheTtl subscriptions are registered by api, reload periodically in indexer
+	subscriptionCacheTtl = 30 * time.Second
+	// SubscriptionBackfillMaxRange max block range of one backfill request
+	SubscriptionBackfillMaxRange = 100000
+	subscriptionBackfillBatch    = 500
+	// processedBlockScanLimit max processed blocks the cursor moves over at once
+	processedBlockScanLimit = 1000
+	subscriptionCursorId    = 1
+)
+
+// subscriptionMaxCount max registered subscriptions, every block receipts are matched against all of them
+var subscriptionMaxCount = util.StringToInt(util.GetEnv("EVM_SUBSCRIPTION_MAX", "100"))
+
+var (
+	ErrSubscriptionAbi      = errors.New("abi has no event fragment")
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrSubscriptionLimit    = errors.New("subscription count exceeds limit")
+	ErrBackfillRange        = errors.New("invalid backfill block range")
+)
+
+// EventSubscription contract events registered by dApp
+type EventSubscription struct {
+	Id        uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name      string         `json:"name" gorm:"size:100"`
+	Address   string         `json:"address" gorm:"size:70;index:address"`
+	Abi       datatypes.JSON `json:"abi"`
+	Topics    string         `json:"topics" gorm:"type:text"`
+	CreatedAt int64          `json:"created_at"`
+}
+
+func (s *EventSubscription) TableName() string {
+	return "evm_event_subscriptions"
+}
+
+// SubscriptionEvent decoded event of subscription, seq is the delivery order.
+// seq is 0 until the block of event is behind subscription cursor, it is assigned with cursor locked
+// so a smaller seq is never committed after a bigger one
+type SubscriptionEvent struct {
+	Id              uint64         `json:"-" gorm:"primaryKey;autoIncrement"`
+	Seq             uint64         `json:"seq" gorm:"index:subscription_seq,priority:2;index:seq_block,priority:1"`
+	SubscriptionId  uint           `json:"subscription_id" gorm:"index:subscription_receipt,unique,priority:1;index:subscription_seq,priority:1"`
+	ReceiptId       uint64         `json:"receipt_id" gorm:"index:subscription_receipt,unique,priority:2"`
+	BlockNum        uint64         `json:"block_num" gorm:"index:seq_block,priority:2"`
+	BlockTimestamp  uint           `json:"block_timestamp"`
+	TransactionHash string         `json:"transaction_hash" gorm:"size:70"`
+	LogIndex        int            `json:"log_index"`
+	Event           string         `json:"event" gorm:"size:255"`
+	Params          datatypes.JSON `json:"params"`
+	CreatedAt       int64          `json:"-"`
+}
+
+func (s *SubscriptionEvent) TableName() string {
+	return "evm_subscription_events"
+}
+
+// SubscriptionCursor blocks up to block_num are all processed, single row
+type SubscriptionCursor struct {
+	Id       uint   `gorm:"primaryKey"`
+	BlockNum uint64 `gorm:"default:0"`
+}
+
+func (s *SubscriptionCursor) TableName() string {
+	return "evm_subscription_cursor"
+}
+
+// ProcessedBlock blocks processed ahead of subscription cursor, removed once cursor moved over
+type ProcessedBlock struct {
+	BlockNum uint64 `gorm:"primaryKey;autoIncrement:false"`
+}
+
+func (p *ProcessedBlock) TableName() string {
+	return "evm_processed_blocks"
+}
+
+type SubscriptionEventJson struct {
+	SubscriptionEvent
+	Confirmations uint64 `json:"confirmations"`
+}
+
+// parseSubscriptionAbi event fragments of abi, return topic0 of events
+func parseSubscriptionAbi(raw []byte) (*abi.ABI, []string, error) {
+	eventAbi := parseAbi(raw)
+	if eventAbi == nil || len(eventAbi.Events) == 0 {
+		return nil, nil, ErrSubscriptionAbi
+	}
+	var topics []string
+	for _, event := range eventAbi.Events {
+		if !event.Anonymous {
+			topics = append(topics, strings.ToLower(event.ID.Hex()))
+		}
+	}
+	if len(topics) == 0 {
+		return nil, nil, ErrSubscriptionAbi
+	}
+	return eventAbi, topics, nil
+}
+
+type subscriptionMatcher struct {
+	subscription EventSubscription
+	abi          *abi.ABI
+	topics       map[string]bool
+}
+
+// match decode receipt if address and topic0 matched
+func (m *subscriptionMatcher) match(r *TransactionReceipt) *SubscriptionEvent {
+	if !strings.EqualFold(m.subscription.Address, r.Address) || !m.topics[strings.ToLower(r.MethodHash)] {
+		return nil
+	}
+	decoded := decodeEvent(m.abi, strings.Split(r.Topics, ","), r.Data)
+	if decoded == nil {
+		return nil
+	}
+	return &SubscriptionEvent{
+		SubscriptionId:  m.subscription.Id,
+		ReceiptId:       r.Id,
+		BlockNum:        r.BlockNum,
+		BlockTimestamp:  r.BlockTimestamp,
+		TransactionHash: r.TransactionHash,
+		LogIndex:        r.Index,
+		Event:           decoded.Name,
+		Params:          datatypes.JSON(util.ToString(decoded.Params)),
+	}
+}
+
+func newSubscriptionMatcher(subscription EventSubscription) *subscriptionMatcher {
+	eventAbi, topics, err := parseSubscriptionAbi(subscription.Abi)
+	if err != nil {
+		return nil
+	}
+	m := subscriptionMatcher{subscription: subscription, abi: eventAbi, topics: make(map[string]bool)}
+	for _, topic := range topics {
+		m.topics[topic] = true
+	}
+	return &m
+}
+
+var subscriptionCache struct {
+	sync.Mutex
+	loadedAt  time.Time
+	byAddress map[string][]*subscriptionMatcher
+}
+
+func subscriptionMatchers(ctx context.Context) map[string][]*subscriptionMatcher {
+	subscriptionCache.Lock()
+	defer subscriptionCache.Unlock()
+	if subscriptionCache.byAddress != nil && time.Since(subscriptionCache.loadedAt) < subscriptionCacheTtl {
+		return subscriptionCache.byAddress
+	}
+	var list []EventSubscription
+	if q := sg.db.WithContext(ctx).Find(&list); q.Error != nil {
+		return subscriptionCache.byAddress
+	}
+	byAddress := make(map[string][]*subscriptionMatcher)
+	for _, subscription := range list {
+		if m := newSubscriptionMatcher(subscription); m != nil {
+			address := strings.ToLower(subscription.Address)
+			byAddress[address] = append(byAddress[address], m)
+		}
+	}
+	subscriptionCache.byAddress, subscriptionCache.loadedAt = byAddress, time.Now()
+	return byAddress
+}
+
+func resetSubscriptionCache() {
+	subscriptionCache.Lock()
+	subscriptionCache.byAddress = nil
+	subscriptionCache.Unlock()
+}
+
+func saveSubscriptionEvents(ctx context.Context, events []SubscriptionEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	now := time.Now().Unix()
+	for index := range events {
+		events[index].CreatedAt = now
+	}
+	// receipt matched again by backfill or block reprocess is ignored
+	return sg.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(events, 500).Error
+}
+
+func matchReceipts(matchers map[string][]*subscriptionMatcher, receipts []TransactionReceipt) (events []SubscriptionEvent) {
+	for index := range receipts {
+		for _, m := range matchers[strings.ToLower(receipts[index].Address)] {
+			if event := m.match(&receipts[index]); event != nil {
+				events = append(events, *event)
+			}
+		}
+	}
+	return
+}
+
+// MatchEventSubscriptions store decoded events of new receipts matched subscriptions
+func MatchEventSubscriptions(ctx context.Context, receipts []TransactionReceipt) error {
+	matchers := subscriptionMatchers(ctx)
+	if len(matchers) == 0 {
+		return nil
+	}
+	return saveSubscriptionEvents(ctx, matchReceipts(matchers, receipts))
+}
+
+// nextProcessedCursor move cursor over contiguous processed blocks, marks is sorted asc
+func nextProcessedCursor(cursor uint64, marks []uint64) uint64 {
+	for _, blockNum := range marks {
+		if blockNum != cursor+1 {
+			break
+		}
+		cursor = blockNum
+	}
+	return cursor
+}
+
+// advanceSubscriptionCursor mark block processed (ignored if nil) and assign seq to events behind cursor.
+// blocks are processed concurrently, cursor only moves over contiguous processed blocks
+func advanceSubscriptionCursor(ctx context.Context, processed *uint64) error {
+	return sg.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if processed != nil && *processed > 0 {
+			// the first processed block starts the cursor
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&SubscriptionCursor{Id: subscriptionCursorId, BlockNum: *processed - 1}).Error; err != nil {
+				return err
+			}
+		}
+		var cursor SubscriptionCursor
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", subscriptionCursorId).First(&cursor).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		next := cursor.BlockNum
+		if processed != nil && *processed > cursor.BlockNum {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&ProcessedBlock{BlockNum: *processed}).Error; err != nil {
+				return err
+			}
+			var marks []uint64
+			if err := tx.Model(ProcessedBlock{}).Where("block_num > ?", cursor.BlockNum).Order("block_num asc").
+				Limit(processedBlockScanLimit).Pluck("block_num", &marks).Error; err != nil {
+				return err
+			}
+			next = nextProcessedCursor(cursor.BlockNum, marks)
+		}
+		if next > cursor.BlockNum {
+			if err := tx.Where("block_num <= ?", next).Delete(&ProcessedBlock{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&cursor).Update("block_num", next).Error; err != nil {
+				return err
+			}
+		}
+		return sequenceSubscriptionEvents(tx, next)
+	})
+}
+
+// sequenceSubscriptionEvents assign seq to events of blocks behind cursor, include reprocessed and backfilled blocks
+func sequenceSubscriptionEvents(tx *gorm.DB, cursor uint64) error {
+	var events []SubscriptionEvent
+	if err := tx.Select("id").Where("seq = 0").Where("block_num <= ?", cursor).
+		Order("block_num asc").Order("receipt_id asc").Order("id asc").Find(&events).Error; err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	var seq uint64
+	if err := tx.Model(SubscriptionEvent{}).Select("coalesce(max(seq), 0)").Scan(&seq).Error; err != nil {
+		return err
+	}
+	for _, event := range events {
+		seq++
+		if err := tx.Model(SubscriptionEvent{}).Where("id = ?", event.Id).Update("seq", seq).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SubscriptionBlockProcessed called after all writes of block done, events of block are deliverable once cursor moved over it
+func SubscriptionBlockProcessed(ctx context.Context, blockNum uint64) error {
+	return advanceSubscriptionCursor(ctx, &blockNum)
+}
+
+func GetEventSubscription(ctx context.Context, id uint) *EventSubscription {
+	var subscription EventSubscription
+	if q := sg.db.WithContext(ctx).Where("id = ?", id).First(&subscription); q.Error != nil {
+		return nil
+	}
+	return &subscription
+}
+
+// SubscriptionBackfillArgs args of backfill queue job
+type SubscriptionBackfillArgs struct {
+	SubscriptionId uint   `json:"subscription_id"`
+	From           uint64 `json:"from"`
+	To             uint64 `json:"to"`
+}
+
+// ProcessSubscriptionBackfill match saved receipts of block range
+func ProcessSubscriptionBackfill(ctx context.Context, args *SubscriptionBackfillArgs) error {
+	subscription := GetEventSubscription(ctx, args.SubscriptionId)
+	if subscription == nil {
+		return nil
+	}
+	m := newSubscriptionMatcher(*subscription)
+	if m == nil {
+		return nil
+	}
+	matchers := map[string][]*subscriptionMatcher{strings.ToLower(subscription.Address): {m}}
+	var topics []string
+	for topic := range m.topics {
+		topics = append(topics, topic)
+	}
+	var lastId uint64
+	for {
+		var receipts []TransactionReceipt
+		q := sg.db.WithContext(ctx).Model(TransactionReceipt{}).Where("address = ?", strings.ToLower(subscription.Address)).
+			Where("method_hash in ?", topics).Where("block_num between ? and ?", args.From, args.To).
+			Where("id > ?", lastId).Order("id asc").Limit(subscriptionBackfillBatch).Find(&receipts)
+		if q.Error != nil {
+			return q.Error
+		}
+		if len(receipts) == 0 {
+			return nil
+		}
+		if err := saveSubscriptionEvents(ctx, matchReceipts(matchers, receipts)); err != nil {
+			return err
+		}
+		lastId = receipts[len(receipts)-1].Id
+		if err := advanceSubscriptionCursor(ctx, nil); err != nil {
+			return err
+		}
+	}
+}
+
+func (a *ApiSrv) CreateEventSubscription(ctx context.Context, name, address string, rawAbi []byte) (*EventSubscription, error) {
+	_, topics, err := parseSubscriptionAbi(rawAbi)
+	if err != nil {
+		return nil, err
+	}
+	var count int64
+	sg.db.WithContext(ctx).Model(EventSubscription{}).Count(&count)
+	if count >= int64(subscriptionMaxCount) {
+		return nil, ErrSubscriptionLimit
+	}
+	subscription := EventSubscription{
+		Name:      name,
+		Address:   strings.ToLower(address),
+		Abi:       rawAbi,
+		Topics:    strings.Join(topics, ","),
+		CreatedAt: time.Now().Unix(),
+	}
+	if q := sg.db.WithContext(ctx).Create(&subscription); q.Error != nil {
+		return nil, q.Error
+	}
+	resetSubscriptionCache()
+	return &subscription, nil
+}
+
+func (a *ApiSrv) EventSubscriptions(ctx context.Context) []EventSubscription {
+	list := []EventSubscription{}
+	sg.db.WithContext(ctx).Order("id asc").Find(&list)
+	return list
+}
+
+func (a *ApiSrv) DeleteEventSubscription(ctx context.Context, id uint) error {
+	if q := sg.db.WithContext(ctx).Where("id = ?", id).Delete(&EventSubscription{}); q.Error != nil || q.RowsAffected == 0 {
+		return ErrSubscriptionNotFound
+	}
+	sg.db.WithContext(ctx).Where("subscription_id = ?", id).Delete(&SubscriptionEvent{})
+	resetSubscriptionCache()
+	return nil
+}
+
+// BackfillEventSubscription enqueue backfill of past block range
+func (a *ApiSrv) BackfillEventSubscription(ctx context.Context, id uint, from, to uint64) error {
+	if to < from || to-from > SubscriptionBackfillMaxRange {
+		return ErrBackfillRange
+	}
+	if GetEventSubscription(ctx, id) == nil {
+		return ErrSubscriptionNotFound
+	}
+	return Publish(EvmSubscription, "backfill", SubscriptionBackfillArgs{SubscriptionId: id, From: from, To: to})
+}
+
+// confirmedPrefix events before the first one not reach confirmations, cursor never skips unconfirmed event
+func confirmedPrefix(events []SubscriptionEvent, latest uint64, confirmations uint64) []SubscriptionEventJson {
+	list := []SubscriptionEventJson{}
+	for _, event := range events {
+		var confirmed uint64
+		if latest >= event.BlockNum {
+			confirmed = latest - event.BlockNum + 1
+		}
+		if confirmed < confirmations {
+			break
+		}
+		list = append(list, SubscriptionEventJson{SubscriptionEvent: event, Confirmations: confirmed})
+	}
+	return list
+}
+
+// SubscriptionEventsCursor events after cursor order by seq, consumer saves the end cursor after handled (at-least-once)
+func (a *ApiSrv) SubscriptionEventsCursor(ctx context.Context, id uint, confirmations uint64, limit int, after uint64) ([]SubscriptionEventJson, map[string]interface{}, error) {
+	if GetEventSubscription(ctx, id) == nil {
+		return nil, nil, ErrSubscriptionNotFound
+	}
+	var events []SubscriptionEvent
+	// seq is assigned only after cursor moved over block, no seq smaller than delivered ones appears later
+	sg.db.WithContext(ctx).Where("subscription_id = ?", id).Where("seq > ?", after).
+		Order("seq asc").Limit(limit + 1).Find(&events)
+	hasNext := len(events) > limit
+	if hasNext {
+		events = events[:limit]
+	}
+	list := confirmedPrefix(events, uint64(latestBlockNum(ctx)), confirmations)
+	if len(list) < len(events) {
+		hasNext = false
+	}
+	end := after
+	if len(list) > 0 {
+		end = list[len(list)-1].Seq
+	}
+	return list, map[string]interface{}{"end_cursor": end, "has_next_page": hasNext}, nil
+}
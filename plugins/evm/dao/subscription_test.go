@@ -0,0 +1,67 @@
+package dao
+
+import (
+	evmAbi "github.com/itering/subscan/plugins/evm/abi"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_parseSubscriptionAbi(t *testing.T) {
+	_, _, err := parseSubscriptionAbi([]byte(`[{"type":"function","name":"transfer","inputs":[]}]`))
+	assert.ErrorIs(t, err, ErrSubscriptionAbi)
+	_, _, err = parseSubscriptionAbi([]byte(`not json`))
+	assert.ErrorIs(t, err, ErrSubscriptionAbi)
+
+	_, topics, err := parseSubscriptionAbi([]byte(evmAbi.Erc20))
+	assert.NoError(t, err)
+	assert.Contains(t, topics, "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+}
+
+func Test_matchReceipts(t *testing.T) {
+	m := newSubscriptionMatcher(EventSubscription{Id: 1, Address: "0xabcd", Abi: []byte(evmAbi.Erc20)})
+	assert.NotNil(t, m)
+	matchers := map[string][]*subscriptionMatcher{"0xabcd": {m}}
+	transfer := TransactionReceipt{
+		Id:      100,
+		Address: "0xABCD",
+		Topics: "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef," +
+			"0x0000000000000000000000002cac6e4b11d6b58f6d3c1c9d5fe8faa89f60e5a2," +
+			"0x00000000000000000000000066a1cba4138cbb5bcc3e3af1b10c7fd56b6aac0b",
+		Data:       "0x00000000000000000000000000000000000000000000000000000000000003e8",
+		MethodHash: "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+		BlockNum:   10,
+		Index:      2,
+	}
+	other := transfer
+	other.Address = "0xffff"
+	unknown := transfer
+	unknown.MethodHash = "0x01"
+	events := matchReceipts(matchers, []TransactionReceipt{transfer, other, unknown})
+	assert.Len(t, events, 1)
+	assert.Equal(t, uint(1), events[0].SubscriptionId)
+	assert.Equal(t, uint64(100), events[0].ReceiptId)
+	assert.Equal(t, "Transfer", events[0].Event)
+	assert.Equal(t, 2, events[0].LogIndex)
+	assert.Contains(t, string(events[0].Params), `"value":"1000"`)
+}
+
+func Test_confirmedPrefix(t *testing.T) {
+	events := []SubscriptionEvent{{Seq: 1, BlockNum: 8}, {Seq: 2, BlockNum: 10}, {Seq: 3, BlockNum: 5}}
+	list := confirmedPrefix(events, 10, 0)
+	assert.Len(t, list, 3)
+	assert.Equal(t, uint64(3), list[0].Confirmations)
+	assert.Equal(t, uint64(1), list[1].Confirmations)
+	// stop at first unconfirmed event, later confirmed event will not be skipped
+	list = confirmedPrefix(events, 10, 2)
+	assert.Len(t, list, 1)
+	assert.Equal(t, uint64(1), list[0].Seq)
+	assert.Len(t, confirmedPrefix(events, 7, 1), 0)
+}
+
+func Test_nextProcessedCursor(t *testing.T) {
+	assert.Equal(t, uint64(10), nextProcessedCursor(10, nil))
+	assert.Equal(t, uint64(13), nextProcessedCursor(10, []uint64{11, 12, 13}))
+	// block 12 is still processing
+	assert.Equal(t, uint64(11), nextProcessedCursor(10, []uint64{11, 13, 14}))
+	assert.Equal(t, uint64(10), nextProcessedCursor(10, []uint64{12}))
+}
@@ -150,6 +150,9 @@ type TokenHolder struct {
 	Contract string          `json:"contract" gorm:"index:contract;index:contract_hold,unique;size:100"`
 	Holder   string          `json:"holder" gorm:"index:hold;index:contract_hold,unique;size:100" `
 	Balance  decimal.Decimal `json:"balance" gorm:"default: 0;type:decimal(65);index:balance_id,priority:1"`
+
+	ValueUSD   *decimal.Decimal `json:"value_usd" gorm:"-"`
+	HolderName string           `json:"holder_name,omitempty" gorm:"-"`
 }
 
 func (c TokenHolder) Cursor() string {
@@ -182,9 +185,9 @@ func RefreshHolder(ctx context.Context, contract, address, category string) erro
 		return nil
 	}
 	// erc1155 balance
-	// if category == Eip1155Token {
-	// 	balance = decimal.New(ERC1155TokenIdsCount(ctx, contract, address), 0)
-	// }
+	if category == Eip1155Token {
+		balance = decimal.New(ERC1155TokenIdsCount(ctx, contract, address), 0)
+	}
 
 	q := sg.AddOrUpdateItem(ctx, &TokenHolder{Contract: contract, Holder: address, Balance: balance}, []string{"contract", "holder"}, "balance")
 	if q.RowsAffected == 1 || (q.RowsAffected == 2 && balance.IsZero()) {
@@ -221,6 +224,13 @@ const (
 	TransferCategoryErc1155
 )
 
+// TransferCategory token category to transfer category
+var TransferCategory = map[string]int{
+	Eip20Token:   TransferCategoryErc20,
+	Eip721Token:  TransferCategoryErc721,
+	Eip1155Token: TransferCategoryErc1155,
+}
+
 func (t *TokensTransfers) TableName() string {
 	return "evm_tokens_transfers"
 }
@@ -279,6 +289,9 @@ func (t *TransactionReceipt) ProcessTokenTransfer(ctx context.Context, category
 	if query.RowsAffected > 0 {
 
 		token.incrTransferCount(ctx, 1)
+		if category == Eip20Token {
+			refreshOwnerAllowances(ctx, token.Contract, transfer.Sender)
+		}
 		if mq.Instant != nil {
 			_ = Publish(category, "balance", []string{token.Contract, transfer.Sender})
 			_ = Publish(category, "balance", []string{token.Contract, transfer.Receiver})
@@ -320,6 +333,9 @@ type TokenTransferJson struct {
 	Symbol     string           `json:"symbol"`
 	Name       string           `json:"name"`
 	Category   string           `json:"category"`
+	ValueUSD   *decimal.Decimal `json:"value_usd"`
+	FromName   string           `json:"from_name,omitempty"`
+	ToName     string           `json:"to_name,omitempty"`
 }
 
 func ContractAddr2Token(ctx context.Context, addr []string) map[string]Token {
@@ -4,7 +4,6 @@ import (
 	"context"
 	"github.com/itering/subscan/model"
 	"github.com/itering/subscan/pkg/go-web3/dto"
-	"github.com/itering/subscan/share/web3"
 	"github.com/itering/subscan/util"
 	"strings"
 
@@ -47,6 +46,14 @@ type Transaction struct {
 	TransactionIndex     uint64          `json:"transaction_index" gorm:"size:32"`
 	// pk
 	TransactionId uint64 `json:"transaction_id" gorm:"size:64;index:transaction_id,unique" `
+	// trace
+	TraceErrorMsg string            `json:"trace_error_msg,omitempty" gorm:"-"`
+	FromName      string            `json:"from_name,omitempty" gorm:"-"`
+	ToName        string            `json:"to_name,omitempty" gorm:"-"`
+	Trace         *InternalCallJson `json:"trace,omitempty" gorm:"-"`
+	// decoded
+	DecodedInput *DecodedMethod `json:"decoded_input,omitempty" gorm:"-"`
+	Logs         []ReceiptJson  `json:"logs,omitempty" gorm:"-"`
 }
 
 type TransactionSample struct {
@@ -120,13 +127,16 @@ func (t *Transaction) TableName() string {
 }
 
 func (t *Transaction) AfterCreate(txn *gorm.DB) (err error) {
-	ctx := txn.Statement.Context
+	t.afterCreate(txn.Statement.Context)
+	return nil
+}
+
+func (t *Transaction) afterCreate(ctx context.Context) {
 	// Increase Contract transaction count
 	if IsContract(ctx, t.ToAddress) {
 		incrContractTransactionCount(ctx, t.ToAddress)
 	}
 	_, _ = sg.redis.HINCRBY(context.Background(), model.MetadataCacheKey(), "total_transaction", 1)
-	return nil
 }
 
 // SetEvmAddressRelate set account evm address
@@ -169,7 +179,8 @@ func GetTransactionByHash(c context.Context, hash string) *Transaction {
 	return &t
 }
 
-func (s *Storage) CreateTransactionByExecuted(ctx context.Context, blockTimestamp uint, ethTransaction *dto.BlockTransaction, extrinsicIndex string) (err error) {
+// NewTransactionByExecuted build transaction and event logs from block transaction and its receipt
+func NewTransactionByExecuted(blockTimestamp uint, ethTransaction *dto.BlockTransaction, ethReceipt *dto.TransactionReceipt, extrinsicIndex string) (*Transaction, []TransactionReceipt) {
 	transaction := Transaction{
 		BlockNum:       uint(util.U256(ethTransaction.BlockNumber).Uint64()),
 		BlockTimestamp: blockTimestamp,
@@ -195,40 +206,30 @@ func (s *Storage) CreateTransactionByExecuted(ctx context.Context, blockTimestam
 	transaction.TransactionIndex = util.U256(ethTransaction.TransactionIndex).Uint64()
 	transaction.TransactionId = uint64(transaction.BlockNum)*TransactionIdGenerateCoefficient + transaction.TransactionIndex
 
-	// prevent transaction blocking
-	var (
-		receipts   []TransactionReceipt
-		ethReceipt *dto.TransactionReceipt
-	)
-
-	//  eth_getTransactionReceipt
-	if ethReceipt, err = web3.RPC.Eth.GetTransactionReceipt(ctx, transaction.Hash); err != nil {
-		return err
-	}
-
 	if ethReceipt.ContractAddress != "" {
 		transaction.Contract = ethReceipt.ContractAddress
 	}
-	// Confirm whether this transaction is to create a contract
-	if transaction.Contract != "" {
-		_ = transaction.NewContract(ctx)
-	}
 	// gas used & effective_gas_price
 	transaction.GasUsed = decimal.NewFromBigInt(ethReceipt.GasUsed, 0)
 	transaction.EffectiveGasPrice = decimal.NewFromBigInt(ethReceipt.EffectiveGasPrice, 0)
 	transaction.Success = ethReceipt.Status
 
+	var receipts []TransactionReceipt
 	for index, receipt := range ethReceipt.Logs {
+		if len(receipt.Topics) == 0 {
+			continue
+		}
 		tr := TransactionReceipt{
-			Id:              transaction.TransactionId*TxnReceiptLimit + uint64(index), // ensure unique id for each receipt
-			Topics:          strings.Join(receipt.Topics, ","),
-			Address:         receipt.Address,
-			TransactionHash: transaction.Hash,
-			Index:           index,
-			Data:            util.IfEmptyElse(util.TrimHex(receipt.Data), ""),
-			MethodHash:      receipt.Topics[0],
-			BlockTimestamp:  transaction.BlockTimestamp,
-			BlockNum:        uint64(transaction.BlockNum),
+			Id:               transaction.TransactionId*TxnReceiptLimit + uint64(index), // ensure unique id for each receipt
+			Topics:           strings.Join(receipt.Topics, ","),
+			Address:          receipt.Address,
+			TransactionHash:  transaction.Hash,
+			Index:            index,
+			Data:             util.IfEmptyElse(util.TrimHex(receipt.Data), ""),
+			MethodHash:       receipt.Topics[0],
+			BlockTimestamp:   transaction.BlockTimestamp,
+			BlockNum:         uint64(transaction.BlockNum),
+			TransactionIndex: transaction.TransactionIndex,
 		}
 		if len(receipt.Topics) > 1 {
 			tr.Topic1 = receipt.Topics[1]
@@ -241,17 +242,26 @@ func (s *Storage) CreateTransactionByExecuted(ctx context.Context, blockTimestam
 		}
 		receipts = append(receipts, tr)
 	}
-	// receipt
-	if err = sg.db.Scopes(model.IgnoreDuplicate).CreateInBatches(receipts, 3000).Error; err != nil {
-		return
+	return &transaction, receipts
+}
+
+// afterSaved hooks of batch inserted receipts and transaction are skipped, run them after block committed
+func (t *Transaction) afterSaved(ctx context.Context, receipts []TransactionReceipt) error {
+	for _, receipt := range receipts {
+		if err := receipt.EventProcess(ctx); err != nil {
+			return err
+		}
+	}
+	t.afterCreate(ctx)
+	_ = TouchAccount(ctx, t.FromAddress)
+	_ = TouchAccount(ctx, t.ToAddress)
+	if err := saveBalanceChanges(ctx, t.balanceChanges()); err != nil {
+		return err
 	}
-	// transaction
-	query := sg.AddOrUpdateItem(ctx, &transaction, []string{"hash"}, "transaction_index")
-	if query.Error != nil {
-		return query.Error
+	// internal transactions
+	if t.needTrace(ctx) {
+		_ = Publish(EvmTrace, "internal", t.Hash)
 	}
-	_ = TouchAccount(ctx, transaction.FromAddress)
-	_ = TouchAccount(ctx, transaction.ToAddress)
 	return nil
 }
 
@@ -2,6 +2,7 @@ package evm
 
 import (
 	"context"
+	"fmt"
 	"github.com/itering/subscan-plugin"
 	"github.com/itering/subscan-plugin/router"
 	"github.com/itering/subscan-plugin/storage"
@@ -13,6 +14,8 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/urfave/cli"
 	"gorm.io/gorm"
+	"os"
+	"strings"
 )
 
 type EVM struct {
@@ -36,6 +39,71 @@ func (a *EVM) Commands() []cli.Command {
 				return nil
 			},
 		},
+		{
+			Name:        "EvmImportAbi",
+			Description: "import method and event signatures from abi json file",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "file"},
+			},
+			Action: func(c *cli.Context) error {
+				count, err := dao.ImportAbiMappings(context.Background(), c.String("file"))
+				if err != nil {
+					return err
+				}
+				util.Logger().Info(fmt.Sprintf("imported %d signatures", count))
+				return nil
+			},
+		},
+		{
+			Name:        "EvmSimilarMatch",
+			Description: "backfill contracts deploy code hash and verify contracts with same code as verified contract",
+			Action: func(c *cli.Context) error {
+				return dao.RefreshSimilarMatch(context.Background())
+			},
+		},
+		{
+			Name:        "EvmHolderSnapshot",
+			Description: "export token holders and balances at block num as csv, reconstructed from transfers",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "contract"},
+				cli.StringFlag{Name: "tokenId"},
+				cli.Uint64Flag{Name: "blockNum"},
+				cli.StringFlag{Name: "output", Usage: "csv file path, stdout if empty"},
+			},
+			Action: func(c *cli.Context) error {
+				snapshot, err := dao.HolderSnapshot(context.Background(), strings.ToLower(c.String("contract")), c.String("tokenId"), c.Uint64("blockNum"))
+				if err != nil {
+					return err
+				}
+				if c.String("output") == "" {
+					return dao.WriteHolderSnapshotCsv(os.Stdout, snapshot)
+				}
+				f, err := os.Create(c.String("output"))
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				return dao.WriteHolderSnapshotCsv(f, snapshot)
+			},
+		},
+		{
+			Name:        "EvmHolderCheck",
+			Description: "compare token holder balances reconstructed from transfers with on-chain balanceOf",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "contract"},
+			},
+			Action: func(c *cli.Context) error {
+				mismatches, err := dao.CheckHolderSnapshot(context.Background(), strings.ToLower(c.String("contract")))
+				for _, m := range mismatches {
+					util.Logger().Warning(fmt.Sprintf("holder %s reconstructed %s on-chain %s", m.Holder, m.Reconstructed, m.OnChain))
+				}
+				if err != nil {
+					return err
+				}
+				util.Logger().Info(fmt.Sprintf("%d holder balances mismatch", len(mismatches)))
+				return nil
+			},
+		},
 	}
 }
 
@@ -50,6 +118,7 @@ func (a *EVM) ProcessBlock(ctx context.Context, block *storage.Block) error {
 func (a *EVM) SetRedisPool(pool subscan_plugin.RedisPool) {
 	if a.Enable() {
 		a.s = dao.Init(a.d.GetDbInstance().(*gorm.DB), pool)
+		dao.SeedAbiMappings(context.Background())
 	}
 }
 
@@ -77,14 +146,27 @@ func (a *EVM) ProcessExtrinsic(*storage.Block, *storage.Extrinsic, []storage.Eve
 	return nil
 }
 
-func (a *EVM) ProcessEvent(_ *storage.Block, _ *storage.Event, _ decimal.Decimal) error { return nil }
+func (a *EVM) ProcessEvent(block *storage.Block, event *storage.Event, _ decimal.Decimal) error {
+	if event == nil {
+		return nil
+	}
+	// native balance change of evm account
+	if strings.EqualFold(event.ModuleId, "balances") && event.EventId == "Transfer" {
+		return dao.ProcessSubstrateTransfer(context.TODO(), block, event)
+	}
+	// PolkaVM contract of revive pallet
+	if strings.EqualFold(event.ModuleId, "revive") {
+		return dao.ProcessReviveEvent(context.TODO(), block, event)
+	}
+	return nil
+}
 
 func (a *EVM) SubscribeExtrinsic() []string {
 	return nil
 }
 
 func (a *EVM) SubscribeEvent() []string {
-	return []string{"evm"}
+	return []string{"evm", "balances", "revive"}
 }
 
 func (a *EVM) Version() string {
@@ -92,7 +174,7 @@ func (a *EVM) Version() string {
 }
 
 func (a *EVM) ConsumptionQueue() []string {
-	return []string{dao.Eip20Token, dao.Eip721Token}
+	return []string{dao.Eip20Token, dao.Eip721Token, dao.Eip1155Token, dao.EvmTrace, dao.EvmNftMetadata, dao.EvmSubscription, dao.EvmProxy, dao.EvmContract}
 }
 
 func (a *EVM) ExecWorker(ctx context.Context, queue, class string, raw interface{}) error {
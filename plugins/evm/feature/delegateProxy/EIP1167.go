@@ -0,0 +1,35 @@
+package delegateProxy
+
+import (
+	"context"
+	"github.com/itering/subscan/util"
+	"strings"
+)
+
+// https://eips.ethereum.org/EIPS/eip-1167
+// minimal proxy runtime code 363d3d373d3d3d363d73<implementation>5af43d82803e903d91602b57fd5bf3
+
+const (
+	EIP1167Standard = "EIP1167"
+	eip1167Prefix   = "363d3d373d3d3d363d73"
+	eip1167Suffix   = "5af43d82803e903d91602b57fd5bf3"
+)
+
+type EIP1167 struct {
+	code string
+}
+
+func InitEIP1167(code string) *EIP1167 {
+	return &EIP1167{code: strings.ToLower(util.TrimHex(code))}
+}
+
+func (c *EIP1167) Implementation(_ context.Context) (string, error) {
+	if len(c.code) != len(eip1167Prefix)+40+len(eip1167Suffix) || !strings.HasPrefix(c.code, eip1167Prefix) || !strings.HasSuffix(c.code, eip1167Suffix) {
+		return "", ErrNotProxy
+	}
+	return util.AddHex(c.code[len(eip1167Prefix) : len(eip1167Prefix)+40]), nil
+}
+
+func (c *EIP1167) Standard() string {
+	return EIP1167Standard
+}
@@ -0,0 +1,54 @@
+package delegateProxy
+
+import (
+	"context"
+	"github.com/itering/subscan/pkg/go-web3"
+	"github.com/itering/subscan/pkg/go-web3/dto"
+	"github.com/itering/subscan/plugins/evm/contract"
+)
+
+// https://eips.ethereum.org/EIPS/eip-1967#beacon-contract-address
+// slot index 0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50 = bytes32(uint256(keccak256('eip1967.proxy.beacon')) - 1)
+// implementation is returned by beacon implementation()
+
+const (
+	EIP1967BeaconStandard = "EIP1967Beacon"
+	EIP1967BeaconSlot     = "0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50"
+	// implementation()
+	implementationSelector = "0x5c60da1b"
+)
+
+type EIP1967Beacon struct {
+	contract.Contract
+}
+
+func Init1967Beacon(w3 *web3.Web3, contract string) *EIP1967Beacon {
+	t := EIP1967Beacon{}
+	t.Eth = w3.Eth
+	t.Contract.TransParam = dto.TransactionParameters{To: contract, Data: ""}
+	return &t
+}
+
+// Beacon beacon contract address
+func (c *EIP1967Beacon) Beacon(ctx context.Context) (string, error) {
+	value, err := c.GetStorageByKey(ctx, c.Contract.TransParam.To, EIP1967BeaconSlot)
+	if err != nil {
+		return "", err
+	}
+	return wordToAddress(value)
+}
+
+func (c *EIP1967Beacon) Implementation(ctx context.Context) (string, error) {
+	beacon, err := c.Beacon(ctx)
+	if err != nil {
+		return "", err
+	}
+	if beacon == NullAddress {
+		return "", ErrNotProxy
+	}
+	return callAddress(ctx, c.Eth, beacon, implementationSelector)
+}
+
+func (c *EIP1967Beacon) Standard() string {
+	return EIP1967BeaconStandard
+}
@@ -0,0 +1,69 @@
+package delegateProxy
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/itering/subscan/pkg/go-web3"
+	"github.com/itering/subscan/pkg/go-web3/dto"
+	"github.com/itering/subscan/plugins/evm/contract"
+	"github.com/itering/subscan/util"
+	"strings"
+)
+
+// https://eips.ethereum.org/EIPS/eip-2535
+// diamond delegate call to multiple facets, facets are listed by loupe facetAddresses()
+
+const (
+	EIP2535Standard = "EIP2535"
+	// facetAddresses()
+	facetAddressesSelector = "0x52ef6b2c"
+)
+
+type EIP2535 struct {
+	contract.Contract
+}
+
+func Init2535(w3 *web3.Web3, contract string) *EIP2535 {
+	t := EIP2535{}
+	t.Eth = w3.Eth
+	t.Contract.TransParam = dto.TransactionParameters{To: contract, Data: ""}
+	return &t
+}
+
+// Facets facet addresses of diamond
+func (c *EIP2535) Facets(ctx context.Context) ([]string, error) {
+	t := c.Contract.TransParam
+	t.Data = facetAddressesSelector
+	result, err := c.Eth.Call(ctx, &t)
+	if err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, errors.New(result.Error.Message)
+	}
+	output, err := result.ToString()
+	if err != nil {
+		return nil, err
+	}
+	return DecodeFacetAddresses(output)
+}
+
+// DecodeFacetAddresses decode address[] output
+func DecodeFacetAddresses(output string) ([]string, error) {
+	addressesTy, _ := abi.NewType("address[]", "", nil)
+	values, err := abi.Arguments{{Type: addressesTy}}.UnpackValues(util.HexToBytes(output))
+	if err != nil {
+		return nil, err
+	}
+	var facets []string
+	for _, facet := range values[0].([]common.Address) {
+		facets = append(facets, strings.ToLower(facet.Hex()))
+	}
+	return facets, nil
+}
+
+func (c *EIP2535) Standard() string {
+	return EIP2535Standard
+}
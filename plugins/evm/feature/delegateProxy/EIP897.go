@@ -3,6 +3,8 @@ package delegateProxy
 import (
 	"context"
 	"errors"
+	"math/big"
+
 	"github.com/itering/subscan/pkg/go-web3"
 	"github.com/itering/subscan/pkg/go-web3/dto"
 	"github.com/itering/subscan/plugins/evm/abi"
@@ -17,7 +19,14 @@ import (
 //  function implementation() public view returns (address codeAddr);
 // }
 
-const Eip897Standard = "EIP897"
+const (
+	Eip897Standard = "EIP897"
+	// proxyType()
+	proxyTypeSelector = "0x4555d5c9"
+	// proxyTypeForwarding 1 forwarding proxy, 2 upgradeable proxy
+	proxyTypeForwarding  = 1
+	proxyTypeUpgradeable = 2
+)
 
 type EIP897 struct {
 	contract.Contract
@@ -35,7 +44,41 @@ func Init897(w3 *web3.Web3, contract string) *EIP897 {
 	return &t
 }
 
+// ProxyType proxyType() of ERCProxy, 1 forwarding or 2 upgradeable
+func (c *EIP897) ProxyType(ctx context.Context) (uint64, error) {
+	t := c.Contract.TransParam
+	t.Data = proxyTypeSelector
+	result, err := c.Eth.Call(ctx, &t)
+	if err != nil {
+		return 0, err
+	}
+	if result.Error != nil {
+		return 0, errors.New(result.Error.Message)
+	}
+	value, err := result.ToString()
+	if err != nil {
+		return 0, err
+	}
+	return decodeProxyType(value)
+}
+
+func decodeProxyType(word string) (uint64, error) {
+	word = util.TrimHex(word)
+	if len(word) != 64 {
+		return 0, ErrNotProxy
+	}
+	value, ok := new(big.Int).SetString(word, 16)
+	if !ok || !value.IsUint64() {
+		return 0, ErrNotProxy
+	}
+	return value.Uint64(), nil
+}
+
+// Implementation implementation() is trusted only if contract declares itself ERCProxy by proxyType()
 func (c *EIP897) Implementation(ctx context.Context) (string, error) {
+	if proxyType, err := c.ProxyType(ctx); err != nil || (proxyType != proxyTypeForwarding && proxyType != proxyTypeUpgradeable) {
+		return "", ErrNotProxy
+	}
 	if value, err := c.GetStorage(ctx, "implementation"); err == nil {
 		if value.Error != nil {
 			return "", errors.New(value.Error.Message)
@@ -0,0 +1,36 @@
+package delegateProxy
+
+import (
+	"context"
+	"github.com/itering/subscan/pkg/go-web3"
+	"github.com/itering/subscan/pkg/go-web3/dto"
+	"github.com/itering/subscan/plugins/evm/contract"
+)
+
+// https://github.com/safe-global/safe-smart-account/blob/main/contracts/proxies/SafeProxy.sol
+// SafeProxy fallback return singleton(storage slot 0) when called with masterCopy()
+
+const (
+	GnosisSafeStandard = "GnosisSafe"
+	// masterCopy()
+	masterCopySelector = "0xa619486e"
+)
+
+type GnosisSafe struct {
+	contract.Contract
+}
+
+func InitGnosisSafe(w3 *web3.Web3, contract string) *GnosisSafe {
+	t := GnosisSafe{}
+	t.Eth = w3.Eth
+	t.Contract.TransParam = dto.TransactionParameters{To: contract, Data: ""}
+	return &t
+}
+
+func (c *GnosisSafe) Implementation(ctx context.Context) (string, error) {
+	return callAddress(ctx, c.Eth, c.Contract.TransParam.To, masterCopySelector)
+}
+
+func (c *GnosisSafe) Standard() string {
+	return GnosisSafeStandard
+}
@@ -2,17 +2,98 @@ package delegateProxy
 
 import (
 	"context"
+	"errors"
+	"github.com/itering/subscan/pkg/go-web3"
+	"github.com/itering/subscan/pkg/go-web3/complex/types"
+	"github.com/itering/subscan/pkg/go-web3/dto"
+	"github.com/itering/subscan/pkg/go-web3/eth"
 	"github.com/itering/subscan/plugins/evm/abi"
+	"github.com/itering/subscan/util"
+	"strings"
 )
 
 // Events
 // event Upgraded(address indexed implementation);
+// event BeaconUpgraded(address indexed beacon);
+// event DiamondCut(FacetCut[] _diamondCut, address _init, bytes _calldata);
 
 var (
-	EventUpgraded = abi.EncodingMethod("Upgraded(address)")
+	EventUpgraded       = abi.EncodingMethod("Upgraded(address)")
+	EventBeaconUpgraded = abi.EncodingMethod("BeaconUpgraded(address)")
+	EventDiamondCut     = abi.EncodingMethod("DiamondCut((address,uint8,bytes4[])[],address,bytes)")
 )
 
+const NullAddress = "0x0000000000000000000000000000000000000000"
+
+var ErrNotProxy = errors.New("not proxy contract")
+
 type IDelegateProxy interface {
 	Implementation(context.Context) (string, error)
 	Standard() string
 }
+
+// Detect try proxy standards by order, return first standard with non-zero implementation
+func Detect(ctx context.Context, w3 *web3.Web3, address string) (string, string) {
+	code, _ := w3.Eth.GetCode(ctx, address, "latest")
+	proxies := []IDelegateProxy{
+		InitEIP1167(code),
+		Init1967(w3, address),
+		Init1967Beacon(w3, address),
+		InitSlot(w3, address, EIP1822Standard, EIP1822Slot),
+		InitSlot(w3, address, OZLegacyStandard, OZLegacySlot),
+		InitGnosisSafe(w3, address),
+		Init897(w3, address),
+	}
+	for _, proxy := range proxies {
+		if implementation, err := proxy.Implementation(ctx); err == nil && implementation != "" && implementation != NullAddress {
+			return proxy.Standard(), implementation
+		}
+	}
+	return "", ""
+}
+
+const (
+	opDelegateCall = 0xf4
+	opPush1        = 0x60
+	opPush32       = 0x7f
+)
+
+// HasDelegateCall runtime code contains DELEGATECALL opcode, push data is skipped.
+// contract without DELEGATECALL can not be a proxy
+func HasDelegateCall(code string) bool {
+	raw := util.HexToBytes(code)
+	for pc := 0; pc < len(raw); pc++ {
+		switch op := raw[pc]; {
+		case op == opDelegateCall:
+			return true
+		case op >= opPush1 && op <= opPush32:
+			pc += int(op-opPush1) + 1
+		}
+	}
+	return false
+}
+
+// wordToAddress last 20 bytes of 32 bytes word
+func wordToAddress(word string) (string, error) {
+	word = util.TrimHex(word)
+	if len(word) < 64 {
+		return "", errors.New("not address")
+	}
+	return util.AddHex(strings.ToLower(word[24:64])), nil
+}
+
+// callAddress eth_call function without arguments which return address
+func callAddress(ctx context.Context, e *eth.Eth, address, selector string) (string, error) {
+	result, err := e.Call(ctx, &dto.TransactionParameters{To: address, Data: types.ComplexString(selector)})
+	if err != nil {
+		return "", err
+	}
+	if result.Error != nil {
+		return "", errors.New(result.Error.Message)
+	}
+	value, err := result.ToString()
+	if err != nil {
+		return "", err
+	}
+	return wordToAddress(value)
+}
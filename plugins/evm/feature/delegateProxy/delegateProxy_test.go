@@ -33,3 +33,39 @@ func TestEIP1967_Implementation(t *testing.T) {
 	assert.Equal(t, "0x0000000000000000000000000000000000000000", Implementation)
 
 }
+
+func TestEIP1167_Implementation(t *testing.T) {
+	ctx := context.Background()
+	Implementation, err := InitEIP1167("0x363d3d373d3d3d363d73bebebebebebebebebebebebebebebebebebebebe5af43d82803e903d91602b57fd5bf3").Implementation(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "0xbebebebebebebebebebebebebebebebebebebebe", Implementation)
+
+	_, err = InitEIP1167("0x6080").Implementation(ctx)
+	assert.ErrorIs(t, err, ErrNotProxy)
+}
+
+func TestDecodeFacetAddresses(t *testing.T) {
+	facets, err := DecodeFacetAddresses("0x0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"00000000000000000000000066a1cba4138cbb5bcc3e3af1b10c7fd56b6aac0b" +
+		"0000000000000000000000002cac6e4b11d6b58f6d3c1c9d5fe8faa89f60e5a2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x66a1cba4138cbb5bcc3e3af1b10c7fd56b6aac0b", "0x2cac6e4b11d6b58f6d3c1c9d5fe8faa89f60e5a2"}, facets)
+}
+
+func TestHasDelegateCall(t *testing.T) {
+	// eip1167 minimal proxy
+	assert.True(t, HasDelegateCall("0x363d3d373d3d3d363d73bebebebebebebebebebebebebebebebebebebebe5af43d82803e903d91602b57fd5bf3"))
+	// 0xf4 in push data
+	assert.False(t, HasDelegateCall("0x61f4f400"))
+	assert.False(t, HasDelegateCall("0x6080604052"))
+	assert.False(t, HasDelegateCall(""))
+}
+
+func Test_decodeProxyType(t *testing.T) {
+	proxyType, err := decodeProxyType("0x0000000000000000000000000000000000000000000000000000000000000002")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(proxyTypeUpgradeable), proxyType)
+	_, err = decodeProxyType("0x")
+	assert.ErrorIs(t, err, ErrNotProxy)
+}
@@ -0,0 +1,43 @@
+package delegateProxy
+
+import (
+	"context"
+	"github.com/itering/subscan/pkg/go-web3"
+	"github.com/itering/subscan/pkg/go-web3/dto"
+	"github.com/itering/subscan/plugins/evm/contract"
+)
+
+const (
+	// EIP1822Slot https://eips.ethereum.org/EIPS/eip-1822 keccak256("PROXIABLE")
+	EIP1822Standard = "EIP1822"
+	EIP1822Slot     = "0xc5f16f0fcc639fa48a6947836d9850f504798523bf8c9a3a87d5876cf622bcf7"
+	// OZLegacySlot OpenZeppelin zeppelinos proxy keccak256("org.zeppelinos.proxy.implementation")
+	OZLegacyStandard = "OZLegacy"
+	OZLegacySlot     = "0x7050c9e0f4ca769c69bd3a8ef740bc37934f8e2c036e5a723fd8ee048ed3f8c3"
+)
+
+// SlotProxy implementation address stored in fixed storage slot
+type SlotProxy struct {
+	contract.Contract
+	standard string
+	slot     string
+}
+
+func InitSlot(w3 *web3.Web3, contract, standard, slot string) *SlotProxy {
+	t := SlotProxy{standard: standard, slot: slot}
+	t.Eth = w3.Eth
+	t.Contract.TransParam = dto.TransactionParameters{To: contract, Data: ""}
+	return &t
+}
+
+func (c *SlotProxy) Implementation(ctx context.Context) (string, error) {
+	value, err := c.GetStorageByKey(ctx, c.Contract.TransParam.To, c.slot)
+	if err != nil {
+		return "", err
+	}
+	return wordToAddress(value)
+}
+
+func (c *SlotProxy) Standard() string {
+	return c.standard
+}
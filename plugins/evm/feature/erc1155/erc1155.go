@@ -164,3 +164,44 @@ func (c Token) BalanceOfBatch(ctx context.Context, accountId []string, tokenId [
 	}
 	return nil, nil
 }
+
+// DecodeTransferSingle TransferSingle event data (id, value)
+func DecodeTransferSingle(data string) (tokenId string, value decimal.Decimal) {
+	data = util.TrimHex(data)
+	if len(data) < 128 {
+		return "", decimal.Zero
+	}
+	return util.U256(data[:64]).String(), util.EvmU256Decoder(data[64:128])
+}
+
+// DecodeTransferBatch TransferBatch event data (ids, values)
+func DecodeTransferBatch(data string) ([]string, []decimal.Decimal, error) {
+	eABI, err := eAbi.JSON(strings.NewReader(abi.Erc1155))
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := eABI.Unpack("TransferBatch", util.HexToBytes(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(result) != 2 {
+		return nil, nil, nil
+	}
+	ids, ok := result[0].([]*big.Int)
+	if !ok {
+		return nil, nil, nil
+	}
+	values, ok := result[1].([]*big.Int)
+	if !ok || len(ids) != len(values) {
+		return nil, nil, nil
+	}
+	var (
+		tokenIds []string
+		amounts  []decimal.Decimal
+	)
+	for index := range ids {
+		tokenIds = append(tokenIds, ids[index].String())
+		amounts = append(amounts, decimal.NewFromBigInt(values[index], 0))
+	}
+	return tokenIds, amounts, nil
+}
@@ -53,3 +53,23 @@ func Test_BaseValidate(t *testing.T) {
 	// https://moonbeam.subscan.io/tx/0x4a64cf70010667b0b5737effbdf0b9d46f323baad6c0ef3bef67df269a32f9ff
 	assert.Equal(t, "6bb7ff708619ba0610cba295a58592e0451dee2622938c8755667688daf3529b", EventURI)
 }
+
+func Test_DecodeTransfer(t *testing.T) {
+	tokenId, value := DecodeTransferSingle("0x00000000000000000000000000000000000000000000000000000000000000060000000000000000000000000000000000000000000000000000000000000001")
+	assert.Equal(t, "6", tokenId)
+	assert.Equal(t, "1", value.String())
+
+	// ids [1, 2], values [10, 20]
+	data := "0000000000000000000000000000000000000000000000000000000000000040" +
+		"00000000000000000000000000000000000000000000000000000000000000a0" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"000000000000000000000000000000000000000000000000000000000000000a" +
+		"0000000000000000000000000000000000000000000000000000000000000014"
+	tokenIds, values, err := DecodeTransferBatch(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, tokenIds)
+	assert.Equal(t, "20", values[1].String())
+}
@@ -34,7 +34,7 @@ var (
 	EventTransfer = abi.EncodingMethod("Transfer(address,address,uint256)")
 	EventDeposit  = abi.EncodingMethod("Deposit(address,uint256)")
 	EventWithdraw = abi.EncodingMethod("Withdrawal(address,uint256)")
-	// EventApproval = abi.EncodingMethod("Approval(address,address,uint256)")
+	EventApproval = abi.EncodingMethod("Approval(address,address,uint256)")
 )
 
 type Token struct {
@@ -106,3 +106,14 @@ func (c Token) BalanceOf(ctx context.Context, address string) (decimal.Decimal,
 		return decimal.Zero, err
 	}
 }
+
+func (c Token) Allowance(ctx context.Context, owner, spender string) (decimal.Decimal, error) {
+	if value, err := c.GetStorage(ctx, "allowance", owner, spender); err == nil {
+		if value.Error != nil {
+			return decimal.Zero, nil
+		}
+		return decimal.NewFromBigInt(util.U256(value.Result.(string)), 0), nil
+	} else {
+		return decimal.Zero, err
+	}
+}
@@ -34,3 +34,7 @@ func TestToken_Decimals(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, balance.IsPositive())
 }
+
+func Test_EventApproval(t *testing.T) {
+	assert.Equal(t, "8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925", EventApproval)
+}
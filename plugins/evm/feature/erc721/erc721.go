@@ -43,7 +43,8 @@ var (
 	SetBaseTokenURIMethodId = abi.EncodingMethod("setBaseTokenURI(string)")[0:8]
 	SetURIMethodId          = abi.EncodingMethod("setURI(string)")[0:8]
 	SetTokenURIMethodId     = abi.EncodingMethod("setTokenURI(uint256,string)")[0:8]
-	// EventApproval = abi.EncodingMethod("Approval(address,address,uint256)")
+	// EventApprovalForAll same as erc1155 ApprovalForAll
+	EventApprovalForAll = abi.EncodingMethod("ApprovalForAll(address,address,bool)")
 )
 
 type Token struct {
@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"fmt"
 	"github.com/itering/subscan/model"
 	balanceModel "github.com/itering/subscan/plugins/balance/model"
 	"github.com/itering/subscan/plugins/evm/dao"
@@ -19,6 +20,40 @@ func (m MockServer) TransactionsCursor(ctx context.Context, limit int, before, a
 	return nil, nil
 }
 
+func (m MockServer) LogsCursor(ctx context.Context, hash, address string, limit int, before, after *uint64) ([]dao.ReceiptJson, map[string]interface{}) {
+	return nil, nil
+}
+
+func (m MockServer) AccountBalanceHistoryCursor(ctx context.Context, address string, limit int, before, after *uint) ([]dao.BalanceHistoryJson, map[string]interface{}) {
+	return nil, nil
+}
+
+func (m MockServer) API_BalanceHistory(ctx context.Context, address string, blockNum uint) (decimal.Decimal, error) {
+	// pruned state of non-archive node
+	if blockNum < 10 {
+		return decimal.Zero, dao.ErrBalanceUnavailable
+	}
+	return decimal.New(1, 18), nil
+}
+
+var mockGasStats = []dao.EvmGasStat{
+	{BlockNum: 2, Timestamp: 12, BaseFeePerGas: decimal.New(10, 9), GasUsed: decimal.NewFromInt(15000000), GasLimit: decimal.NewFromInt(30000000), Utilization: decimal.NewFromFloat(0.5), TransactionCount: 3,
+		MinPriorityFee: decimal.New(1, 9), PriorityFeeP25: decimal.New(1, 9), PriorityFeeP50: decimal.New(2, 9), PriorityFeeP75: decimal.New(3, 9)},
+	{BlockNum: 1, Timestamp: 6, BaseFeePerGas: decimal.New(10, 9), GasUsed: decimal.NewFromInt(15000000), GasLimit: decimal.NewFromInt(30000000), Utilization: decimal.NewFromFloat(0.5)},
+}
+
+func (m MockServer) GasTracker(ctx context.Context, window int) *dao.GasTrackerJson {
+	return dao.GasOracle(mockGasStats)
+}
+
+func (m MockServer) API_GasOracle(ctx context.Context) *dao.GasTrackerJson {
+	return dao.GasOracle(mockGasStats)
+}
+
+func (m MockServer) API_GasEstimate(ctx context.Context, gasPrice decimal.Decimal) (int64, error) {
+	return dao.GasEstimate(mockGasStats, gasPrice)
+}
+
 func (m MockServer) AccountsCursor(ctx context.Context, address string, limit int, before, after *string) ([]dao.AccountsJson, map[string]interface{}) {
 	return nil, nil
 }
@@ -31,6 +66,10 @@ func (m MockServer) CollectiblesCursor(ctx context.Context, address string, cont
 	return nil, nil
 }
 
+func (m MockServer) Erc1155HoldersCursor(ctx context.Context, address, contract, tokenId string, limit int, before, after *string) ([]dao.ERC1155HolderJson, map[string]interface{}) {
+	return nil, nil
+}
+
 func (m MockServer) TokenListCursor(ctx context.Context, contract, category string, limit int, before, after *string) ([]dao.Token, map[string]interface{}) {
 	return nil, nil
 }
@@ -43,6 +82,10 @@ func (m MockServer) TokenHoldersCursor(ctx context.Context, address string, limi
 	return nil, nil
 }
 
+func (m MockServer) AccountApprovalsCursor(ctx context.Context, address, category string, limit int, before, after *uint) ([]dao.TokenApprovalJson, map[string]interface{}) {
+	return nil, nil
+}
+
 func (m MockServer) AccountTokens(ctx context.Context, address, _ string) []dao.AccountTokenJson {
 	return nil
 }
@@ -121,7 +164,33 @@ func (m MockServer) API_Transactions(ctx context.Context, opts ...model.Option)
 }
 
 func (m MockServer) API_TokenEventRes(ctx context.Context, opts ...model.Option) []dao.EtherscanTokenEventRes {
-	return nil
+	return []dao.EtherscanTokenEventRes{
+		{
+			BlockNumber:     "150",
+			Hash:            "0xdf03f7309487778643a40a7fc4a8224f8c984f7f1821d970458cabc51c6a59b6",
+			From:            "0x66b8c60c79dfad02fc04f1f13aab0f6feff8615b",
+			ContractAddress: "0x1c3d21ac81860deaf7736fe87d664eeb788bacc1",
+			To:              "0x2cac6e4b11d6b58f6d3c1c9d5fe8faa89f60e5a2",
+			TokenName:       "Tether USD",
+			TokenSymbol:     "USDT",
+			TokenDecimal:    "6",
+			Value:           "1000000",
+		},
+	}
+}
+
+func (m MockServer) API_InternalTransactions(ctx context.Context, opts ...model.Option) []dao.EtherscanInternalTxnRes {
+	return []dao.EtherscanInternalTxnRes{
+		{
+			BlockNumber: "50107",
+			Hash:        "0xdf03f7309487778643a40a7fc4a8224f8c984f7f1821d970458cabc51c6a59b6",
+			From:        "0x2cac6e4b11d6b58f6d3c1c9d5fe8faa89f60e5a2",
+			To:          "0x66a1cba4138cbb5bcc3e3af1b10c7fd56b6aac0b",
+			Type:        "call",
+			TraceId:     "0_1",
+			IsError:     "0",
+		},
+	}
 }
 
 func (m MockServer) API_ContractSourceCode(_ context.Context, c *dao.Contract) *dao.EtherscanContractSourceCodeRes {
@@ -136,10 +205,115 @@ func (m MockServer) API_GetContractCreation(ctx context.Context, addresses []str
 	}
 }
 
+func (m MockServer) API_EthSupply(ctx context.Context) (decimal.Decimal, error) {
+	return decimal.New(1, 27), nil
+}
+
+func (m MockServer) API_TokenSupply(ctx context.Context, contract string) *decimal.Decimal {
+	supply := decimal.New(21, 24)
+	return &supply
+}
+
+func (m MockServer) API_TokenBalance(ctx context.Context, contract, holder string) decimal.Decimal {
+	return decimal.New(135, 18)
+}
+
+func (m MockServer) API_BlockNoByTime(ctx context.Context, timestamp uint, closest string) *uint64 {
+	blockNum := uint64(12712551)
+	return &blockNum
+}
+
+func (m MockServer) API_BlockReward(ctx context.Context, blockNum uint64) *dao.EtherscanBlockRewardRes {
+	return &dao.EtherscanBlockRewardRes{BlockNumber: "2165403", TimeStamp: "1472533979", BlockReward: "5314181600000000000", Uncles: []interface{}{}, UncleInclusionReward: "0"}
+}
+
+func (m MockServer) API_BlockCountdown(ctx context.Context, blockNum uint64) (*dao.EtherscanBlockCountdownRes, error) {
+	if blockNum <= 100 {
+		return nil, dao.ErrBlockPassed
+	}
+	return &dao.EtherscanBlockCountdownRes{CurrentBlock: "100", CountdownBlock: fmt.Sprintf("%d", blockNum), RemainingBlock: fmt.Sprintf("%d", blockNum-100), EstimateTimeInSec: "12"}, nil
+}
+
+func (m MockServer) API_ProxyBlockNumber(ctx context.Context) string {
+	return "0xc36b29"
+}
+
+func (m MockServer) API_ProxyTransaction(ctx context.Context, hash string) *dao.EtherscanProxyTxnRes {
+	return &dao.EtherscanProxyTxnRes{Hash: hash, BlockNumber: "0x5daf3b"}
+}
+
+func (m MockServer) API_ProxyBlock(ctx context.Context, blockNum uint64, full bool) *dao.EtherscanProxyBlockRes {
+	return &dao.EtherscanProxyBlockRes{Number: fmt.Sprintf("0x%x", blockNum), Transactions: []interface{}{}, Uncles: []string{}}
+}
+
+func (m MockServer) API_ProxyCall(ctx context.Context, to, data string) (string, error) {
+	return "0x0000000000000000000000000000000000000000000000000000000000000001", nil
+}
+
 func (m MockServer) ContractsByAddr(ctx context.Context, address string) (contract *dao.Contract) {
 	return &dao.Contract{Address: address, VerifyStatus: "perfect"}
 }
 
+func (m MockServer) ContractFunctions(ctx context.Context, address string, asProxy bool) (*dao.ContractFunctionsJson, error) {
+	return &dao.ContractFunctionsJson{Address: address}, nil
+}
+
+func (m MockServer) ContractRead(ctx context.Context, address string, asProxy bool, method string, args []interface{}, from string) ([]dao.DecodedParam, error) {
+	return nil, nil
+}
+
+func (m MockServer) ContractEncodeCall(ctx context.Context, address string, asProxy bool, method string, args []interface{}, value string) (*dao.ContractCallData, error) {
+	return &dao.ContractCallData{To: address}, nil
+}
+
 func init() {
 	srv = MockServer{}
 }
+
+func (m MockServer) NftMetadataStatus(ctx context.Context, contract string) *dao.NftMetadataStatusJson {
+	return &dao.NftMetadataStatusJson{Contract: contract, Status: map[string]int64{dao.NftMetadataSuccess: 1}, Failures: []dao.NftMetadata{}}
+}
+
+func (m MockServer) RefreshNftMetadata(ctx context.Context, contract, tokenId string) (*dao.NftMetadata, error) {
+	return &dao.NftMetadata{Contract: contract, TokenId: tokenId, Status: dao.NftMetadataPending}, nil
+}
+
+func (m MockServer) CreateEventSubscription(ctx context.Context, name, address string, rawAbi []byte) (*dao.EventSubscription, error) {
+	return &dao.EventSubscription{Id: 1, Name: name, Address: address, Abi: rawAbi}, nil
+}
+
+func (m MockServer) EventSubscriptions(ctx context.Context) []dao.EventSubscription {
+	return []dao.EventSubscription{}
+}
+
+func (m MockServer) DeleteEventSubscription(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (m MockServer) BackfillEventSubscription(ctx context.Context, id uint, from, to uint64) error {
+	return nil
+}
+
+func (m MockServer) SubscriptionEventsCursor(ctx context.Context, id uint, confirmations uint64, limit int, after uint64) ([]dao.SubscriptionEventJson, map[string]interface{}, error) {
+	return []dao.SubscriptionEventJson{}, map[string]interface{}{"end_cursor": after, "has_next_page": false}, nil
+}
+
+func (m MockServer) ReviveCode(ctx context.Context, codeHash string) (*dao.ReviveCodeJson, error) {
+	return &dao.ReviveCodeJson{ReviveCode: dao.ReviveCode{CodeHash: codeHash}}, nil
+}
+
+func (m MockServer) HolderSnapshotPage(ctx context.Context, contract, tokenId string, blockNum uint64, page, row int) (*dao.HolderSnapshotJson, error) {
+	return &dao.HolderSnapshotJson{Contract: contract, BlockNum: blockNum, Holders: []dao.HolderBalance{}}, nil
+}
+
+func (m MockServer) ResolveName(ctx context.Context, name string) (*dao.NameRecord, error) {
+	return &dao.NameRecord{Node: dao.Namehash(name), Name: name}, nil
+}
+
+func (m MockServer) ReverseName(ctx context.Context, address string) (*dao.NameReverseJson, error) {
+	return &dao.NameReverseJson{Address: address}, nil
+}
+
+func (m MockServer) SubmitRawTransaction(ctx context.Context, raw string) (string, error) {
+	return "", nil
+}
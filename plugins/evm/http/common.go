@@ -1,11 +1,36 @@
 package http
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"github.com/pkg/errors"
 	"net/http"
+	"os"
+	"strings"
 )
 
+// adminToken token of management api, management api is disabled if empty
+var adminToken = os.Getenv("EVM_ADMIN_TOKEN")
+
+var ErrAdminForbidden = errors.New("admin token required")
+
+// adminAuthorized request carries admin token as "Authorization: Bearer <token>"
+func adminAuthorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1
+}
+
+// adminOnly handle is called only if request is authorized by admin token
+func adminOnly(handle func(w http.ResponseWriter, r *http.Request) error) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if !adminAuthorized(r) {
+			toJson(w, 10003, nil, ErrAdminForbidden)
+			return nil
+		}
+		return handle(w, r)
+	}
+}
+
 type J struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
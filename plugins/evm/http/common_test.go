@@ -0,0 +1,39 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminOnly(t *testing.T) {
+	srv = MockServer{}
+	handle := adminOnly(eventSubscriptionDeleteHandle)
+	tests := []struct {
+		name     string
+		token    string
+		header   string
+		wantBody string
+	}{
+		{name: "Admin api disabled", token: "", header: "Bearer ", wantBody: `"code":10003`},
+		{name: "Missing token", token: "secret", header: "", wantBody: `"code":10003`},
+		{name: "Wrong token", token: "secret", header: "Bearer wrong", wantBody: `"code":10003`},
+		{name: "Authorized", token: "secret", header: "Bearer secret", wantBody: `"code":0`},
+	}
+	defer func(token string) { adminToken = token }(adminToken)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adminToken = tt.token
+			req := httptest.NewRequest(http.MethodPost, "/api/plugin/evm/event/subscription/delete", strings.NewReader(`{"id":1}`))
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			assert.NoError(t, handle(w, req))
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+		})
+	}
+}
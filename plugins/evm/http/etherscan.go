@@ -10,8 +10,10 @@ import (
 	"github.com/itering/subscan/plugins/evm/dao"
 	"github.com/itering/subscan/util"
 	"github.com/itering/subscan/util/address"
+	"github.com/shopspring/decimal"
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
 )
 
@@ -24,8 +26,8 @@ func etherscanHandle(w http.ResponseWriter, r *http.Request) error {
 
 	// module, action params
 	actionParams := new(struct {
-		Module string `form:"module" binding:"required,oneof=logs transaction account contract"`
-		Action string `form:"action" binding:"required,oneof=getLogs getstatus gettxreceiptstatus balance balancemulti txlist txlistinternal tokentx token1155tx tokennfttx getabi getsourcecode getcontractcreation verifysourcecode checkverifystatus"`
+		Module string `form:"module" binding:"required,oneof=logs transaction account contract gastracker stats block proxy"`
+		Action string `form:"action" binding:"required,oneof=getLogs getstatus gettxreceiptstatus balance balancemulti balancehistory txlist txlistinternal txlistinternalbyhash tokentx token1155tx tokennfttx getabi getsourcecode getcontractcreation verifysourcecode checkverifystatus gasoracle gasestimate ethsupply tokensupply tokenbalance getblocknobytime getblockreward getblockcountdown eth_blockNumber eth_getTransactionByHash eth_getBlockByNumber eth_call"`
 	})
 
 	logsParams := new(struct {
@@ -175,7 +177,7 @@ func etherscanHandle(w http.ResponseWriter, r *http.Request) error {
 		if !txn.Success {
 			isError = 1
 		}
-		etherscanRes(w, 1, map[string]interface{}{"isError": isError, "errDescription": ""}, nil)
+		etherscanRes(w, 1, map[string]interface{}{"isError": isError, "errDescription": txn.TraceErrorMsg}, nil)
 		// Check Transaction Receipt Status
 
 	case "transaction-gettxreceiptstatus":
@@ -209,6 +211,22 @@ func etherscanHandle(w http.ResponseWriter, r *http.Request) error {
 		}
 		etherscanRes(w, 1, map[string]interface{}{"balance": account[accountParams.Address]}, nil)
 
+	case "account-balancehistory":
+		accountParams := new(struct {
+			Address string `form:"address" binding:"required,eth_addr"`
+			BlockNo uint   `form:"blockno" binding:"required"`
+		})
+		if err := binding.Query.Bind(r, accountParams); err != nil {
+			toJson(w, 0, nil, err)
+			return nil
+		}
+		balance, err := srv.API_BalanceHistory(r.Context(), strings.ToLower(accountParams.Address), accountParams.BlockNo)
+		if err != nil {
+			etherscanRes(w, 0, err.Error(), err)
+			return nil
+		}
+		etherscanRes(w, 1, balance.String(), nil)
+
 	case "account-balancemulti":
 		accountParams := new(struct {
 			Address string `form:"address" binding:"required"`
@@ -280,7 +298,62 @@ func etherscanHandle(w http.ResponseWriter, r *http.Request) error {
 		// https://docs.etherscan.io/etherscan-v2/api-endpoints/accounts#get-internal-transactions-by-transaction-hash
 		// https://docs.etherscan.io/etherscan-v2/api-endpoints/accounts#get-internal-transactions-by-block-range
 
-	case "account-txlistinternal": // todo
+	case "account-txlistinternal", "account-txlistinternalbyhash":
+		p := new(struct {
+			Address    string `form:"address" binding:"omitempty,eth_addr"`
+			TxHash     string `form:"txhash" binding:"omitempty,len=66"`
+			StartBlock int    `form:"startblock" binding:"min=0"`
+			EndBlock   int    `form:"endblock" binding:"min=0"`
+			Page       int    `form:"page" binding:"omitempty,min=1"`
+			Offset     int    `form:"offset" binding:"omitempty,min=1,max=1000"`
+			Sort       string `form:"sort" binding:"omitempty,oneof=asc desc"`
+		})
+		if err := binding.Query.Bind(r, p); err != nil {
+			toJson(w, 0, nil, err)
+			return nil
+		}
+		if actionParams.Action == "txlistinternalbyhash" && p.TxHash == "" {
+			etherscanRes(w, 0, nil, InvalidParam)
+			return nil
+		}
+		if p.Address == "" && p.TxHash == "" && p.StartBlock == 0 && p.EndBlock == 0 {
+			etherscanRes(w, 0, nil, InvalidParam)
+			return nil
+		}
+		if p.Offset == 0 || p.Page == 0 {
+			p.Offset = 1000
+			p.Page = 1
+		}
+		if p.Offset*p.Page > 50000 {
+			toJson(w, 0, nil, errors.New("page size too large"))
+			return nil
+		}
+		if p.Sort == "" {
+			p.Sort = "asc"
+		}
+		// addresses of internal transactions are stored in lowercase
+		p.Address, p.TxHash = strings.ToLower(p.Address), strings.ToLower(p.TxHash)
+		var opts []model.Option
+		if p.TxHash != "" {
+			opts = append(opts, model.Where("hash = ?", p.TxHash))
+		}
+		if p.Address != "" {
+			opts = append(opts, model.Where("(from_address = ? or to_address = ?)", p.Address, p.Address))
+		}
+		if p.StartBlock > 0 {
+			opts = append(opts, model.Where("block_num >= ?", p.StartBlock))
+		}
+		if p.EndBlock > 0 {
+			opts = append(opts, model.Where("block_num <= ?", p.EndBlock))
+		}
+		opts = append(opts, model.WithLimit((p.Page-1)*p.Offset, p.Offset))
+		opts = append(opts, model.Order(fmt.Sprintf("id %s", p.Sort)))
+		results := srv.API_InternalTransactions(r.Context(), opts...)
+		if len(results) == 0 {
+			etherscanRes(w, 0, nil, ErrRecordNotFound)
+			return nil
+		}
+		etherscanRes(w, 1, results, nil)
 
 	// https://docs.etherscan.io/etherscan-v2/api-endpoints/accounts#get-a-list-of-erc20-token-transfer-events-by-address
 	// https://docs.etherscan.io/etherscan-v2/api-endpoints/accounts#get-a-list-of-erc721-token-transfer-events-by-address
@@ -294,26 +367,40 @@ func etherscanHandle(w http.ResponseWriter, r *http.Request) error {
 			toJson(w, 0, nil, errors.New("page size too large"))
 			return nil
 		}
+		if tokenParams.Address == "" && tokenParams.ContractAddress == "" {
+			etherscanRes(w, 0, nil, InvalidParam)
+			return nil
+		}
 		var opts []model.Option
-		opts = append(opts, model.Where("from_address = ?", tokenParams.Address))
-		opts = append(opts, model.WithLimit(tokenParams.Page, tokenParams.Offset))
+		if tokenParams.Address != "" {
+			opts = append(opts, model.Where("(sender = ? or receiver = ?)", tokenParams.Address, tokenParams.Address))
+		}
+		if tokenParams.ContractAddress != "" {
+			opts = append(opts, model.Where("contract = ?", tokenParams.ContractAddress))
+		}
+		opts = append(opts, model.WithLimit((tokenParams.Page-1)*tokenParams.Offset, tokenParams.Offset))
 		if tokenParams.Sort == "" {
 			tokenParams.Sort = "desc"
 		}
-		if tokenParams.ToBlock > 0 {
-			opts = append(opts, model.Where("block_num <= ?", tokenParams.ToBlock))
+		// transfer_id = (block_num * TransactionIdGenerateCoefficient + transaction_index) * TxnReceiptLimit + log_index
+		blockTransferId := func(blockNum int) uint64 {
+			return uint64(blockNum) * dao.TransactionIdGenerateCoefficient * dao.TxnReceiptLimit
+		}
+		if endBlock := max(tokenParams.EndBlock, tokenParams.ToBlock); endBlock > 0 {
+			opts = append(opts, model.Where("transfer_id < ?", blockTransferId(endBlock+1)))
 		}
 		if tokenParams.StartBlock > 0 {
-			opts = append(opts, model.Where("block_num >= ?", tokenParams.StartBlock))
+			opts = append(opts, model.Where("transfer_id >= ?", blockTransferId(tokenParams.StartBlock)))
 		}
-		opts = append(opts, model.Order(fmt.Sprintf("id %s", tokenParams.Sort)))
-		var category uint
+		opts = append(opts, model.Order(fmt.Sprintf("transfer_id %s", tokenParams.Sort)))
+		opts = append(opts, model.Order(fmt.Sprintf("batch_index %s", tokenParams.Sort)))
+		var category int
 		switch actionParams.Action {
-		case "account:tokentx":
+		case "tokentx":
 			category = dao.TransferCategoryErc20
-		case "account:tokennfttx":
+		case "tokennfttx":
 			category = dao.TransferCategoryErc721
-		case "account:token1155tx":
+		case "token1155tx":
 			category = dao.TransferCategoryErc1155
 		}
 		opts = append(opts, model.Where("category = ?", category))
@@ -481,7 +568,7 @@ func etherscanHandle(w http.ResponseWriter, r *http.Request) error {
 		if p.ResolcVersion != "" {
 			input.ResolcVersion = p.ResolcVersion
 		}
-		verify, err := input.VerifyFromJsonInput(r.Context(), p.ContractAddress)
+		verify, err := input.VerifyFromJsonInput(r.Context(), p.ContractAddress, localContract.CreationCode)
 		if err != nil {
 			// raise http 500 error
 			etherscanRes(w, 0, VerifyFail, err)
@@ -515,6 +602,176 @@ func etherscanHandle(w http.ResponseWriter, r *http.Request) error {
 			etherscanRes(w, 0, "Fail - Unable to verify", errors.New("NOTOK"))
 			return nil
 		}
+
+	case "gastracker-gasoracle":
+		tracker := srv.API_GasOracle(r.Context())
+		if tracker == nil {
+			etherscanRes(w, 0, nil, ErrRecordNotFound)
+			return nil
+		}
+		var ratios []string
+		for _, block := range tracker.Blocks {
+			ratios = append(ratios, block.Utilization.String())
+		}
+		etherscanRes(w, 1, map[string]string{
+			"LastBlock":       fmt.Sprintf("%d", tracker.LastBlock),
+			"SafeGasPrice":    tracker.Safe.Shift(-9).String(),
+			"ProposeGasPrice": tracker.Propose.Shift(-9).String(),
+			"FastGasPrice":    tracker.Fast.Shift(-9).String(),
+			"suggestBaseFee":  tracker.NextBaseFee.Shift(-9).String(),
+			"gasUsedRatio":    strings.Join(ratios, ","),
+		}, nil)
+
+	case "gastracker-gasestimate":
+		gasParams := new(struct {
+			GasPrice string `form:"gasprice" binding:"required,numeric"`
+		})
+		if err := binding.Query.Bind(r, gasParams); err != nil {
+			toJson(w, 0, nil, err)
+			return nil
+		}
+		seconds, err := srv.API_GasEstimate(r.Context(), decimal.RequireFromString(gasParams.GasPrice))
+		if err != nil {
+			etherscanRes(w, 0, "", err)
+			return nil
+		}
+		etherscanRes(w, 1, fmt.Sprintf("%d", seconds), nil)
+
+	case "stats-ethsupply":
+		supply, err := srv.API_EthSupply(r.Context())
+		if err != nil {
+			etherscanRes(w, 0, "", err)
+			return nil
+		}
+		etherscanRes(w, 1, supply.String(), nil)
+
+	case "stats-tokensupply":
+		statsParams := new(struct {
+			ContractAddress string `form:"contractaddress" binding:"required,eth_addr"`
+		})
+		if err := binding.Query.Bind(r, statsParams); err != nil {
+			toJson(w, 0, nil, err)
+			return nil
+		}
+		supply := srv.API_TokenSupply(r.Context(), strings.ToLower(statsParams.ContractAddress))
+		if supply == nil {
+			etherscanRes(w, 0, nil, ErrRecordNotFound)
+			return nil
+		}
+		etherscanRes(w, 1, supply.String(), nil)
+
+	case "stats-tokenbalance":
+		statsParams := new(struct {
+			ContractAddress string `form:"contractaddress" binding:"required,eth_addr"`
+			Address         string `form:"address" binding:"required,eth_addr"`
+		})
+		if err := binding.Query.Bind(r, statsParams); err != nil {
+			toJson(w, 0, nil, err)
+			return nil
+		}
+		etherscanRes(w, 1, srv.API_TokenBalance(r.Context(), strings.ToLower(statsParams.ContractAddress), strings.ToLower(statsParams.Address)).String(), nil)
+
+	case "block-getblocknobytime":
+		blockParams := new(struct {
+			Timestamp uint   `form:"timestamp" binding:"required"`
+			Closest   string `form:"closest" binding:"required,oneof=before after"`
+		})
+		if err := binding.Query.Bind(r, blockParams); err != nil {
+			toJson(w, 0, nil, err)
+			return nil
+		}
+		blockNum := srv.API_BlockNoByTime(r.Context(), blockParams.Timestamp, blockParams.Closest)
+		if blockNum == nil {
+			etherscanRes(w, 0, nil, ErrRecordNotFound)
+			return nil
+		}
+		etherscanRes(w, 1, fmt.Sprintf("%d", *blockNum), nil)
+
+	case "block-getblockreward":
+		blockParams := new(struct {
+			BlockNo uint64 `form:"blockno" binding:"required"`
+		})
+		if err := binding.Query.Bind(r, blockParams); err != nil {
+			toJson(w, 0, nil, err)
+			return nil
+		}
+		reward := srv.API_BlockReward(r.Context(), blockParams.BlockNo)
+		if reward == nil {
+			etherscanRes(w, 0, nil, ErrRecordNotFound)
+			return nil
+		}
+		etherscanRes(w, 1, reward, nil)
+
+	case "block-getblockcountdown":
+		blockParams := new(struct {
+			BlockNo uint64 `form:"blockno" binding:"required"`
+		})
+		if err := binding.Query.Bind(r, blockParams); err != nil {
+			toJson(w, 0, nil, err)
+			return nil
+		}
+		countdown, err := srv.API_BlockCountdown(r.Context(), blockParams.BlockNo)
+		if err != nil {
+			etherscanRes(w, 0, err.Error(), errors.New("NOTOK"))
+			return nil
+		}
+		etherscanRes(w, 1, countdown, nil)
+
+	case "proxy-eth_blockNumber":
+		etherscanRes(w, 1, srv.API_ProxyBlockNumber(r.Context()), nil)
+
+	case "proxy-eth_getTransactionByHash":
+		if err := binding.Query.Bind(r, txParams); err != nil || txParams.TxHash == "" {
+			etherscanRes(w, 0, nil, InvalidParam)
+			return nil
+		}
+		txn := srv.API_ProxyTransaction(r.Context(), txParams.TxHash)
+		if txn == nil {
+			etherscanRes(w, 0, nil, ErrRecordNotFound)
+			return nil
+		}
+		etherscanRes(w, 1, txn, nil)
+
+	case "proxy-eth_getBlockByNumber":
+		proxyParams := new(struct {
+			Tag     string `form:"tag" binding:"required"`
+			Boolean bool   `form:"boolean"`
+		})
+		if err := binding.Query.Bind(r, proxyParams); err != nil {
+			toJson(w, 0, nil, err)
+			return nil
+		}
+		if proxyParams.Tag == "latest" {
+			proxyParams.Tag = srv.API_ProxyBlockNumber(r.Context())
+		}
+		blockNum, err := strconv.ParseUint(util.TrimHex(proxyParams.Tag), 16, 64)
+		if err != nil {
+			etherscanRes(w, 0, nil, InvalidParam)
+			return nil
+		}
+		block := srv.API_ProxyBlock(r.Context(), blockNum, proxyParams.Boolean)
+		if block == nil {
+			etherscanRes(w, 0, nil, ErrRecordNotFound)
+			return nil
+		}
+		etherscanRes(w, 1, block, nil)
+
+	case "proxy-eth_call":
+		proxyParams := new(struct {
+			To   string `form:"to" binding:"required,eth_addr"`
+			Data string `form:"data" binding:"required,hexadecimal"`
+			Tag  string `form:"tag" binding:"omitempty,eq=latest"`
+		})
+		if err := binding.Query.Bind(r, proxyParams); err != nil {
+			toJson(w, 0, nil, err)
+			return nil
+		}
+		result, err := srv.API_ProxyCall(r.Context(), proxyParams.To, proxyParams.Data)
+		if err != nil {
+			etherscanRes(w, 0, "", err)
+			return nil
+		}
+		etherscanRes(w, 1, result, nil)
 	}
 	return nil
 }
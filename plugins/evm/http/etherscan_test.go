@@ -50,6 +50,96 @@ func TestEtherscanHandle(t *testing.T) {
 			wantStatus: http.StatusOK,
 			wantBody:   `"status":1`,
 		},
+		{
+			name:       "Valid account-balancehistory request",
+			query:      "module=account&action=balancehistory&address=0x66b8c60c79dfad02fc04f1f13aab0f6feff8615b&blockno=100",
+			wantStatus: http.StatusOK,
+			wantBody:   `"result":"1000000000000000000"`,
+		},
+		{
+			name:       "Unavailable account-balancehistory request",
+			query:      "module=account&action=balancehistory&address=0x66b8c60c79dfad02fc04f1f13aab0f6feff8615b&blockno=1",
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":0`,
+		},
+		{
+			name:       "Valid gastracker-gasoracle request",
+			query:      "module=gastracker&action=gasoracle",
+			wantStatus: http.StatusOK,
+			wantBody:   `"ProposeGasPrice":"12"`,
+		},
+		{
+			name:       "Valid gastracker-gasestimate request",
+			query:      "module=gastracker&action=gasestimate&gasprice=20000000000",
+			wantStatus: http.StatusOK,
+			wantBody:   `"result":"6"`,
+		},
+		{
+			name:       "Valid stats-ethsupply request",
+			query:      "module=stats&action=ethsupply",
+			wantStatus: http.StatusOK,
+			wantBody:   `"result":"1000000000000000000000000000"`,
+		},
+		{
+			name:       "Valid stats-tokensupply request",
+			query:      "module=stats&action=tokensupply&contractaddress=0x57d90b64a1a57749b0f932f1a3395792e12e7055",
+			wantStatus: http.StatusOK,
+			wantBody:   `"result":"21000000000000000000000000"`,
+		},
+		{
+			name:       "Valid stats-tokenbalance request",
+			query:      "module=stats&action=tokenbalance&contractaddress=0x57d90b64a1a57749b0f932f1a3395792e12e7055&address=0xe04f27eb70e025b78871a2ad7eabe85e61212761",
+			wantStatus: http.StatusOK,
+			wantBody:   `"result":"135000000000000000000"`,
+		},
+		{
+			name:       "Valid block-getblocknobytime request",
+			query:      "module=block&action=getblocknobytime&timestamp=1578638524&closest=before",
+			wantStatus: http.StatusOK,
+			wantBody:   `"result":"12712551"`,
+		},
+		{
+			name:       "Valid block-getblockreward request",
+			query:      "module=block&action=getblockreward&blockno=2165403",
+			wantStatus: http.StatusOK,
+			wantBody:   `"blockReward":"5314181600000000000"`,
+		},
+		{
+			name:       "Valid block-getblockcountdown request",
+			query:      "module=block&action=getblockcountdown&blockno=200",
+			wantStatus: http.StatusOK,
+			wantBody:   `"RemainingBlock":"100"`,
+		},
+		{
+			name:       "Passed block-getblockcountdown request",
+			query:      "module=block&action=getblockcountdown&blockno=50",
+			wantStatus: http.StatusOK,
+			wantBody:   `"result":"Error! Block number already pass"`,
+		},
+		{
+			name:       "Valid proxy-eth_blockNumber request",
+			query:      "module=proxy&action=eth_blockNumber",
+			wantStatus: http.StatusOK,
+			wantBody:   `"result":"0xc36b29"`,
+		},
+		{
+			name:       "Valid proxy-eth_getTransactionByHash request",
+			query:      "module=proxy&action=eth_getTransactionByHash&txhash=0xdf03f7309487778643a40a7fc4a8224f8c984f7f1821d970458cabc51c6a59b6",
+			wantStatus: http.StatusOK,
+			wantBody:   `"blockNumber":"0x5daf3b"`,
+		},
+		{
+			name:       "Valid proxy-eth_getBlockByNumber request",
+			query:      "module=proxy&action=eth_getBlockByNumber&tag=0x10d4f&boolean=true",
+			wantStatus: http.StatusOK,
+			wantBody:   `"number":"0x10d4f"`,
+		},
+		{
+			name:       "Valid proxy-eth_call request",
+			query:      "module=proxy&action=eth_call&to=0xAEEF46DB4855E25702F8237E8f403FddcaF931C0&data=0x70a08231000000000000000000000000e16359506c028e51f16be38986ec5746251e9724&tag=latest",
+			wantStatus: http.StatusOK,
+			wantBody:   `"result":"0x0000000000000000000000000000000000000000000000000000000000000001"`,
+		},
 		{
 			name:       "Valid account-balanceMulti request",
 			query:      "module=account&action=balancemulti&address=0x66b8c60c79dfad02fc04f1f13aab0f6feff8615b,0xe22d73f5dcccb31a994ad4e7ad265cf69b4e725a",
@@ -62,6 +152,24 @@ func TestEtherscanHandle(t *testing.T) {
 			wantStatus: http.StatusOK,
 			wantBody:   `"status":1`,
 		},
+		{
+			name:       "Valid account-txlistinternal request",
+			query:      "module=account&action=txlistinternal&address=0x66b8c60c79dfad02fc04f1f13aab0f6feff8615b&startblock=0&endblock=99999999&sort=asc",
+			wantStatus: http.StatusOK,
+			wantBody:   `"traceId":"0_1"`,
+		},
+		{
+			name:       "Valid account-txlistinternalbyhash request",
+			query:      "module=account&action=txlistinternalbyhash&txhash=0xdf03f7309487778643a40a7fc4a8224f8c984f7f1821d970458cabc51c6a59b6",
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":1`,
+		},
+		{
+			name:       "Missing txhash of account-txlistinternalbyhash request",
+			query:      "module=account&action=txlistinternalbyhash",
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":0`,
+		},
 		{
 			name:       "Valid account-tokentx request", // erc20
 			query:      "module=account&action=tokentx&address=0x66b8c60c79dfad02fc04f1f13aab0f6feff8615b&offset=100&page=1",
@@ -74,6 +182,66 @@ func TestEtherscanHandle(t *testing.T) {
 			wantStatus: http.StatusOK,
 			wantBody:   `"status":1`,
 		},
+		{
+			name:       "Valid account-tokentx request by contract address",
+			query:      "module=account&action=tokentx&contractaddress=0x1c3d21ac81860deaf7736fe87d664eeb788bacc1&offset=100&page=1",
+			wantStatus: http.StatusOK,
+			wantBody:   `"tokenSymbol":"USDT"`,
+		},
+		{
+			name:       "Valid account-tokentx request in block range",
+			query:      "module=account&action=tokentx&address=0x66b8c60c79dfad02fc04f1f13aab0f6feff8615b&contractaddress=0x1c3d21ac81860deaf7736fe87d664eeb788bacc1&startblock=100&endblock=200&offset=10&page=1&sort=asc",
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":1`,
+		},
+		{
+			name:       "Missing address and contract address of account-tokentx request",
+			query:      "module=account&action=tokentx&offset=100&page=1",
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":0`,
+		},
+		{
+			name:       "Invalid address of account-tokentx request",
+			query:      "module=account&action=tokentx&address=0x66b8&offset=100&page=1",
+			wantStatus: http.StatusOK,
+			wantBody:   `"code":0`,
+		},
+		{
+			name:       "Invalid sort of account-tokentx request",
+			query:      "module=account&action=tokentx&address=0x66b8c60c79dfad02fc04f1f13aab0f6feff8615b&offset=100&page=1&sort=random",
+			wantStatus: http.StatusOK,
+			wantBody:   `"code":0`,
+		},
+		{
+			name:       "Page too large of account-tokentx request",
+			query:      "module=account&action=tokentx&address=0x66b8c60c79dfad02fc04f1f13aab0f6feff8615b&offset=1000&page=51",
+			wantStatus: http.StatusOK,
+			wantBody:   `"message":"page size too large"`,
+		},
+		{
+			name:       "Valid account-tokennfttx request by contract address",
+			query:      "module=account&action=tokennfttx&contractaddress=0x1c3d21ac81860deaf7736fe87d664eeb788bacc1&offset=100&page=1",
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":1`,
+		},
+		{
+			name:       "Valid account-tokennfttx request in block range",
+			query:      "module=account&action=tokennfttx&address=0x66b8c60c79dfad02fc04f1f13aab0f6feff8615b&startblock=100&toBlock=200&offset=10&page=2",
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":1`,
+		},
+		{
+			name:       "Missing address and contract address of account-tokennfttx request",
+			query:      "module=account&action=tokennfttx&offset=100&page=1",
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":0`,
+		},
+		{
+			name:       "Missing page of account-tokennfttx request",
+			query:      "module=account&action=tokennfttx&address=0x66b8c60c79dfad02fc04f1f13aab0f6feff8615b&offset=100",
+			wantStatus: http.StatusOK,
+			wantBody:   `"code":0`,
+		},
 		{
 			name:       "Valid account-token1155tx request", // erc1155
 			query:      "module=account&action=token1155tx&address=0x66b8c60c79dfad02fc04f1f13aab0f6feff8615b&offset=100&page=1",
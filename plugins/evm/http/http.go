@@ -6,6 +6,7 @@ import (
 	"github.com/itering/subscan/model"
 	"github.com/itering/subscan/plugins/evm/contract"
 	"github.com/itering/subscan/plugins/evm/dao"
+	"github.com/itering/subscan/util"
 	"github.com/itering/subscan/util/validator"
 	"net/http"
 )
@@ -20,26 +21,49 @@ func Router() []router.Http {
 
 		{"transactions", transactionsHandle, http.MethodPost},
 		{"transaction", transactionHandle, http.MethodPost},
+		{"transaction/submit", transactionSubmitHandle, http.MethodPost},
+		{"logs", logsHandle, http.MethodPost},
 
 		{"accounts", accountsHandle, http.MethodPost},
 
 		{"contract", contractHandle, http.MethodPost},
 		{"contracts", contractsHandle, http.MethodPost},
 		{"contract/solcs", solcVersions, http.MethodPost},
+		{"contract/functions", contractFunctionsHandle, http.MethodPost},
+		{"contract/read", contractReadHandle, http.MethodPost},
+		{"contract/write", contractWriteHandle, http.MethodPost},
 		{"contract/resolcs", resolcVersions, http.MethodPost},
+		{"revive/code", reviveCodeHandle, http.MethodPost},
 
 		// token holder
 		{"token/holder", tokenHolderHandle, http.MethodPost},
+		{"token/holder/snapshot", tokenHolderSnapshotHandle, http.MethodPost},
 		{"tokens", tokenListHandle, http.MethodPost},
 		{"token/transfer", tokenTransferHandle, http.MethodPost},
 		{"token/erc721/collectibles", collectiblesHandle, http.MethodPost},
+		{"token/erc1155/holders", erc1155HoldersHandle, http.MethodPost},
+		{"token/nft/metadata/status", nftMetadataStatusHandle, http.MethodPost},
+		{"token/nft/metadata/refresh", adminOnly(nftMetadataRefreshHandle), http.MethodPost},
 		{"account/tokens", accountTokensHandle, http.MethodPost},
+		{"account/approvals", accountApprovalsHandle, http.MethodPost},
+		{"account/balance_history", accountBalanceHistoryHandle, http.MethodPost},
+
+		{"gas/tracker", gasTrackerHandle, http.MethodPost},
+
+		{"name/resolve", nameResolveHandle, http.MethodPost},
+		{"name/reverse", nameReverseHandle, http.MethodPost},
+
+		{"event/subscription/create", adminOnly(eventSubscriptionCreateHandle), http.MethodPost},
+		{"event/subscription/delete", adminOnly(eventSubscriptionDeleteHandle), http.MethodPost},
+		{"event/subscriptions", eventSubscriptionsHandle, http.MethodPost},
+		{"event/subscription/events", subscriptionEventsHandle, http.MethodPost},
+		{"event/subscription/backfill", adminOnly(eventSubscriptionBackfillHandle), http.MethodPost},
 	}
 }
 
 type accountTokensParams struct {
 	Address  string `json:"address" validate:"required,eth_addr"`
-	Category string `json:"category" validate:"omitempty,oneof=erc20 erc721"`
+	Category string `json:"category" validate:"omitempty,oneof=erc20 erc721 erc1155"`
 }
 
 // @Summary Get account tokens
@@ -60,6 +84,81 @@ func accountTokensHandle(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+type accountApprovalsParams struct {
+	Address  string `json:"address" validate:"required,eth_addr"`
+	Category string `json:"category" validate:"omitempty,oneof=erc20 erc721 erc1155"`
+	Limit    int    `json:"row" validate:"min=1,max=100"`
+	Before   *uint  `json:"before" validate:"omitempty,min=0"`
+	After    *uint  `json:"after" validate:"omitempty,min=0"`
+}
+
+// @Summary Get account outstanding token approvals
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body accountApprovalsParams true "params"
+// @Success 200 {object} J{data=object{list=[]dao.TokenApprovalJson,pagination=object}}
+// @Router /api/plugin/evm/account/approvals [post]
+func accountApprovalsHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(accountApprovalsParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	list, page := srv.AccountApprovalsCursor(r.Context(), p.Address, p.Category, p.Limit, p.Before, p.After)
+	toJson(w, 0, map[string]interface{}{"list": list, "pagination": page}, nil)
+	return nil
+}
+
+type accountBalanceHistoryParams struct {
+	Address string `json:"address" validate:"required,eth_addr"`
+	Limit   int    `json:"row" validate:"min=1,max=100"`
+	Before  *uint  `json:"before" validate:"omitempty,min=0"`
+	After   *uint  `json:"after" validate:"omitempty,min=0"`
+}
+
+// @Summary Evm account native balance changes by block
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body accountBalanceHistoryParams true "params"
+// @Success 200 {object} J{data=object{list=[]dao.BalanceHistoryJson,pagination=object}}
+// @Router /api/plugin/evm/account/balance_history [post]
+func accountBalanceHistoryHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(accountBalanceHistoryParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	list, page := srv.AccountBalanceHistoryCursor(r.Context(), p.Address, p.Limit, p.Before, p.After)
+	toJson(w, 0, map[string]interface{}{"list": list, "pagination": page}, nil)
+	return nil
+}
+
+type gasTrackerParams struct {
+	Window int `json:"window" validate:"omitempty,min=1,max=1000"`
+}
+
+// @Summary Evm gas tracker
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body gasTrackerParams true "params"
+// @Success 200 {object} J{data=dao.GasTrackerJson}
+// @Router /api/plugin/evm/gas/tracker [post]
+func gasTrackerHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(gasTrackerParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	if p.Window == 0 {
+		p.Window = dao.GasOracleWindow
+	}
+	toJson(w, 0, srv.GasTracker(r.Context(), p.Window), nil)
+	return nil
+}
+
 type collectiblesParams struct {
 	Address  string  `json:"address" validate:"omitempty,eth_addr"`
 	Contract string  `json:"contract" validate:"omitempty,eth_addr"`
@@ -90,11 +189,91 @@ func collectiblesHandle(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+type nftMetadataStatusParams struct {
+	Contract string `json:"contract" validate:"required,eth_addr"`
+}
+
+// @Summary Evm nft metadata fetch status of collection
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body nftMetadataStatusParams true "params"
+// @Success 200 {object} J{data=dao.NftMetadataStatusJson}
+// @Router /api/plugin/evm/token/nft/metadata/status [post]
+func nftMetadataStatusHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(nftMetadataStatusParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	toJson(w, 0, srv.NftMetadataStatus(r.Context(), p.Contract), nil)
+	return nil
+}
+
+type nftMetadataRefreshParams struct {
+	Contract string `json:"contract" validate:"required,eth_addr"`
+	TokenId  string `json:"token_id" validate:"required"`
+}
+
+// @Summary Evm refresh nft metadata of token id
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body nftMetadataRefreshParams true "params"
+// @Success 200 {object} J{data=dao.NftMetadata}
+// @Param Authorization header string true "Bearer EVM_ADMIN_TOKEN"
+// @Router /api/plugin/evm/token/nft/metadata/refresh [post]
+func nftMetadataRefreshHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(nftMetadataRefreshParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	record, err := srv.RefreshNftMetadata(r.Context(), p.Contract, p.TokenId)
+	if err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	toJson(w, 0, record, nil)
+	return nil
+}
+
+type erc1155HoldersParams struct {
+	Address  string  `json:"address" validate:"omitempty,eth_addr"`
+	Contract string  `json:"contract" validate:"omitempty,eth_addr"`
+	TokenId  string  `json:"token_id" validate:"omitempty,numeric"`
+	Limit    int     `json:"row" validate:"min=1,max=100"`
+	Before   *string `json:"before" validate:"omitempty,min=0"`
+	After    *string `json:"after" validate:"omitempty,min=0"`
+}
+
+// @Summary Evm Erc1155 token id balances
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body erc1155HoldersParams true "params"
+// @Success 200 {object} J{data=object{list=[]dao.ERC1155HolderJson,pagination=object}}
+// @Router /api/plugin/evm/token/erc1155/holders [post]
+func erc1155HoldersHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(erc1155HoldersParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	if p.Address == "" && p.Contract == "" {
+		toJson(w, 10001, nil, fmt.Errorf("address or contract is required"))
+		return nil
+	}
+	list, page := srv.Erc1155HoldersCursor(r.Context(), p.Address, p.Contract, p.TokenId, p.Limit, p.Before, p.After)
+	toJson(w, 0, map[string]interface{}{"list": list, "pagination": page}, nil)
+	return nil
+}
+
 type tokenListParams struct {
 	Limit    int     `json:"row" validate:"min=1,max=100"`
 	Before   *string `json:"before" validate:"omitempty,min=0"`
 	After    *string `json:"after" validate:"omitempty,min=0"`
-	Category string  `json:"category" validate:"omitempty,oneof=erc20 erc721"`
+	Category string  `json:"category" validate:"omitempty,oneof=erc20 erc721 erc1155"`
 	Contract string  `json:"contract" validate:"omitempty,eth_addr"`
 }
 
@@ -122,7 +301,7 @@ type tokenTransferParams struct {
 	Limit        int    `json:"row" validate:"min=1,max=100"`
 	Before       *uint  `json:"before" validate:"omitempty,min=0"`
 	After        *uint  `json:"after" validate:"omitempty,min=0"`
-	Category     string `json:"category" validate:"omitempty,oneof=erc20 erc721"`
+	Category     string `json:"category" validate:"omitempty,oneof=erc20 erc721 erc1155"`
 }
 
 // @Summary Evm token transfer
@@ -172,6 +351,37 @@ func tokenHolderHandle(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+type tokenHolderSnapshotParams struct {
+	TokenAddress string `json:"token_address" validate:"required,eth_addr"`
+	TokenId      string `json:"token_id" validate:"omitempty,numeric"`
+	BlockNum     uint64 `json:"block_num" validate:"required"`
+	Page         int    `json:"page" validate:"min=0"`
+	Row          int    `json:"row" validate:"min=1,max=100"`
+}
+
+// @Summary Evm token holders and balances at block num, reconstructed from transfers
+// @Description paged by page and row, full snapshot csv is exported by EvmHolderSnapshot command
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body tokenHolderSnapshotParams true "params"
+// @Success 200 {object} J{data=dao.HolderSnapshotJson}
+// @Router /api/plugin/evm/token/holder/snapshot [post]
+func tokenHolderSnapshotHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(tokenHolderSnapshotParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	snapshot, err := srv.HolderSnapshotPage(r.Context(), p.TokenAddress, p.TokenId, p.BlockNum, p.Page, p.Row)
+	if err != nil {
+		toJson(w, 10002, nil, err)
+		return nil
+	}
+	toJson(w, 0, snapshot, nil)
+	return nil
+}
+
 type EvmBlocks struct {
 	Limit  int   `json:"row" validate:"min=1,max=100"`
 	Before *uint `json:"before" validate:"omitempty,min=0"`
@@ -257,6 +467,36 @@ func transactionHandle(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+type logsParams struct {
+	Hash    string  `json:"hash" validate:"omitempty,len=66"`
+	Address string  `json:"address" validate:"omitempty,eth_addr"`
+	Limit   int     `json:"row" validate:"min=1,max=100"`
+	Before  *uint64 `json:"before" validate:"omitempty,min=0"`
+	After   *uint64 `json:"after" validate:"omitempty,min=0"`
+}
+
+// @Summary Evm event logs with decoded event
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body logsParams true "params"
+// @Success 200 {object} J{data=object{list=[]dao.ReceiptJson,pagination=object}}
+// @Router /api/plugin/evm/logs [post]
+func logsHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(logsParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	if p.Hash == "" && p.Address == "" {
+		toJson(w, 10001, nil, fmt.Errorf("hash or address is required"))
+		return nil
+	}
+	list, page := srv.LogsCursor(r.Context(), p.Hash, p.Address, p.Limit, p.Before, p.After)
+	toJson(w, 0, map[string]interface{}{"list": list, "pagination": page}, nil)
+	return nil
+}
+
 type transactionsParams struct {
 	Limit    int    `json:"row" validate:"min=1,max=100"`
 	Before   *uint  `json:"before" validate:"omitempty,min=0"`
@@ -341,6 +581,93 @@ func contractHandle(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+type contractFunctionsParams struct {
+	Address string `json:"address" validate:"required,eth_addr"`
+	Proxy   bool   `json:"proxy"`
+}
+
+// @Summary Evm verified contract read and write functions
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body contractFunctionsParams true "params"
+// @Success 200 {object} J{data=dao.ContractFunctionsJson}
+// @Router /api/plugin/evm/contract/functions [post]
+func contractFunctionsHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(contractFunctionsParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	functions, err := srv.ContractFunctions(r.Context(), p.Address, p.Proxy)
+	if err != nil {
+		toJson(w, 10002, nil, err)
+		return nil
+	}
+	toJson(w, 0, functions, nil)
+	return nil
+}
+
+type contractReadParams struct {
+	Address string        `json:"address" validate:"required,eth_addr"`
+	Proxy   bool          `json:"proxy"`
+	Method  string        `json:"method" validate:"required"`
+	Args    []interface{} `json:"args"`
+	From    string        `json:"from" validate:"omitempty,eth_addr"`
+}
+
+// @Summary Evm call contract view function
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body contractReadParams true "params"
+// @Success 200 {object} J{data=[]dao.DecodedParam}
+// @Router /api/plugin/evm/contract/read [post]
+func contractReadHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(contractReadParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	outputs, err := srv.ContractRead(r.Context(), p.Address, p.Proxy, p.Method, p.Args, p.From)
+	if err != nil {
+		toJson(w, 10002, nil, err)
+		return nil
+	}
+	toJson(w, 0, outputs, nil)
+	return nil
+}
+
+type contractWriteParams struct {
+	Address string        `json:"address" validate:"required,eth_addr"`
+	Proxy   bool          `json:"proxy"`
+	Method  string        `json:"method" validate:"required"`
+	Args    []interface{} `json:"args"`
+	Value   string        `json:"value" validate:"omitempty,numeric"`
+}
+
+// @Summary Evm encode calldata of contract state-changing function
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body contractWriteParams true "params"
+// @Success 200 {object} J{data=dao.ContractCallData}
+// @Router /api/plugin/evm/contract/write [post]
+func contractWriteHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(contractWriteParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	callData, err := srv.ContractEncodeCall(r.Context(), p.Address, p.Proxy, p.Method, p.Args, p.Value)
+	if err != nil {
+		toJson(w, 10002, nil, err)
+		return nil
+	}
+	toJson(w, 0, callData, nil)
+	return nil
+}
+
 type contractsParams struct {
 	Limit  int     `json:"row" validate:"min=1,max=100"`
 	Before *string `json:"before" validate:"omitempty,min=0"`
@@ -398,3 +725,230 @@ func solcVersions(w http.ResponseWriter, r *http.Request) error {
 	toJson(w, 0, list, err)
 	return nil
 }
+
+type eventSubscriptionCreateParams struct {
+	Name    string        `json:"name" validate:"omitempty,max=100"`
+	Address string        `json:"address" validate:"required,eth_addr"`
+	Abi     []interface{} `json:"abi" validate:"required,min=1"`
+}
+
+// @Summary Evm register contract event subscription
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body eventSubscriptionCreateParams true "params"
+// @Success 200 {object} J{data=dao.EventSubscription}
+// @Param Authorization header string true "Bearer EVM_ADMIN_TOKEN"
+// @Router /api/plugin/evm/event/subscription/create [post]
+func eventSubscriptionCreateHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(eventSubscriptionCreateParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	subscription, err := srv.CreateEventSubscription(r.Context(), p.Name, p.Address, []byte(util.ToString(p.Abi)))
+	if err != nil {
+		toJson(w, 10002, nil, err)
+		return nil
+	}
+	toJson(w, 0, subscription, nil)
+	return nil
+}
+
+type eventSubscriptionParams struct {
+	Id uint `json:"id" validate:"required"`
+}
+
+// @Summary Evm delete contract event subscription and its events
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body eventSubscriptionParams true "params"
+// @Success 200 {object} J{data=object}
+// @Param Authorization header string true "Bearer EVM_ADMIN_TOKEN"
+// @Router /api/plugin/evm/event/subscription/delete [post]
+func eventSubscriptionDeleteHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(eventSubscriptionParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	if err := srv.DeleteEventSubscription(r.Context(), p.Id); err != nil {
+		toJson(w, 10002, nil, err)
+		return nil
+	}
+	toJson(w, 0, nil, nil)
+	return nil
+}
+
+// @Summary Evm contract event subscriptions
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Success 200 {object} J{data=[]dao.EventSubscription}
+// @Router /api/plugin/evm/event/subscriptions [post]
+func eventSubscriptionsHandle(w http.ResponseWriter, r *http.Request) error {
+	toJson(w, 0, srv.EventSubscriptions(r.Context()), nil)
+	return nil
+}
+
+type subscriptionEventsParams struct {
+	Id            uint   `json:"id" validate:"required"`
+	Confirmations uint64 `json:"confirmations"`
+	Limit         int    `json:"row" validate:"min=1,max=1000"`
+	After         uint64 `json:"after"`
+}
+
+// @Summary Evm decoded events of subscription, order by seq, save end_cursor as after once handled
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body subscriptionEventsParams true "params"
+// @Success 200 {object} J{data=object{list=[]dao.SubscriptionEventJson,pagination=object}}
+// @Router /api/plugin/evm/event/subscription/events [post]
+func subscriptionEventsHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(subscriptionEventsParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	list, page, err := srv.SubscriptionEventsCursor(r.Context(), p.Id, p.Confirmations, p.Limit, p.After)
+	if err != nil {
+		toJson(w, 10002, nil, err)
+		return nil
+	}
+	toJson(w, 0, map[string]interface{}{"list": list, "pagination": page}, nil)
+	return nil
+}
+
+type eventSubscriptionBackfillParams struct {
+	Id        uint   `json:"id" validate:"required"`
+	FromBlock uint64 `json:"from_block"`
+	ToBlock   uint64 `json:"to_block" validate:"gtefield=FromBlock"`
+}
+
+// @Summary Evm backfill subscription events from saved receipts of block range
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body eventSubscriptionBackfillParams true "params"
+// @Success 200 {object} J{data=object}
+// @Param Authorization header string true "Bearer EVM_ADMIN_TOKEN"
+// @Router /api/plugin/evm/event/subscription/backfill [post]
+func eventSubscriptionBackfillHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(eventSubscriptionBackfillParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	if err := srv.BackfillEventSubscription(r.Context(), p.Id, p.FromBlock, p.ToBlock); err != nil {
+		toJson(w, 10002, nil, err)
+		return nil
+	}
+	toJson(w, 0, nil, nil)
+	return nil
+}
+
+type reviveCodeParams struct {
+	CodeHash string `json:"code_hash" validate:"required,len=66"`
+}
+
+// @Summary Polkadot pvm code blob uploaded to revive pallet
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body reviveCodeParams true "params"
+// @Success 200 {object} J{data=dao.ReviveCodeJson}
+// @Router /api/plugin/evm/revive/code [post]
+func reviveCodeHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(reviveCodeParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	code, err := srv.ReviveCode(r.Context(), p.CodeHash)
+	if err != nil {
+		toJson(w, 10002, nil, err)
+		return nil
+	}
+	toJson(w, 0, code, nil)
+	return nil
+}
+
+type transactionSubmitParams struct {
+	Raw string `json:"raw" validate:"required,startswith=0x,hexadecimal"`
+}
+
+// @Summary Relay signed raw evm transaction to rpc node
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body transactionSubmitParams true "params"
+// @Success 200 {object} J{data=object{hash=string}}
+// @Router /api/plugin/evm/transaction/submit [post]
+func transactionSubmitHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(transactionSubmitParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	hash, err := srv.SubmitRawTransaction(r.Context(), p.Raw)
+	if err != nil {
+		toJson(w, 10002, nil, err)
+		return nil
+	}
+	toJson(w, 0, map[string]string{"hash": hash}, nil)
+	return nil
+}
+
+type nameResolveParams struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// @Summary Evm forward lookup of name service name
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body nameResolveParams true "params"
+// @Success 200 {object} J{data=dao.NameRecord}
+// @Router /api/plugin/evm/name/resolve [post]
+func nameResolveHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(nameResolveParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	record, err := srv.ResolveName(r.Context(), p.Name)
+	if err != nil {
+		toJson(w, 10002, nil, err)
+		return nil
+	}
+	toJson(w, 0, record, nil)
+	return nil
+}
+
+type nameReverseParams struct {
+	Address string `json:"address" validate:"required,eth_addr"`
+}
+
+// @Summary Evm primary name of address
+// @Tags EVM
+// @Accept json
+// @Produce json
+// @Param params body nameReverseParams true "params"
+// @Success 200 {object} J{data=dao.NameReverseJson}
+// @Router /api/plugin/evm/name/reverse [post]
+func nameReverseHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(nameReverseParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	reverse, err := srv.ReverseName(r.Context(), p.Address)
+	if err != nil {
+		toJson(w, 10002, nil, err)
+		return nil
+	}
+	toJson(w, 0, reverse, nil)
+	return nil
+}
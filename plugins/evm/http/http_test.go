@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenHolderSnapshotHandle(t *testing.T) {
+	srv = MockServer{}
+	tests := []struct {
+		name     string
+		body     string
+		wantBody string
+	}{
+		{name: "Missing row", body: `{"token_address":"0xffffffff1fcacbd218edc0eba20fc2308c778080","block_num":100}`, wantBody: `"code":10001`},
+		{name: "Row over limit", body: `{"token_address":"0xffffffff1fcacbd218edc0eba20fc2308c778080","block_num":100,"row":1000}`, wantBody: `"code":10001`},
+		{name: "Negative page", body: `{"token_address":"0xffffffff1fcacbd218edc0eba20fc2308c778080","block_num":100,"row":10,"page":-1}`, wantBody: `"code":10001`},
+		{name: "Valid", body: `{"token_address":"0xffffffff1fcacbd218edc0eba20fc2308c778080","block_num":100,"row":10,"page":1}`, wantBody: `"block_num":100`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/plugin/evm/token/holder/snapshot", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			assert.NoError(t, tokenHolderSnapshotHandle(w, req))
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+		})
+	}
+}
@@ -2,7 +2,9 @@ package workers
 
 import (
 	"context"
+	"fmt"
 	"github.com/itering/subscan/plugins/evm/dao"
+	"github.com/itering/subscan/plugins/evm/feature/erc1155"
 	"github.com/itering/subscan/util"
 )
 
@@ -17,37 +19,117 @@ func Emit(ctx context.Context, queue, class string, raw interface{}) error {
 
 		case "balance":
 			// [contract, address]
-			var contractAddress []string
-			util.Logger().Error(util.UnmarshalAny(&contractAddress, raw))
+			contractAddress, err := stringArgs(raw, 2)
+			if err != nil {
+				return err
+			}
 			return dao.RefreshHolder(ctx, contractAddress[0], contractAddress[1], queue)
 
+		case "approval":
+			var receipt dao.TransactionReceipt
+			util.Logger().Error(util.UnmarshalAny(&receipt, raw))
+			return receipt.ProcessApproval(ctx, queue)
+
+		case "allowance":
+			// [contract, owner, spender]
+			args, err := stringArgs(raw, 3)
+			if err != nil {
+				return err
+			}
+			return dao.RefreshAllowance(ctx, args[0], args[1], args[2])
+
 		case "holder":
 			// [contract, tokenId]
-			var args []string
-			util.Logger().Error(util.UnmarshalAny(&args, raw))
+			args, err := stringArgs(raw, 2)
+			if err != nil {
+				return err
+			}
 			if token := dao.GetTokenByContract(ctx, args[0]); token != nil {
 				return token.RefreshErc721Holders(ctx, args[1])
 			}
 		}
 
-		// case dao.Eip1155Token:
-		// 	switch class {
-		// 	case "balance":
-		// 		// [contract, address, tokenIds []string ]
-		// 		var contractAddress []string
-		// 		util.Logger().Error(util.UnmarshalAny(&contractAddress, raw))
-		// 		if contractAddress[1] == dao.NullAddress {
-		// 			return nil
-		// 		}
-		// 		return dao.RefreshErc1155Holder(ctx, contractAddress[0], contractAddress[1], contractAddress[2])
-		// 	default:
-		// 		var receipt dao.TransactionReceipt
-		// 		util.Logger().Error(util.UnmarshalAny(&receipt, raw))
-		// 		switch util.TrimHex(receipt.MethodHash) {
-		// 		case erc1155.EventTransferBatch, erc1155.EventTransferSingle, erc1155.EventURI:
-		// 			return receipt.ProcessErc1155(ctx)
-		// 		}
-		// 	}
+	case dao.EvmTrace:
+		switch class {
+		case "internal":
+			var hash string
+			util.Logger().Error(util.UnmarshalAny(&hash, raw))
+			return dao.ProcessInternalTransactions(ctx, hash)
+		}
+
+	case dao.EvmNftMetadata:
+		switch class {
+		case "fetch":
+			var args dao.NftMetadataArgs
+			util.Logger().Error(util.UnmarshalAny(&args, raw))
+			return dao.FetchNftMetadata(ctx, &args)
+		}
+
+	case dao.EvmSubscription:
+		switch class {
+		case "backfill":
+			var args dao.SubscriptionBackfillArgs
+			util.Logger().Error(util.UnmarshalAny(&args, raw))
+			return dao.ProcessSubscriptionBackfill(ctx, &args)
+		}
+
+	case dao.EvmProxy:
+		switch class {
+		case "detect":
+			var address string
+			util.Logger().Error(util.UnmarshalAny(&address, raw))
+			return dao.RefreshProxy(ctx, address)
+		}
+
+	case dao.EvmContract:
+		// contract address
+		var address string
+		util.Logger().Error(util.UnmarshalAny(&address, raw))
+		switch class {
+		case "code":
+			return dao.ProcessContractCode(ctx, address)
+		case "similar":
+			return dao.ProcessSimilarContracts(ctx, address)
+		case "revive_code":
+			return dao.RefreshReviveCode(ctx, address)
+		}
+
+	case dao.Eip1155Token:
+		switch class {
+		case "balance":
+			// [contract, address, tokenId]
+			contractAddress, err := stringArgs(raw, 3)
+			if err != nil {
+				return err
+			}
+			if contractAddress[1] == dao.NullAddress {
+				return nil
+			}
+			return dao.RefreshErc1155Holder(ctx, contractAddress[0], contractAddress[1], contractAddress[2])
+		case "approval":
+			var receipt dao.TransactionReceipt
+			util.Logger().Error(util.UnmarshalAny(&receipt, raw))
+			return receipt.ProcessApproval(ctx, queue)
+		default:
+			var receipt dao.TransactionReceipt
+			util.Logger().Error(util.UnmarshalAny(&receipt, raw))
+			switch util.TrimHex(receipt.MethodHash) {
+			case erc1155.EventTransferBatch, erc1155.EventTransferSingle, erc1155.EventURI:
+				return receipt.ProcessErc1155(ctx)
+			}
+		}
 	}
 	return nil
 }
+
+// stringArgs unmarshal queue args of string list, at least n args are required
+func stringArgs(raw interface{}, n int) ([]string, error) {
+	var args []string
+	if err := util.UnmarshalAny(&args, raw); err != nil {
+		return nil, err
+	}
+	if len(args) < n {
+		return nil, fmt.Errorf("invalid args %v, %d required", args, n)
+	}
+	return args, nil
+}
@@ -0,0 +1,34 @@
+package workers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/itering/subscan/plugins/evm/dao"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_stringArgs(t *testing.T) {
+	args, err := stringArgs([]interface{}{"0x1", "0x2"}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x1", "0x2"}, args)
+
+	args, err = stringArgs(`["0x1","0x2","1"]`, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x1", "0x2", "1"}, args)
+
+	_, err = stringArgs([]interface{}{"0x1"}, 2)
+	assert.Error(t, err)
+	_, err = stringArgs(nil, 2)
+	assert.Error(t, err)
+	_, err = stringArgs("0x1", 2)
+	assert.Error(t, err)
+}
+
+func TestEmitInvalidArgs(t *testing.T) {
+	ctx := context.TODO()
+	assert.Error(t, Emit(ctx, dao.Eip20Token, "balance", []string{"0x1"}))
+	assert.Error(t, Emit(ctx, dao.Eip721Token, "allowance", []string{"0x1", "0x2"}))
+	assert.Error(t, Emit(ctx, dao.Eip721Token, "holder", []string{}))
+	assert.Error(t, Emit(ctx, dao.Eip1155Token, "balance", []string{"0x1", "0x2"}))
+}
@@ -0,0 +1,48 @@
+// Package daotest provide a dry run storage.Dao for plugin dao tests, sql is recorded instead of executed
+package daotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/itering/subscan-plugin/storage"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	Alice = "d43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"
+	Bob   = "8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48"
+)
+
+// Recorder record sql of dry run db
+type Recorder struct {
+	logger.Interface
+	SQL []string
+}
+
+func (r *Recorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	r.SQL = append(r.SQL, sql)
+}
+
+type Dao struct {
+	storage.Dao
+	db *gorm.DB
+}
+
+func (d *Dao) GetDbInstance() any {
+	return d.db
+}
+
+// New dry run dao, no database connection is made
+func New(t *testing.T) (*Dao, *Recorder) {
+	recorder := &Recorder{Interface: logger.Discard}
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: recorder})
+	assert.NoError(t, err)
+	return &Dao{db: db}, recorder
+}
@@ -0,0 +1,296 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"github.com/itering/subscan-plugin/storage"
+	"github.com/itering/subscan/model"
+	pModel "github.com/itering/subscan/plugins/nominationpools/model"
+	"github.com/itering/subscan/share/substrate"
+	"github.com/itering/subscan/util"
+	"github.com/itering/subscan/util/address"
+	"github.com/itering/substrate-api-rpc/rpc"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log"
+	"strings"
+)
+
+// eventParamsLen least params count of events, malformed or old runtime events with fewer params are skipped
+var eventParamsLen = map[string]int{
+	"Created":               2,
+	"Bonded":                3,
+	"PaidOut":               3,
+	"Unbonded":              3,
+	"Withdrawn":             3,
+	"MemberRemoved":         2,
+	"StateChanged":          1,
+	"MetadataUpdated":       1,
+	"PoolSlashed":           1,
+	"PoolCommissionUpdated": 1,
+	"Destroyed":             1,
+}
+
+func EmitEvent(ctx context.Context, d storage.Dao, event *storage.Event, block *storage.Block) error {
+	var paramEvent []storage.EventParam
+	_ = util.UnmarshalAny(&paramEvent, event.Params)
+	if len(paramEvent) < eventParamsLen[event.EventId] {
+		return nil
+	}
+	blockNum := uint(block.BlockNum)
+	history := pModel.MemberHistory{
+		EventIndex:     fmt.Sprintf("%d-%d", event.BlockNum, event.EventIdx),
+		BlockNum:       blockNum,
+		BlockTimestamp: block.BlockTimestamp,
+		ExtrinsicIndex: fmt.Sprintf("%d-%d", event.BlockNum, event.ExtrinsicIdx),
+	}
+	switch event.EventId {
+	// [depositor, pool_id]
+	case "Created":
+		history.Address = model.CheckoutParamValueAddress(paramEvent[0].Value)
+		history.PoolId = util.UIntFromInterface(paramEvent[1].Value)
+		history.Action = pModel.ActionCreate
+		if err := RefreshPool(ctx, d, history.PoolId, blockNum); err != nil {
+			return err
+		}
+		db := d.GetDbInstance().(*gorm.DB)
+		if err := db.WithContext(ctx).Model(&pModel.Pool{}).Where("pool_id = ?", history.PoolId).Update("created_block", blockNum).Error; err != nil {
+			return err
+		}
+		return createHistory(ctx, d, &history)
+	// [member, pool_id, bonded, joined]
+	case "Bonded":
+		history.Address = model.CheckoutParamValueAddress(paramEvent[0].Value)
+		history.PoolId = util.UIntFromInterface(paramEvent[1].Value)
+		history.Amount = util.DecimalFromInterface(paramEvent[2].Value)
+		history.Action = pModel.ActionBond
+		if len(paramEvent) > 3 && util.BoolFromInterface(paramEvent[3].Value) {
+			history.Action = pModel.ActionJoin
+		}
+	// [member, pool_id, payout]
+	case "PaidOut":
+		history.Address = model.CheckoutParamValueAddress(paramEvent[0].Value)
+		history.PoolId = util.UIntFromInterface(paramEvent[1].Value)
+		history.Amount = util.DecimalFromInterface(paramEvent[2].Value)
+		history.Action = pModel.ActionPayout
+		if err := createHistory(ctx, d, &history); err != nil {
+			return err
+		}
+		db := d.GetDbInstance().(*gorm.DB)
+		return db.WithContext(ctx).Model(&pModel.PoolMember{}).Where("address = ?", history.Address).
+			Update("total_reward", gorm.Expr("total_reward + ?", history.Amount)).Error
+	// [member, pool_id, balance, points, era]
+	case "Unbonded":
+		history.Address = model.CheckoutParamValueAddress(paramEvent[0].Value)
+		history.PoolId = util.UIntFromInterface(paramEvent[1].Value)
+		history.Amount = util.DecimalFromInterface(paramEvent[2].Value)
+		history.Action = pModel.ActionUnbond
+		if len(paramEvent) > 4 {
+			history.Points = util.DecimalFromInterface(paramEvent[3].Value)
+			history.Era = util.UIntFromInterface(paramEvent[4].Value)
+		}
+	// [member, pool_id, balance, points]
+	case "Withdrawn":
+		history.Address = model.CheckoutParamValueAddress(paramEvent[0].Value)
+		history.PoolId = util.UIntFromInterface(paramEvent[1].Value)
+		history.Amount = util.DecimalFromInterface(paramEvent[2].Value)
+		history.Action = pModel.ActionWithdraw
+		if len(paramEvent) > 3 {
+			history.Points = util.DecimalFromInterface(paramEvent[3].Value)
+		}
+	// [pool_id, member, released_balance]
+	case "MemberRemoved":
+		history.PoolId = util.UIntFromInterface(paramEvent[0].Value)
+		history.Address = model.CheckoutParamValueAddress(paramEvent[1].Value)
+		history.Action = pModel.ActionRemove
+		if len(paramEvent) > 2 {
+			history.Amount = util.DecimalFromInterface(paramEvent[2].Value)
+		}
+		if err := createHistory(ctx, d, &history); err != nil {
+			return err
+		}
+		db := d.GetDbInstance().(*gorm.DB)
+		if err := db.WithContext(ctx).Where("address = ?", history.Address).Delete(&pModel.PoolMember{}).Error; err != nil {
+			return err
+		}
+		return RefreshPool(ctx, d, history.PoolId, blockNum)
+	// [pool_id, new_state]
+	case "StateChanged", "MetadataUpdated", "PoolSlashed", "PoolCommissionUpdated":
+		return RefreshPool(ctx, d, util.UIntFromInterface(paramEvent[0].Value), blockNum)
+	// [pool_id]
+	case "Destroyed":
+		poolId := util.UIntFromInterface(paramEvent[0].Value)
+		db := d.GetDbInstance().(*gorm.DB)
+		return db.WithContext(ctx).Model(&pModel.Pool{}).Where("pool_id = ?", poolId).
+			Updates(map[string]interface{}{"state": "Destroyed", "destroyed_block": blockNum, "updated_block": blockNum}).Error
+	default:
+		return nil
+	}
+	// bond, unbond and withdraw will change member and pool points
+	if err := createHistory(ctx, d, &history); err != nil {
+		return err
+	}
+	if err := RefreshPoolMember(ctx, d, history.Address, blockNum, history.Action == pModel.ActionJoin); err != nil {
+		return err
+	}
+	return RefreshPool(ctx, d, history.PoolId, blockNum)
+}
+
+// EmitExtrinsic roles and metadata updated by extrinsic, RolesUpdated event has no pool id
+func EmitExtrinsic(ctx context.Context, d storage.Dao, block *storage.Block, extrinsic *storage.Extrinsic) error {
+	if !extrinsic.Success {
+		return nil
+	}
+	switch strings.ToLower(extrinsic.CallModuleFunction) {
+	case "update_roles", "set_metadata":
+	default:
+		return nil
+	}
+	var params []storage.ExtrinsicParam
+	_ = util.UnmarshalAny(&params, extrinsic.Params)
+	for _, param := range params {
+		if param.Name == "pool_id" {
+			return RefreshPool(ctx, d, util.UIntFromInterface(param.Value), uint(block.BlockNum))
+		}
+	}
+	return nil
+}
+
+func createHistory(ctx context.Context, d storage.Dao, history *pModel.MemberHistory) error {
+	db := d.GetDbInstance().(*gorm.DB)
+	return db.WithContext(ctx).Scopes(model.IgnoreDuplicate).Create(history).Error
+}
+
+// RefreshPool sync NominationPools.BondedPools and NominationPools.Metadata of pool
+func RefreshPool(ctx context.Context, d storage.Dao, poolId, blockNum uint) error {
+	raw, err := rpc.ReadStorage(nil, "NominationPools", "BondedPools", "", util.U32Encode(uint32(poolId)))
+	if err != nil {
+		return err
+	}
+	var inner pModel.BondedPoolInner
+	raw.ToAny(&inner)
+	if inner.State == "" {
+		// pool already destroyed
+		return nil
+	}
+	metadata, err := rpc.ReadStorage(nil, "NominationPools", "Metadata", "", util.U32Encode(uint32(poolId)))
+	if err != nil {
+		return err
+	}
+	return savePool(ctx, d, poolId, blockNum, &inner, metadata.ToString())
+}
+
+func savePool(ctx context.Context, d storage.Dao, poolId, blockNum uint, inner *pModel.BondedPoolInner, metadata string) error {
+	bouncer := inner.Roles.Bouncer
+	if bouncer == "" {
+		bouncer = inner.Roles.StateToggler
+	}
+	pool := pModel.Pool{
+		PoolId:       poolId,
+		Name:         metadataName(metadata),
+		State:        inner.State,
+		Depositor:    address.Format(inner.Roles.Depositor),
+		Root:         address.Format(inner.Roles.Root),
+		Nominator:    address.Format(inner.Roles.Nominator),
+		Bouncer:      address.Format(bouncer),
+		Points:       inner.Points,
+		MemberCount:  inner.MemberCounter,
+		UpdatedBlock: blockNum,
+	}
+	db := d.GetDbInstance().(*gorm.DB)
+	return db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "pool_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "state", "depositor", "root", "nominator", "bouncer", "points", "member_count", "updated_block"}),
+	}).Create(&pool).Error
+}
+
+// metadataName pool metadata is utf8 bytes
+func metadataName(metadata string) string {
+	if strings.HasPrefix(metadata, "0x") {
+		return strings.TrimRight(string(util.HexToBytes(metadata)), "\x00")
+	}
+	return metadata
+}
+
+// RefreshPoolMember sync NominationPools.PoolMembers of account
+func RefreshPoolMember(ctx context.Context, d storage.Dao, accountId string, blockNum uint, joined bool) error {
+	accountId = address.Format(accountId)
+	if accountId == "" {
+		return nil
+	}
+	raw, err := rpc.ReadStorage(nil, "NominationPools", "PoolMembers", "", accountId)
+	if err != nil {
+		return err
+	}
+	var info pModel.PoolMemberInfo
+	raw.ToAny(&info)
+	return savePoolMember(ctx, d, accountId, blockNum, &info, joined)
+}
+
+// savePoolMember joined member reset joined block and total reward, member left pool is removed
+func savePoolMember(ctx context.Context, d storage.Dao, accountId string, blockNum uint, info *pModel.PoolMemberInfo, joined bool) error {
+	db := d.GetDbInstance().(*gorm.DB)
+	if info.PoolId == 0 {
+		return db.WithContext(ctx).Where("address = ?", accountId).Delete(&pModel.PoolMember{}).Error
+	}
+	member := pModel.PoolMember{
+		Address:   accountId,
+		PoolId:    info.PoolId,
+		Points:    info.Points,
+		Unbonding: sumUnbonding(info.UnbondingEras),
+	}
+	updates := []string{"pool_id", "points", "unbonding"}
+	if joined {
+		member.JoinedBlock = blockNum
+		member.TotalReward = decimal.Zero
+		updates = append(updates, "joined_block", "total_reward")
+	}
+	return db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "address"}},
+		DoUpdates: clause.AssignmentColumns(updates),
+	}).Create(&member).Error
+}
+
+// sumUnbonding BoundedBTreeMap<EraIndex, Balance>, decoded as map or [[era, balance]] list
+func sumUnbonding(unbondingEras interface{}) decimal.Decimal {
+	total := decimal.Zero
+	switch eras := unbondingEras.(type) {
+	case map[string]interface{}:
+		for _, balance := range eras {
+			total = total.Add(util.DecimalFromInterface(balance))
+		}
+	case []interface{}:
+		for _, item := range eras {
+			if pair, ok := item.([]interface{}); ok && len(pair) == 2 {
+				total = total.Add(util.DecimalFromInterface(pair[1]))
+			}
+		}
+	}
+	return total
+}
+
+func InitPools(d storage.Dao) {
+	ctx := context.Background()
+	blockNum, _ := d.GetCurrentBlockNum(ctx)
+	if err := substrate.BatchReadKeysPaged(ctx, "NominationPools", "BondedPools", "", func(keys []string, scaleType string) error {
+		r, _ := substrate.BatchStorageByKey(ctx, keys, scaleType, "")
+		for key := range r {
+			val, _ := substrate.ParseStorageKey(key)
+			util.Logger().Error(RefreshPool(ctx, d, uint(val[0].ToInt()), uint(blockNum)))
+		}
+		return nil
+	}); err != nil {
+		log.Panic(err)
+	}
+	if err := substrate.BatchReadKeysPaged(ctx, "NominationPools", "PoolMembers", "", func(keys []string, scaleType string) error {
+		r, _ := substrate.BatchStorageByKey(ctx, keys, scaleType, "")
+		for key := range r {
+			val, _ := substrate.ParseStorageKey(key)
+			util.Logger().Error(RefreshPoolMember(ctx, d, val[0].ToString(), uint(blockNum), false))
+		}
+		return nil
+	}); err != nil {
+		log.Panic(err)
+	}
+}
@@ -0,0 +1,144 @@
+package dao
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/itering/subscan-plugin/storage"
+	"github.com/itering/subscan/plugins/internal/daotest"
+	pModel "github.com/itering/subscan/plugins/nominationpools/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_savePool(t *testing.T) {
+	d, recorder := daotest.New(t)
+	var inner pModel.BondedPoolInner
+	inner.MemberCounter = 2
+	inner.Points = decimal.NewFromInt(1000)
+	inner.State = "Open"
+	inner.Roles.Depositor = daotest.Alice
+	inner.Roles.Root = daotest.Alice
+	inner.Roles.Nominator = daotest.Bob
+	// legacy runtime
+	inner.Roles.StateToggler = daotest.Bob
+	assert.NoError(t, savePool(context.TODO(), d, 12, 100, &inner, "0x706f6f6c"))
+	assert.Len(t, recorder.SQL, 1)
+	sql := recorder.SQL[0]
+	assert.Contains(t, sql, "INSERT INTO `nomination_pools`")
+	assert.Contains(t, sql, "'pool',")
+	assert.Contains(t, sql, "'Open',")
+	assert.Contains(t, sql, "'"+daotest.Bob+"','1000',2,0,0,100)")
+	assert.Contains(t, sql, "ON DUPLICATE KEY UPDATE `name`=VALUES(`name`),`state`=VALUES(`state`)")
+	assert.Contains(t, sql, "`member_count`=VALUES(`member_count`),`updated_block`=VALUES(`updated_block`)")
+	// created and destroyed block are not overwritten
+	assert.NotContains(t, sql, "`created_block`=VALUES")
+	assert.NotContains(t, sql, "`destroyed_block`=VALUES")
+}
+
+func Test_savePoolMember(t *testing.T) {
+	ctx := context.TODO()
+	info := pModel.PoolMemberInfo{PoolId: 12, Points: decimal.NewFromInt(500), UnbondingEras: map[string]interface{}{"10": "100"}}
+
+	d, recorder := daotest.New(t)
+	assert.NoError(t, savePoolMember(ctx, d, daotest.Alice, 100, &info, true))
+	assert.Equal(t, []string{
+		"INSERT INTO `nomination_pool_members` (`address`,`pool_id`,`points`,`unbonding`,`total_reward`,`joined_block`) VALUES ('" + daotest.Alice + "',12,'500','100','0',100) " +
+			"ON DUPLICATE KEY UPDATE `pool_id`=VALUES(`pool_id`),`points`=VALUES(`points`),`unbonding`=VALUES(`unbonding`),`joined_block`=VALUES(`joined_block`),`total_reward`=VALUES(`total_reward`)",
+	}, recorder.SQL)
+
+	// bond extra keep joined block and total reward
+	d, recorder = daotest.New(t)
+	assert.NoError(t, savePoolMember(ctx, d, daotest.Alice, 200, &info, false))
+	assert.Len(t, recorder.SQL, 1)
+	assert.Contains(t, recorder.SQL[0], "ON DUPLICATE KEY UPDATE `pool_id`=VALUES(`pool_id`),`points`=VALUES(`points`),`unbonding`=VALUES(`unbonding`)")
+	assert.NotContains(t, recorder.SQL[0], "`joined_block`=VALUES")
+	assert.NotContains(t, recorder.SQL[0], "`total_reward`=VALUES")
+
+	// fully withdrawn member
+	d, recorder = daotest.New(t)
+	assert.NoError(t, savePoolMember(ctx, d, daotest.Alice, 300, &pModel.PoolMemberInfo{}, false))
+	assert.Equal(t, []string{"DELETE FROM `nomination_pool_members` WHERE address = '" + daotest.Alice + "'"}, recorder.SQL)
+}
+
+func TestEmitEventMember(t *testing.T) {
+	ctx := context.TODO()
+	block := &storage.Block{BlockNum: 100, BlockTimestamp: 1700000000}
+
+	d, recorder := daotest.New(t)
+	paidOut := &storage.Event{BlockNum: 100, EventIdx: 3, ExtrinsicIdx: 1, ModuleId: "nominationpools", EventId: "PaidOut",
+		Params: []byte(`[{"type":"AccountId","value":"` + daotest.Alice + `"},{"type":"PoolId","value":12},{"type":"Balance","value":"500"}]`)}
+	assert.NoError(t, EmitEvent(ctx, d, paidOut, block))
+	assert.Len(t, recorder.SQL, 2)
+	assert.Contains(t, recorder.SQL[0], "INSERT INTO `nomination_pool_member_histories`")
+	assert.Contains(t, recorder.SQL[0], "'100-3','"+daotest.Alice+"',12,'payout','500'")
+	assert.Contains(t, recorder.SQL[0], "ON DUPLICATE KEY UPDATE `id`=`id`")
+	assert.Contains(t, recorder.SQL[1], "UPDATE `nomination_pool_members` SET `total_reward`=total_reward + '500' WHERE address = '"+daotest.Alice+"'")
+
+	d, recorder = daotest.New(t)
+	destroyed := &storage.Event{BlockNum: 100, EventIdx: 4, ModuleId: "nominationpools", EventId: "Destroyed",
+		Params: []byte(`[{"type":"PoolId","value":12}]`)}
+	assert.NoError(t, EmitEvent(ctx, d, destroyed, block))
+	assert.Len(t, recorder.SQL, 1)
+	assert.Contains(t, recorder.SQL[0], "UPDATE `nomination_pools` SET `destroyed_block`=100,`state`='Destroyed',`updated_block`=100 WHERE pool_id = 12")
+
+	// not subscribed event
+	d, recorder = daotest.New(t)
+	assert.NoError(t, EmitEvent(ctx, d, &storage.Event{EventId: "UnbondingPoolSlashed"}, block))
+	assert.Empty(t, recorder.SQL)
+
+	// short params of malformed event
+	for eventId, least := range eventParamsLen {
+		params := strings.TrimSuffix(strings.Repeat(`{"type":"PoolId","value":12},`, least-1), ",")
+		assert.NoError(t, EmitEvent(ctx, d, &storage.Event{EventId: eventId, Params: []byte("[" + params + "]")}, block), eventId)
+	}
+	assert.Empty(t, recorder.SQL)
+}
+
+func TestEmitExtrinsicIgnored(t *testing.T) {
+	d, recorder := daotest.New(t)
+	block := &storage.Block{BlockNum: 100}
+	assert.NoError(t, EmitExtrinsic(context.TODO(), d, block, &storage.Extrinsic{CallModuleFunction: "update_roles", Success: false}))
+	assert.NoError(t, EmitExtrinsic(context.TODO(), d, block, &storage.Extrinsic{CallModuleFunction: "join", Success: true}))
+	assert.NoError(t, EmitExtrinsic(context.TODO(), d, block, &storage.Extrinsic{CallModuleFunction: "set_metadata", Success: true, Params: []byte(`[]`)}))
+	assert.Empty(t, recorder.SQL)
+}
+
+func Test_metadataName(t *testing.T) {
+	assert.Equal(t, "pool", metadataName("0x706f6f6c"))
+	assert.Equal(t, "pool", metadataName("0x706f6f6c0000"))
+	assert.Equal(t, "pool", metadataName("pool"))
+	assert.Equal(t, "", metadataName(""))
+}
+
+func Test_sumUnbonding(t *testing.T) {
+	assert.Equal(t, "300", sumUnbonding(map[string]interface{}{"10": "100", "11": float64(200)}).String())
+	assert.Equal(t, "300", sumUnbonding([]interface{}{[]interface{}{float64(10), "100"}, []interface{}{float64(11), "200"}}).String())
+	assert.True(t, sumUnbonding(nil).IsZero())
+}
+
+func TestQuery(t *testing.T) {
+	ctx := context.TODO()
+	d, recorder := daotest.New(t)
+	_ = GetPool(ctx, d, 12)
+	_ = GetPoolMember(ctx, d, daotest.Alice)
+	after := uint(20)
+	list, hasPrev, hasNext := PoolsCursor(ctx, d, 10, nil, &after)
+	assert.Empty(t, list)
+	assert.True(t, hasPrev)
+	assert.False(t, hasNext)
+	before := uint(5)
+	_, hasPrev, hasNext = PoolMembersCursor(ctx, d, 10, &before, nil)
+	assert.False(t, hasPrev)
+	assert.True(t, hasNext)
+	_, _, _ = MemberHistoriesCursor(ctx, d, 10, nil, nil)
+
+	assert.Equal(t, []string{
+		"SELECT * FROM `nomination_pools` WHERE pool_id = 12 ORDER BY `nomination_pools`.`id` LIMIT 1",
+		"SELECT * FROM `nomination_pool_members` WHERE address = '" + daotest.Alice + "' ORDER BY `nomination_pool_members`.`id` LIMIT 1",
+		"SELECT * FROM `nomination_pools` WHERE pool_id < 20 ORDER BY pool_id desc LIMIT 11",
+		"SELECT * FROM `nomination_pool_members` WHERE id > 5 ORDER BY id asc LIMIT 11",
+		"SELECT * FROM `nomination_pool_member_histories` ORDER BY id desc LIMIT 11",
+	}, recorder.SQL)
+}
@@ -0,0 +1,135 @@
+package dao
+
+import (
+	"context"
+	"github.com/itering/subscan-plugin/storage"
+	"github.com/itering/subscan/model"
+	pModel "github.com/itering/subscan/plugins/nominationpools/model"
+	"gorm.io/gorm"
+)
+
+func GetPool(ctx context.Context, db storage.DB, poolId uint) *pModel.Pool {
+	var pool pModel.Pool
+	d := db.GetDbInstance().(*gorm.DB)
+	if q := d.WithContext(ctx).Where("pool_id = ?", poolId).First(&pool); q.Error != nil {
+		return nil
+	}
+	return &pool
+}
+
+func GetPoolMember(ctx context.Context, db storage.DB, accountId string) *pModel.PoolMember {
+	var member pModel.PoolMember
+	d := db.GetDbInstance().(*gorm.DB)
+	if q := d.WithContext(ctx).Where("address = ?", accountId).First(&member); q.Error != nil {
+		return nil
+	}
+	return &member
+}
+
+func PoolsCursor(ctx context.Context, db storage.DB, limit int, before, after *uint, opts ...model.Option) ([]pModel.Pool, bool, bool) {
+	var list []pModel.Pool
+	d := db.GetDbInstance().(*gorm.DB)
+	fetch := limit + 1
+	q := d.WithContext(ctx).Model(pModel.Pool{}).Scopes(opts...)
+	var hasPrev, hasNext bool
+	if after != nil && *after > 0 {
+		q = q.Where("pool_id < ?", *after).Order("pool_id desc")
+	} else if before != nil && *before > 0 {
+		q = q.Where("pool_id > ?", *before).Order("pool_id asc")
+	} else {
+		q = q.Order("pool_id desc")
+	}
+	q = q.Limit(fetch).Find(&list)
+	if q.Error != nil {
+		return nil, false, false
+	}
+	if before != nil && *before > 0 {
+		hasPrev = len(list) > limit
+		if hasPrev {
+			list = list[:limit]
+		}
+		for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+			list[i], list[j] = list[j], list[i]
+		}
+		hasNext = true
+	} else {
+		hasNext = len(list) > limit
+		if hasNext {
+			list = list[:limit]
+		}
+		hasPrev = after != nil && *after > 0
+	}
+	return list, hasPrev, hasNext
+}
+
+func PoolMembersCursor(ctx context.Context, db storage.DB, limit int, before, after *uint, opts ...model.Option) ([]pModel.PoolMember, bool, bool) {
+	var list []pModel.PoolMember
+	d := db.GetDbInstance().(*gorm.DB)
+	fetch := limit + 1
+	q := d.WithContext(ctx).Model(pModel.PoolMember{}).Scopes(opts...)
+	var hasPrev, hasNext bool
+	if after != nil && *after > 0 {
+		q = q.Where("id < ?", *after).Order("id desc")
+	} else if before != nil && *before > 0 {
+		q = q.Where("id > ?", *before).Order("id asc")
+	} else {
+		q = q.Order("id desc")
+	}
+	q = q.Limit(fetch).Find(&list)
+	if q.Error != nil {
+		return nil, false, false
+	}
+	if before != nil && *before > 0 {
+		hasPrev = len(list) > limit
+		if hasPrev {
+			list = list[:limit]
+		}
+		for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+			list[i], list[j] = list[j], list[i]
+		}
+		hasNext = true
+	} else {
+		hasNext = len(list) > limit
+		if hasNext {
+			list = list[:limit]
+		}
+		hasPrev = after != nil && *after > 0
+	}
+	return list, hasPrev, hasNext
+}
+
+func MemberHistoriesCursor(ctx context.Context, db storage.DB, limit int, before, after *uint, opts ...model.Option) ([]pModel.MemberHistory, bool, bool) {
+	var list []pModel.MemberHistory
+	d := db.GetDbInstance().(*gorm.DB)
+	fetch := limit + 1
+	q := d.WithContext(ctx).Model(pModel.MemberHistory{}).Scopes(opts...)
+	var hasPrev, hasNext bool
+	if after != nil && *after > 0 {
+		q = q.Where("id < ?", *after).Order("id desc")
+	} else if before != nil && *before > 0 {
+		q = q.Where("id > ?", *before).Order("id asc")
+	} else {
+		q = q.Order("id desc")
+	}
+	q = q.Limit(fetch).Find(&list)
+	if q.Error != nil {
+		return nil, false, false
+	}
+	if before != nil && *before > 0 {
+		hasPrev = len(list) > limit
+		if hasPrev {
+			list = list[:limit]
+		}
+		for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+			list[i], list[j] = list[j], list[i]
+		}
+		hasNext = true
+	} else {
+		hasNext = len(list) > limit
+		if hasNext {
+			list = list[:limit]
+		}
+		hasPrev = after != nil && *after > 0
+	}
+	return list, hasPrev, hasNext
+}
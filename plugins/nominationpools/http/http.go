@@ -0,0 +1,201 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/itering/subscan-plugin/router"
+	_ "github.com/itering/subscan/plugins/nominationpools/model"
+	"github.com/itering/subscan/plugins/nominationpools/service"
+	"github.com/itering/subscan/util/address"
+	"github.com/itering/subscan/util/validator"
+	"github.com/pkg/errors"
+	"net/http"
+)
+
+var (
+	svc *service.Service
+)
+
+func Router(s *service.Service) []router.Http {
+	svc = s
+	return []router.Http{
+		{"pools", poolsHandle, http.MethodPost},
+		{"pool", poolHandle, http.MethodPost},
+		{"pool/members", poolMembersHandle, http.MethodPost},
+		{"member", memberHandle, http.MethodPost},
+		{"member/history", memberHistoryHandle, http.MethodPost},
+	}
+}
+
+type poolsParams struct {
+	State  string `json:"state" validate:"omitempty,oneof=Open Blocked Destroying Destroyed"`
+	Limit  int    `json:"row" validate:"min=1,max=100"`
+	Before *uint  `json:"before" validate:"omitempty,min=0"`
+	After  *uint  `json:"after" validate:"omitempty,min=0"`
+}
+
+// @Summary Get nomination pools list
+// @Tags nomination pools
+// @Accept json
+// @Produce json
+// @Param params body poolsParams true "params"
+// @Success 200 {object} J{data=object{list=[]model.Pool,pagination=object}}
+// @Router /api/plugin/nominationpools/pools [post]
+func poolsHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(poolsParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	list, page := svc.GetPoolsCursor(r.Context(), p.State, p.Limit, p.Before, p.After)
+	toJson(w, 0, map[string]interface{}{
+		"list": list, "pagination": page,
+	}, nil)
+	return nil
+}
+
+type poolParams struct {
+	PoolId uint `json:"pool_id" validate:"required,min=1"`
+	Limit  int  `json:"row" validate:"omitempty,min=1,max=100"`
+}
+
+// @Summary Get nomination pool detail with members
+// @Tags nomination pools
+// @Accept json
+// @Produce json
+// @Param params body poolParams true "params"
+// @Success 200 {object} J{data=model.PoolDetail}
+// @Router /api/plugin/nominationpools/pool [post]
+func poolHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(poolParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	if p.Limit == 0 {
+		p.Limit = 25
+	}
+	toJson(w, 0, svc.GetPoolDetail(r.Context(), p.PoolId, p.Limit), nil)
+	return nil
+}
+
+type poolMembersParams struct {
+	PoolId uint  `json:"pool_id" validate:"required,min=1"`
+	Limit  int   `json:"row" validate:"min=1,max=100"`
+	Before *uint `json:"before" validate:"omitempty,min=0"`
+	After  *uint `json:"after" validate:"omitempty,min=0"`
+}
+
+// @Summary Get nomination pool members
+// @Tags nomination pools
+// @Accept json
+// @Produce json
+// @Param params body poolMembersParams true "params"
+// @Success 200 {object} J{data=object{list=[]model.PoolMember,pagination=object}}
+// @Router /api/plugin/nominationpools/pool/members [post]
+func poolMembersHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(poolMembersParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	list, page := svc.GetPoolMembersCursor(r.Context(), p.PoolId, p.Limit, p.Before, p.After)
+	toJson(w, 0, map[string]interface{}{
+		"list": list, "pagination": page,
+	}, nil)
+	return nil
+}
+
+type memberParams struct {
+	Address string `json:"address" validate:"required,addr"`
+}
+
+// @Summary Get account current pool membership
+// @Tags nomination pools
+// @Accept json
+// @Produce json
+// @Param params body memberParams true "params"
+// @Success 200 {object} J{data=model.PoolMember}
+// @Router /api/plugin/nominationpools/member [post]
+func memberHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(memberParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	toJson(w, 0, svc.GetPoolMember(r.Context(), address.Decode(p.Address)), nil)
+	return nil
+}
+
+type memberHistoryParams struct {
+	Address string `json:"address" validate:"omitempty,addr"`
+	PoolId  uint   `json:"pool_id" validate:"omitempty,min=1"`
+	Limit   int    `json:"row" validate:"min=1,max=100"`
+	Before  *uint  `json:"before" validate:"omitempty,min=0"`
+	After   *uint  `json:"after" validate:"omitempty,min=0"`
+}
+
+// @Summary Get pool membership history
+// @Tags nomination pools
+// @Accept json
+// @Produce json
+// @Param params body memberHistoryParams true "params"
+// @Success 200 {object} J{data=object{list=[]model.MemberHistory,pagination=object}}
+// @Router /api/plugin/nominationpools/member/history [post]
+func memberHistoryHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(memberHistoryParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	list, page := svc.GetMemberHistoriesCursor(r.Context(), address.Decode(p.Address), p.PoolId, p.Limit, p.Before, p.After)
+	toJson(w, 0, map[string]interface{}{
+		"list": list, "pagination": page,
+	}, nil)
+	return nil
+}
+
+type J struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	TTL     int         `json:"ttl"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (j J) Render(w http.ResponseWriter) error {
+	header := w.Header()
+	if val := header["Content-Type"]; len(val) == 0 {
+		header["Content-Type"] = []string{"application/json; charset=utf-8"}
+	}
+	return nil
+}
+
+func (j J) WriteContentType(w http.ResponseWriter) {
+	var (
+		jsonBytes []byte
+		err       error
+	)
+	_ = j.Render(w)
+	if jsonBytes, err = json.Marshal(j); err != nil {
+		_ = errors.WithStack(err)
+		return
+	}
+	if _, err = w.Write(jsonBytes); err != nil {
+		_ = errors.WithStack(err)
+	}
+}
+
+func toJson(w http.ResponseWriter, code int, data interface{}, err error) {
+	j := J{
+		Message: "success",
+		TTL:     1,
+		Data:    data,
+	}
+	if err != nil {
+		j.Message = err.Error()
+	}
+	if code != 0 {
+		j.Code = code
+	}
+	j.WriteContentType(w)
+	_ = j.Render(w)
+}
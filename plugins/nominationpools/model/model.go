@@ -0,0 +1,96 @@
+package model
+
+import "github.com/shopspring/decimal"
+
+const (
+	ActionCreate   = "create"
+	ActionJoin     = "join"
+	ActionBond     = "bond"
+	ActionUnbond   = "unbond"
+	ActionWithdraw = "withdraw"
+	ActionPayout   = "payout"
+	ActionRemove   = "remove"
+)
+
+// Pool NominationPools.BondedPools with metadata name
+type Pool struct {
+	ID             uint            `gorm:"primary_key" json:"-"`
+	PoolId         uint            `json:"pool_id" gorm:"index:pool_id,unique"`
+	Name           string          `json:"name" gorm:"size:255"`
+	State          string          `json:"state" gorm:"size:32;index:state"`
+	Depositor      string          `json:"depositor" gorm:"size:100"`
+	Root           string          `json:"root" gorm:"size:100"`
+	Nominator      string          `json:"nominator" gorm:"size:100"`
+	Bouncer        string          `json:"bouncer" gorm:"size:100"`
+	Points         decimal.Decimal `json:"points" gorm:"type:decimal(65,0);"`
+	MemberCount    uint            `json:"member_count"`
+	CreatedBlock   uint            `json:"created_block"`
+	DestroyedBlock uint            `json:"destroyed_block"`
+	UpdatedBlock   uint            `json:"updated_block"`
+}
+
+func (p *Pool) TableName() string {
+	return "nomination_pools"
+}
+
+// PoolMember current member of pool, one account can only join one pool
+type PoolMember struct {
+	ID          uint            `gorm:"primary_key" json:"id"`
+	Address     string          `json:"address" gorm:"size:100;index:address,unique"`
+	PoolId      uint            `json:"pool_id" gorm:"index:pool_id"`
+	Points      decimal.Decimal `json:"points" gorm:"type:decimal(65,0);"`
+	Unbonding   decimal.Decimal `json:"unbonding" gorm:"type:decimal(65,0);"`
+	TotalReward decimal.Decimal `json:"total_reward" gorm:"type:decimal(65,0);"`
+	JoinedBlock uint            `json:"joined_block"`
+}
+
+func (p *PoolMember) TableName() string {
+	return "nomination_pool_members"
+}
+
+// MemberHistory pool membership change of account
+type MemberHistory struct {
+	ID             uint            `gorm:"primary_key" json:"id"`
+	EventIndex     string          `json:"event_index" gorm:"size:100;index:event_index,unique"`
+	Address        string          `json:"address" gorm:"size:100;index:address"`
+	PoolId         uint            `json:"pool_id" gorm:"index:pool_id"`
+	Action         string          `json:"action" gorm:"size:32"`
+	Amount         decimal.Decimal `json:"amount" gorm:"type:decimal(65,0);"`
+	Points         decimal.Decimal `json:"points" gorm:"type:decimal(65,0);"`
+	Era            uint            `json:"era"`
+	BlockNum       uint            `json:"block_num"`
+	BlockTimestamp int             `json:"block_timestamp"`
+	ExtrinsicIndex string          `json:"extrinsic_index" gorm:"size:100"`
+}
+
+func (m *MemberHistory) TableName() string {
+	return "nomination_pool_member_histories"
+}
+
+// BondedPoolInner NominationPools.BondedPools storage item
+type BondedPoolInner struct {
+	MemberCounter uint            `json:"member_counter"`
+	Points        decimal.Decimal `json:"points"`
+	State         string          `json:"state"`
+	Roles         struct {
+		Depositor string `json:"depositor"`
+		Root      string `json:"root"`
+		Nominator string `json:"nominator"`
+		Bouncer   string `json:"bouncer"`
+		// legacy name of bouncer
+		StateToggler string `json:"state_toggler"`
+	} `json:"roles"`
+}
+
+// PoolMemberInfo NominationPools.PoolMembers storage item
+type PoolMemberInfo struct {
+	PoolId        uint            `json:"pool_id"`
+	Points        decimal.Decimal `json:"points"`
+	UnbondingEras interface{}     `json:"unbonding_eras"`
+}
+
+// PoolDetail pool with member list
+type PoolDetail struct {
+	Pool
+	Members []PoolMember `json:"members"`
+}
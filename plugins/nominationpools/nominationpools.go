@@ -0,0 +1,92 @@
+package nominationpools
+
+import (
+	"context"
+	"github.com/itering/subscan-plugin"
+	"github.com/itering/subscan-plugin/router"
+	"github.com/itering/subscan-plugin/storage"
+	"github.com/itering/subscan/plugins/nominationpools/dao"
+	"github.com/itering/subscan/plugins/nominationpools/http"
+	"github.com/itering/subscan/plugins/nominationpools/model"
+	"github.com/itering/subscan/plugins/nominationpools/service"
+	"github.com/shopspring/decimal"
+	"github.com/urfave/cli"
+)
+
+var srv *service.Service
+
+type NominationPools struct {
+	d storage.Dao
+}
+
+func New() *NominationPools {
+	return &NominationPools{}
+}
+
+func (a *NominationPools) Commands() []cli.Command {
+	return []cli.Command{
+		{
+			Name: "InitNominationPools",
+			Action: func(c *cli.Context) error {
+				dao.InitPools(a.d)
+				return nil
+			},
+		},
+	}
+}
+
+func (a *NominationPools) ConsumptionQueue() []string {
+	return nil
+}
+
+func (a *NominationPools) Enable() bool {
+	return true
+}
+
+func (a *NominationPools) ProcessBlock(context.Context, *storage.Block) error { return nil }
+
+func (a *NominationPools) SetRedisPool(subscan_plugin.RedisPool) {}
+
+func (a *NominationPools) InitDao(d storage.Dao) {
+	srv = service.New(d)
+	a.d = d
+	a.Migrate()
+}
+
+func (a *NominationPools) InitHttp() []router.Http {
+	return http.Router(srv)
+}
+
+func (a *NominationPools) ProcessExtrinsic(block *storage.Block, extrinsic *storage.Extrinsic, _ []storage.Event) error {
+	if extrinsic == nil {
+		return nil
+	}
+	return dao.EmitExtrinsic(context.TODO(), a.d, block, extrinsic)
+}
+
+func (a *NominationPools) ProcessEvent(block *storage.Block, event *storage.Event, _ decimal.Decimal) error {
+	if event == nil {
+		return nil
+	}
+	return dao.EmitEvent(context.TODO(), a.d, event, block)
+}
+
+func (a *NominationPools) SubscribeExtrinsic() []string {
+	return []string{"nominationpools"}
+}
+
+func (a *NominationPools) SubscribeEvent() []string {
+	return []string{"nominationpools"}
+}
+
+func (a *NominationPools) Version() string {
+	return "0.1"
+}
+
+func (a *NominationPools) Migrate() {
+	_ = a.d.AutoMigration(&model.Pool{})
+	_ = a.d.AutoMigration(&model.PoolMember{})
+	_ = a.d.AutoMigration(&model.MemberHistory{})
+}
+
+func (a *NominationPools) ExecWorker(context.Context, string, string, interface{}) error { return nil }
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"github.com/itering/subscan-plugin/storage"
+	cmodel "github.com/itering/subscan/model"
+	"github.com/itering/subscan/plugins/nominationpools/dao"
+	"github.com/itering/subscan/plugins/nominationpools/model"
+	"github.com/itering/subscan/util/address"
+)
+
+type Service struct {
+	d storage.Dao
+}
+
+func New(d storage.Dao) *Service {
+	return &Service{d: d}
+}
+
+func encodePool(pool *model.Pool) {
+	pool.Depositor = address.Encode(pool.Depositor)
+	pool.Root = address.Encode(pool.Root)
+	pool.Nominator = address.Encode(pool.Nominator)
+	pool.Bouncer = address.Encode(pool.Bouncer)
+}
+
+func (s *Service) GetPoolsCursor(ctx context.Context, state string, limit int, before, after *uint) ([]model.Pool, map[string]interface{}) {
+	var opts []cmodel.Option
+	if state != "" {
+		opts = append(opts, cmodel.Where("state = ?", state))
+	}
+	list, hasPrev, hasNext := dao.PoolsCursor(ctx, s.d, limit, before, after, opts...)
+	for i := range list {
+		encodePool(&list[i])
+	}
+	var start, end *uint
+	if len(list) > 0 {
+		start = &list[0].PoolId
+		end = &list[len(list)-1].PoolId
+	}
+	return list, map[string]interface{}{
+		"start_cursor":      start,
+		"end_cursor":        end,
+		"has_previous_page": hasPrev,
+		"has_next_page":     hasNext,
+	}
+}
+
+// GetPoolDetail pool with the first page of members
+func (s *Service) GetPoolDetail(ctx context.Context, poolId uint, limit int) *model.PoolDetail {
+	pool := dao.GetPool(ctx, s.d, poolId)
+	if pool == nil {
+		return nil
+	}
+	encodePool(pool)
+	detail := model.PoolDetail{Pool: *pool}
+	detail.Members, _ = s.GetPoolMembersCursor(ctx, poolId, limit, nil, nil)
+	return &detail
+}
+
+func (s *Service) GetPoolMembersCursor(ctx context.Context, poolId uint, limit int, before, after *uint) ([]model.PoolMember, map[string]interface{}) {
+	list, hasPrev, hasNext := dao.PoolMembersCursor(ctx, s.d, limit, before, after, cmodel.Where("pool_id = ?", poolId))
+	for i := range list {
+		list[i].Address = address.Encode(list[i].Address)
+	}
+	var start, end *uint
+	if len(list) > 0 {
+		start = &list[0].ID
+		end = &list[len(list)-1].ID
+	}
+	return list, map[string]interface{}{
+		"start_cursor":      start,
+		"end_cursor":        end,
+		"has_previous_page": hasPrev,
+		"has_next_page":     hasNext,
+	}
+}
+
+func (s *Service) GetPoolMember(ctx context.Context, addr string) *model.PoolMember {
+	member := dao.GetPoolMember(ctx, s.d, addr)
+	if member == nil {
+		return nil
+	}
+	member.Address = address.Encode(member.Address)
+	return member
+}
+
+func (s *Service) GetMemberHistoriesCursor(ctx context.Context, addr string, poolId uint, limit int, before, after *uint) ([]model.MemberHistory, map[string]interface{}) {
+	var opts []cmodel.Option
+	if addr != "" {
+		opts = append(opts, cmodel.Where("address = ?", addr))
+	}
+	if poolId > 0 {
+		opts = append(opts, cmodel.Where("pool_id = ?", poolId))
+	}
+	list, hasPrev, hasNext := dao.MemberHistoriesCursor(ctx, s.d, limit, before, after, opts...)
+	for i := range list {
+		list[i].Address = address.Encode(list[i].Address)
+	}
+	var start, end *uint
+	if len(list) > 0 {
+		start = &list[0].ID
+		end = &list[len(list)-1].ID
+	}
+	return list, map[string]interface{}{
+		"start_cursor":      start,
+		"end_cursor":        end,
+		"has_previous_page": hasPrev,
+		"has_next_page":     hasNext,
+	}
+}
@@ -0,0 +1,225 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/itering/subscan-plugin/storage"
+	"github.com/itering/subscan/model"
+	pModel "github.com/itering/subscan/plugins/proxy/model"
+	"github.com/itering/subscan/share/substrate"
+	"github.com/itering/subscan/util"
+	"github.com/itering/subscan/util/address"
+	"github.com/itering/substrate-api-rpc/rpc"
+	substrateStorage "github.com/itering/substrate-api-rpc/storage"
+	"gorm.io/gorm"
+	"log"
+	"strings"
+)
+
+func EmitEvent(ctx context.Context, d storage.Dao, event *storage.Event, block *storage.Block) error {
+	var paramEvent []storage.EventParam
+	_ = util.UnmarshalAny(&paramEvent, event.Params)
+	switch event.EventId {
+	// [delegator, delegatee, proxy_type, delay]
+	case "ProxyAdded", "ProxyRemoved":
+		if len(paramEvent) < 1 {
+			return nil
+		}
+		return RefreshDelegations(ctx, d, model.CheckoutParamValueAddress(paramEvent[0].Value), uint(block.BlockNum))
+	// [pure, who, proxy_type, disambiguation_index]
+	case "PureCreated", "AnonymousCreated":
+		if len(paramEvent) < 3 {
+			return nil
+		}
+		pure := model.CheckoutParamValueAddress(paramEvent[0].Value)
+		pureProxy := pModel.PureProxy{
+			Pure:           pure,
+			Spawner:        model.CheckoutParamValueAddress(paramEvent[1].Value),
+			ProxyType:      proxyTypeToString(paramEvent[2].Value),
+			BlockNum:       uint(block.BlockNum),
+			ExtrinsicIndex: fmt.Sprintf("%d-%d", event.BlockNum, event.ExtrinsicIdx),
+		}
+		if len(paramEvent) > 3 {
+			pureProxy.DisambiguationIndex = util.UIntFromInterface(paramEvent[3].Value)
+		}
+		db := d.GetDbInstance().(*gorm.DB)
+		if err := db.WithContext(ctx).Scopes(model.IgnoreDuplicate).Create(&pureProxy).Error; err != nil {
+			return err
+		}
+		return RefreshDelegations(ctx, d, pure, uint(block.BlockNum))
+	// [pure, spawner, proxy_type, disambiguation_index]
+	case "PureKilled":
+		if len(paramEvent) < 1 {
+			return nil
+		}
+		pure := model.CheckoutParamValueAddress(paramEvent[0].Value)
+		if err := killPure(ctx, d, pure, uint(block.BlockNum)); err != nil {
+			return err
+		}
+		return RefreshDelegations(ctx, d, pure, uint(block.BlockNum))
+	// [real, proxy, call_hash]
+	case "Announced":
+		if len(paramEvent) < 3 {
+			return nil
+		}
+		db := d.GetDbInstance().(*gorm.DB)
+		return db.WithContext(ctx).Scopes(model.IgnoreDuplicate).Create(&pModel.Announcement{
+			EventIndex:     fmt.Sprintf("%d-%d", event.BlockNum, event.EventIdx),
+			Real:           model.CheckoutParamValueAddress(paramEvent[0].Value),
+			Delegate:       model.CheckoutParamValueAddress(paramEvent[1].Value),
+			CallHash:       util.AddHex(util.ToString(paramEvent[2].Value)),
+			BlockNum:       uint(block.BlockNum),
+			BlockTimestamp: block.BlockTimestamp,
+			ExtrinsicIndex: fmt.Sprintf("%d-%d", event.BlockNum, event.ExtrinsicIdx),
+		}).Error
+	}
+	return nil
+}
+
+func EmitExtrinsic(ctx context.Context, d storage.Dao, block *storage.Block, extrinsic *storage.Extrinsic, events []storage.Event) error {
+	var params []model.ExtrinsicParam
+	_ = util.UnmarshalAny(&params, extrinsic.Params)
+	signer := address.Format(extrinsic.AccountId)
+	switch strings.ToLower(extrinsic.CallModuleFunction) {
+	// remove_proxies will not emit ProxyRemoved event
+	case "remove_proxies":
+		if extrinsic.Success {
+			return RefreshDelegations(ctx, d, signer, uint(block.BlockNum))
+		}
+		return nil
+	case "proxy", "proxy_announced":
+	default:
+		return nil
+	}
+	proxy := model.CheckoutExtrinsicProxy(extrinsic.CallModule, extrinsic.CallModuleFunction, params)
+	if proxy == nil {
+		return nil
+	}
+	success := extrinsic.Success
+	for _, event := range events {
+		if strings.EqualFold(event.ModuleId, "proxy") && event.EventId == "ProxyExecuted" {
+			var paramEvent []storage.EventParam
+			_ = util.UnmarshalAny(&paramEvent, event.Params)
+			if len(paramEvent) > 0 && dispatchFailed(paramEvent[0].Value) {
+				success = false
+			}
+		}
+	}
+	db := d.GetDbInstance().(*gorm.DB)
+	if err := db.WithContext(ctx).Scopes(model.IgnoreDuplicate).Create(&pModel.ProxyCall{
+		ExtrinsicIndex:     extrinsic.ExtrinsicIndex,
+		BlockNum:           uint(block.BlockNum),
+		BlockTimestamp:     block.BlockTimestamp,
+		Delegate:           signer,
+		Real:               proxy.Real,
+		ForceProxyType:     proxy.ForceProxyType,
+		CallModule:         proxy.CallModule,
+		CallModuleFunction: proxy.CallModuleFunction,
+		Success:            success,
+	}).Error; err != nil {
+		return err
+	}
+	// real account remove all proxies or kill pure itself through proxy
+	if success && proxy.CallModule == "proxy" {
+		switch proxy.CallModuleFunction {
+		case "remove_proxies":
+			return RefreshDelegations(ctx, d, proxy.Real, uint(block.BlockNum))
+		case "kill_pure", "kill_anonymous":
+			if err := killPure(ctx, d, proxy.Real, uint(block.BlockNum)); err != nil {
+				return err
+			}
+			return RefreshDelegations(ctx, d, proxy.Real, uint(block.BlockNum))
+		}
+	}
+	return nil
+}
+
+// dispatchFailed DispatchResult is {"Err": DispatchError}
+func dispatchFailed(result interface{}) bool {
+	if r, ok := result.(map[string]interface{}); ok {
+		_, failed := r["Err"]
+		return failed
+	}
+	return false
+}
+
+func proxyTypeToString(value interface{}) string {
+	if v, ok := value.(map[string]interface{}); ok && len(v) == 1 {
+		for key := range v {
+			return key
+		}
+	}
+	return util.ToString(value)
+}
+
+func killPure(ctx context.Context, d storage.Dao, pure string, blockNum uint) error {
+	db := d.GetDbInstance().(*gorm.DB)
+	return db.WithContext(ctx).Model(&pModel.PureProxy{}).Where("pure = ?", pure).
+		Updates(map[string]interface{}{"killed": true, "killed_block_num": blockNum}).Error
+}
+
+// RefreshDelegations sync Proxy.Proxies of delegator
+func RefreshDelegations(ctx context.Context, d storage.Dao, delegator string, blockNum uint) error {
+	delegator = address.Format(delegator)
+	if delegator == "" {
+		return nil
+	}
+	raw, err := rpc.ReadStorage(nil, "Proxy", "Proxies", "", delegator)
+	if err != nil {
+		return err
+	}
+	return saveDelegations(ctx, d, delegator, blockNum, parseProxies(raw))
+}
+
+// parseProxies (BoundedVec<ProxyDefinition>, Balance)
+func parseProxies(raw substrateStorage.StateStorage) []pModel.ProxyDefinition {
+	var (
+		tuple       []json.RawMessage
+		definitions []pModel.ProxyDefinition
+	)
+	raw.ToAny(&tuple)
+	if len(tuple) > 0 {
+		_ = json.Unmarshal(tuple[0], &definitions)
+	}
+	return definitions
+}
+
+func saveDelegations(ctx context.Context, d storage.Dao, delegator string, blockNum uint, definitions []pModel.ProxyDefinition) error {
+	db := d.GetDbInstance().(*gorm.DB)
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("delegator = ?", delegator).Delete(&pModel.Delegation{}).Error; err != nil {
+			return err
+		}
+		var delegations []pModel.Delegation
+		for _, definition := range definitions {
+			delegations = append(delegations, pModel.Delegation{
+				Delegator: delegator,
+				Delegate:  model.CheckoutParamValueAddress(definition.Delegate),
+				ProxyType: proxyTypeToString(definition.ProxyType),
+				Delay:     definition.Delay,
+				BlockNum:  blockNum,
+			})
+		}
+		if len(delegations) == 0 {
+			return nil
+		}
+		return tx.Scopes(model.IgnoreDuplicate).Create(&delegations).Error
+	})
+}
+
+func InitDelegations(d storage.Dao) {
+	ctx := context.Background()
+	blockNum, _ := d.GetCurrentBlockNum(ctx)
+	if err := substrate.BatchReadKeysPaged(ctx, "Proxy", "Proxies", "", func(keys []string, scaleType string) error {
+		r, _ := substrate.BatchStorageByKey(ctx, keys, scaleType, "")
+		for key, v := range r {
+			val, _ := substrate.ParseStorageKey(key)
+			delegator := address.Format(val[0].ToString())
+			util.Logger().Error(saveDelegations(ctx, d, delegator, uint(blockNum), parseProxies(v)))
+		}
+		return nil
+	}); err != nil {
+		log.Panic(err)
+	}
+}
@@ -0,0 +1,104 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/itering/subscan-plugin/storage"
+	"github.com/itering/subscan/model"
+	"github.com/itering/subscan/plugins/internal/daotest"
+	pModel "github.com/itering/subscan/plugins/proxy/model"
+	substrateStorage "github.com/itering/substrate-api-rpc/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_dispatchFailed(t *testing.T) {
+	assert.True(t, dispatchFailed(map[string]interface{}{"Err": map[string]interface{}{"BadOrigin": nil}}))
+	assert.False(t, dispatchFailed(map[string]interface{}{"Ok": nil}))
+	assert.False(t, dispatchFailed("Ok"))
+	assert.False(t, dispatchFailed(nil))
+}
+
+func Test_proxyTypeToString(t *testing.T) {
+	assert.Equal(t, "Staking", proxyTypeToString("Staking"))
+	assert.Equal(t, "Any", proxyTypeToString(map[string]interface{}{"Any": nil}))
+	assert.Equal(t, "1", proxyTypeToString(1))
+}
+
+func Test_parseProxies(t *testing.T) {
+	raw := substrateStorage.StateStorage(`[[{"delegate":"0x8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48","proxy_type":"Staking","delay":0},{"delegate":"0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d","proxy_type":"Any","delay":10}],"2000000000"]`)
+	definitions := parseProxies(raw)
+	assert.Equal(t, []pModel.ProxyDefinition{
+		{Delegate: "0x8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48", ProxyType: "Staking", Delay: 0},
+		{Delegate: "0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d", ProxyType: "Any", Delay: 10},
+	}, definitions)
+
+	// account without proxies
+	assert.Empty(t, parseProxies(substrateStorage.StateStorage(`[[],"0"]`)))
+	assert.Empty(t, parseProxies(substrateStorage.StateStorage("")))
+}
+
+func TestEmitExtrinsicIgnored(t *testing.T) {
+	ctx := context.TODO()
+	block := &storage.Block{BlockNum: 10}
+	// not proxy call, dao not touched
+	assert.NoError(t, EmitExtrinsic(ctx, nil, block, &storage.Extrinsic{CallModule: "balances", CallModuleFunction: "transfer", Success: true}, nil))
+	// failed remove_proxies
+	assert.NoError(t, EmitExtrinsic(ctx, nil, block, &storage.Extrinsic{CallModule: "proxy", CallModuleFunction: "remove_proxies"}, nil))
+	// proxy call without real account
+	assert.NoError(t, EmitExtrinsic(ctx, nil, block, &storage.Extrinsic{CallModule: "proxy", CallModuleFunction: "proxy", Params: []byte(`[{"name":"call","value":{"call_module":"Balances","call_name":"transfer"}}]`)}, nil))
+}
+
+func TestEmitEventIgnored(t *testing.T) {
+	assert.NoError(t, EmitEvent(context.TODO(), nil, &storage.Event{ModuleId: "proxy", EventId: "ProxyExecuted"}, &storage.Block{BlockNum: 10}))
+	// short params of malformed event
+	d, recorder := daotest.New(t)
+	for _, eventId := range []string{"ProxyAdded", "ProxyRemoved", "PureCreated", "AnonymousCreated", "PureKilled", "Announced"} {
+		assert.NoError(t, EmitEvent(context.TODO(), d, &storage.Event{ModuleId: "proxy", EventId: eventId}, &storage.Block{BlockNum: 10}))
+	}
+	assert.NoError(t, EmitEvent(context.TODO(), d, &storage.Event{ModuleId: "proxy", EventId: "Announced",
+		Params: []byte(`[{"type":"AccountId","value":"` + daotest.Alice + `"}]`)}, &storage.Block{BlockNum: 10}))
+	assert.Empty(t, recorder.SQL)
+}
+
+func TestEmitEventAnnounced(t *testing.T) {
+	d, recorder := daotest.New(t)
+	event := &storage.Event{BlockNum: 100, EventIdx: 2, ExtrinsicIdx: 1, ModuleId: "proxy", EventId: "Announced",
+		Params: []byte(`[{"type":"AccountId","value":"` + daotest.Alice + `"},{"type":"AccountId","value":"` + daotest.Bob + `"},{"type":"Hash","value":"0102"}]`)}
+	assert.NoError(t, EmitEvent(context.TODO(), d, event, &storage.Block{BlockNum: 100, BlockTimestamp: 1700000000}))
+	assert.Equal(t, []string{
+		"INSERT INTO `proxy_announcements` (`event_index`,`real_account`,`delegate`,`call_hash`,`block_num`,`block_timestamp`,`extrinsic_index`) " +
+			"VALUES ('100-2','" + daotest.Alice + "','" + daotest.Bob + "','0x0102',100,1700000000,'100-1') ON DUPLICATE KEY UPDATE `id`=`id`",
+	}, recorder.SQL)
+}
+
+func Test_killPure(t *testing.T) {
+	d, recorder := daotest.New(t)
+	assert.NoError(t, killPure(context.TODO(), d, daotest.Alice, 100))
+	assert.Equal(t, []string{"UPDATE `proxy_pures` SET `killed`=true,`killed_block_num`=100 WHERE pure = '" + daotest.Alice + "'"}, recorder.SQL)
+}
+
+func TestQuery(t *testing.T) {
+	ctx := context.TODO()
+	d, recorder := daotest.New(t)
+	_ = GetDelegations(ctx, d, model.Where("delegate = ?", daotest.Bob))
+	_ = GetPureProxy(ctx, d, daotest.Alice)
+	after := uint(20)
+	list, hasPrev, hasNext := PureProxiesCursor(ctx, d, 10, nil, &after, model.Where("spawner = ?", daotest.Alice))
+	assert.Empty(t, list)
+	assert.True(t, hasPrev)
+	assert.False(t, hasNext)
+	before := uint(5)
+	_, hasPrev, hasNext = ProxyCallsCursor(ctx, d, 10, &before, nil)
+	assert.False(t, hasPrev)
+	assert.True(t, hasNext)
+	_, _, _ = AnnouncementsCursor(ctx, d, 10, nil, nil, model.Where("real_account = ?", daotest.Alice))
+
+	assert.Equal(t, []string{
+		"SELECT * FROM `proxy_delegations` WHERE delegate = '" + daotest.Bob + "' ORDER BY id asc",
+		"SELECT * FROM `proxy_pures` WHERE pure = '" + daotest.Alice + "' ORDER BY `proxy_pures`.`id` LIMIT 1",
+		"SELECT * FROM `proxy_pures` WHERE id < 20 AND spawner = '" + daotest.Alice + "' ORDER BY id desc LIMIT 11",
+		"SELECT * FROM `proxy_calls` WHERE id > 5 ORDER BY id asc LIMIT 11",
+		"SELECT * FROM `proxy_announcements` WHERE real_account = '" + daotest.Alice + "' ORDER BY id desc LIMIT 11",
+	}, recorder.SQL)
+}
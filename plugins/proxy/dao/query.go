@@ -0,0 +1,132 @@
+package dao
+
+import (
+	"context"
+	"github.com/itering/subscan-plugin/storage"
+	"github.com/itering/subscan/model"
+	pModel "github.com/itering/subscan/plugins/proxy/model"
+	"gorm.io/gorm"
+)
+
+func GetDelegations(ctx context.Context, db storage.DB, opts ...model.Option) (list []pModel.Delegation) {
+	d := db.GetDbInstance().(*gorm.DB)
+	d.WithContext(ctx).Scopes(opts...).Order("id asc").Find(&list)
+	return
+}
+
+func GetPureProxy(ctx context.Context, db storage.DB, pure string) *pModel.PureProxy {
+	var p pModel.PureProxy
+	d := db.GetDbInstance().(*gorm.DB)
+	if q := d.WithContext(ctx).Where("pure = ?", pure).First(&p); q.Error != nil {
+		return nil
+	}
+	return &p
+}
+
+func PureProxiesCursor(ctx context.Context, db storage.DB, limit int, before, after *uint, opts ...model.Option) ([]pModel.PureProxy, bool, bool) {
+	var list []pModel.PureProxy
+	d := db.GetDbInstance().(*gorm.DB)
+	fetch := limit + 1
+	q := d.WithContext(ctx).Model(pModel.PureProxy{}).Scopes(opts...)
+	var hasPrev, hasNext bool
+	if after != nil && *after > 0 {
+		q = q.Where("id < ?", *after).Order("id desc")
+	} else if before != nil && *before > 0 {
+		q = q.Where("id > ?", *before).Order("id asc")
+	} else {
+		q = q.Order("id desc")
+	}
+	q = q.Limit(fetch).Find(&list)
+	if q.Error != nil {
+		return nil, false, false
+	}
+	if before != nil && *before > 0 {
+		hasPrev = len(list) > limit
+		if hasPrev {
+			list = list[:limit]
+		}
+		for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+			list[i], list[j] = list[j], list[i]
+		}
+		hasNext = true
+	} else {
+		hasNext = len(list) > limit
+		if hasNext {
+			list = list[:limit]
+		}
+		hasPrev = after != nil && *after > 0
+	}
+	return list, hasPrev, hasNext
+}
+
+func ProxyCallsCursor(ctx context.Context, db storage.DB, limit int, before, after *uint, opts ...model.Option) ([]pModel.ProxyCall, bool, bool) {
+	var list []pModel.ProxyCall
+	d := db.GetDbInstance().(*gorm.DB)
+	fetch := limit + 1
+	q := d.WithContext(ctx).Model(pModel.ProxyCall{}).Scopes(opts...)
+	var hasPrev, hasNext bool
+	if after != nil && *after > 0 {
+		q = q.Where("id < ?", *after).Order("id desc")
+	} else if before != nil && *before > 0 {
+		q = q.Where("id > ?", *before).Order("id asc")
+	} else {
+		q = q.Order("id desc")
+	}
+	q = q.Limit(fetch).Find(&list)
+	if q.Error != nil {
+		return nil, false, false
+	}
+	if before != nil && *before > 0 {
+		hasPrev = len(list) > limit
+		if hasPrev {
+			list = list[:limit]
+		}
+		for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+			list[i], list[j] = list[j], list[i]
+		}
+		hasNext = true
+	} else {
+		hasNext = len(list) > limit
+		if hasNext {
+			list = list[:limit]
+		}
+		hasPrev = after != nil && *after > 0
+	}
+	return list, hasPrev, hasNext
+}
+
+func AnnouncementsCursor(ctx context.Context, db storage.DB, limit int, before, after *uint, opts ...model.Option) ([]pModel.Announcement, bool, bool) {
+	var list []pModel.Announcement
+	d := db.GetDbInstance().(*gorm.DB)
+	fetch := limit + 1
+	q := d.WithContext(ctx).Model(pModel.Announcement{}).Scopes(opts...)
+	var hasPrev, hasNext bool
+	if after != nil && *after > 0 {
+		q = q.Where("id < ?", *after).Order("id desc")
+	} else if before != nil && *before > 0 {
+		q = q.Where("id > ?", *before).Order("id asc")
+	} else {
+		q = q.Order("id desc")
+	}
+	q = q.Limit(fetch).Find(&list)
+	if q.Error != nil {
+		return nil, false, false
+	}
+	if before != nil && *before > 0 {
+		hasPrev = len(list) > limit
+		if hasPrev {
+			list = list[:limit]
+		}
+		for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+			list[i], list[j] = list[j], list[i]
+		}
+		hasNext = true
+	} else {
+		hasNext = len(list) > limit
+		if hasNext {
+			list = list[:limit]
+		}
+		hasPrev = after != nil && *after > 0
+	}
+	return list, hasPrev, hasNext
+}
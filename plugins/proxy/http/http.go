@@ -0,0 +1,167 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/itering/subscan-plugin/router"
+	_ "github.com/itering/subscan/plugins/proxy/model"
+	"github.com/itering/subscan/plugins/proxy/service"
+	"github.com/itering/subscan/util/address"
+	"github.com/itering/subscan/util/validator"
+	"github.com/pkg/errors"
+	"net/http"
+)
+
+var (
+	svc *service.Service
+)
+
+func Router(s *service.Service) []router.Http {
+	svc = s
+	return []router.Http{
+		{"account", accountHandle, http.MethodPost},
+		{"pures", puresHandle, http.MethodPost},
+		{"calls", callsHandle, http.MethodPost},
+		{"announcements", announcementsHandle, http.MethodPost},
+	}
+}
+
+type accountParams struct {
+	Address string `json:"address" validate:"required,addr"`
+}
+
+// @Summary Get account proxies, proxied accounts and pure proxies
+// @Tags proxy
+// @Accept json
+// @Produce json
+// @Param params body accountParams true "params"
+// @Success 200 {object} J{data=model.AccountProxy}
+// @Router /api/plugin/proxy/account [post]
+func accountHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(accountParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	toJson(w, 0, svc.GetAccountProxy(r.Context(), address.Decode(p.Address)), nil)
+	return nil
+}
+
+type puresParams struct {
+	Spawner string `json:"spawner" validate:"omitempty,addr"`
+	Limit   int    `json:"row" validate:"min=1,max=100"`
+	Before  *uint  `json:"before" validate:"omitempty,min=0"`
+	After   *uint  `json:"after" validate:"omitempty,min=0"`
+}
+
+// @Summary Get pure proxies list
+// @Tags proxy
+// @Accept json
+// @Produce json
+// @Param params body puresParams true "params"
+// @Success 200 {object} J{data=object{list=[]model.PureProxy,pagination=object}}
+// @Router /api/plugin/proxy/pures [post]
+func puresHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(puresParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	list, page := svc.GetPureProxiesCursor(r.Context(), address.Decode(p.Spawner), p.Limit, p.Before, p.After)
+	toJson(w, 0, map[string]interface{}{
+		"list": list, "pagination": page,
+	}, nil)
+	return nil
+}
+
+type listParams struct {
+	Address string `json:"address" validate:"omitempty,addr"`
+	Limit   int    `json:"row" validate:"min=1,max=100"`
+	Before  *uint  `json:"before" validate:"omitempty,min=0"`
+	After   *uint  `json:"after" validate:"omitempty,min=0"`
+}
+
+// @Summary Get calls executed through proxy
+// @Tags proxy
+// @Accept json
+// @Produce json
+// @Param params body listParams true "params"
+// @Success 200 {object} J{data=object{list=[]model.ProxyCall,pagination=object}}
+// @Router /api/plugin/proxy/calls [post]
+func callsHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(listParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	list, page := svc.GetProxyCallsCursor(r.Context(), address.Decode(p.Address), p.Limit, p.Before, p.After)
+	toJson(w, 0, map[string]interface{}{
+		"list": list, "pagination": page,
+	}, nil)
+	return nil
+}
+
+// @Summary Get proxy announcements
+// @Tags proxy
+// @Accept json
+// @Produce json
+// @Param params body listParams true "params"
+// @Success 200 {object} J{data=object{list=[]model.Announcement,pagination=object}}
+// @Router /api/plugin/proxy/announcements [post]
+func announcementsHandle(w http.ResponseWriter, r *http.Request) error {
+	p := new(listParams)
+	if err := validator.Validate(r.Body, p); err != nil {
+		toJson(w, 10001, nil, err)
+		return nil
+	}
+	list, page := svc.GetAnnouncementsCursor(r.Context(), address.Decode(p.Address), p.Limit, p.Before, p.After)
+	toJson(w, 0, map[string]interface{}{
+		"list": list, "pagination": page,
+	}, nil)
+	return nil
+}
+
+type J struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	TTL     int         `json:"ttl"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (j J) Render(w http.ResponseWriter) error {
+	header := w.Header()
+	if val := header["Content-Type"]; len(val) == 0 {
+		header["Content-Type"] = []string{"application/json; charset=utf-8"}
+	}
+	return nil
+}
+
+func (j J) WriteContentType(w http.ResponseWriter) {
+	var (
+		jsonBytes []byte
+		err       error
+	)
+	_ = j.Render(w)
+	if jsonBytes, err = json.Marshal(j); err != nil {
+		_ = errors.WithStack(err)
+		return
+	}
+	if _, err = w.Write(jsonBytes); err != nil {
+		_ = errors.WithStack(err)
+	}
+}
+
+func toJson(w http.ResponseWriter, code int, data interface{}, err error) {
+	j := J{
+		Message: "success",
+		TTL:     1,
+		Data:    data,
+	}
+	if err != nil {
+		j.Message = err.Error()
+	}
+	if code != 0 {
+		j.Code = code
+	}
+	j.WriteContentType(w)
+	_ = j.Render(w)
+}
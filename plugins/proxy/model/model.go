@@ -0,0 +1,81 @@
+package model
+
+// Delegation proxy definition of delegator, Proxy.Proxies
+type Delegation struct {
+	ID        uint   `gorm:"primary_key" json:"-"`
+	Delegator string `json:"delegator" gorm:"size:100;index:delegation,unique,priority:1"`
+	Delegate  string `json:"delegate" gorm:"size:100;index:delegation,unique,priority:2;index:delegate"`
+	ProxyType string `json:"proxy_type" gorm:"size:100;index:delegation,unique,priority:3"`
+	Delay     uint   `json:"delay" gorm:"index:delegation,unique,priority:4"`
+	BlockNum  uint   `json:"block_num"`
+}
+
+func (d *Delegation) TableName() string {
+	return "proxy_delegations"
+}
+
+// ProxyDefinition Proxy.Proxies storage item
+type ProxyDefinition struct {
+	Delegate  interface{} `json:"delegate"`
+	ProxyType interface{} `json:"proxy_type"`
+	Delay     uint        `json:"delay"`
+}
+
+// PureProxy keyless account created by proxy.create_pure
+type PureProxy struct {
+	ID                  uint   `gorm:"primary_key" json:"id"`
+	Pure                string `json:"pure" gorm:"size:100;index:pure,unique"`
+	Spawner             string `json:"spawner" gorm:"size:100;index:spawner"`
+	ProxyType           string `json:"proxy_type" gorm:"size:100"`
+	DisambiguationIndex uint   `json:"disambiguation_index"`
+	BlockNum            uint   `json:"block_num"`
+	ExtrinsicIndex      string `json:"extrinsic_index" gorm:"size:100"`
+	Killed              bool   `json:"killed"`
+	KilledBlockNum      uint   `json:"killed_block_num"`
+}
+
+func (p *PureProxy) TableName() string {
+	return "proxy_pures"
+}
+
+// Announcement call hash announced by delegate, will be executed by proxy_announced
+type Announcement struct {
+	ID             uint   `gorm:"primary_key" json:"id"`
+	EventIndex     string `json:"event_index" gorm:"size:100;index:event_index,unique"`
+	Real           string `json:"real" gorm:"column:real_account;size:100;index:real_account"`
+	Delegate       string `json:"delegate" gorm:"size:100;index:delegate"`
+	CallHash       string `json:"call_hash" gorm:"size:100"`
+	BlockNum       uint   `json:"block_num"`
+	BlockTimestamp int    `json:"block_timestamp"`
+	ExtrinsicIndex string `json:"extrinsic_index" gorm:"size:100"`
+}
+
+func (a *Announcement) TableName() string {
+	return "proxy_announcements"
+}
+
+// ProxyCall call executed through proxy.proxy or proxy.proxy_announced
+type ProxyCall struct {
+	ID                 uint   `gorm:"primary_key" json:"id"`
+	ExtrinsicIndex     string `json:"extrinsic_index" gorm:"size:100;index:extrinsic_index,unique"`
+	BlockNum           uint   `json:"block_num"`
+	BlockTimestamp     int    `json:"block_timestamp"`
+	Delegate           string `json:"delegate" gorm:"size:100;index:delegate"`
+	Real               string `json:"real" gorm:"column:real_account;size:100;index:real_account"`
+	ForceProxyType     string `json:"force_proxy_type" gorm:"size:100"`
+	CallModule         string `json:"call_module" gorm:"size:100"`
+	CallModuleFunction string `json:"call_module_function" gorm:"size:100"`
+	Success            bool   `json:"success"`
+}
+
+func (p *ProxyCall) TableName() string {
+	return "proxy_calls"
+}
+
+// AccountProxy proxy relationship of account
+type AccountProxy struct {
+	Proxies []Delegation `json:"proxies"`
+	Proxied []Delegation `json:"proxied"`
+	Pure    *PureProxy   `json:"pure"`
+	Spawned []PureProxy  `json:"spawned"`
+}
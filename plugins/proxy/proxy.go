@@ -0,0 +1,93 @@
+package proxy
+
+import (
+	"context"
+	"github.com/itering/subscan-plugin"
+	"github.com/itering/subscan-plugin/router"
+	"github.com/itering/subscan-plugin/storage"
+	"github.com/itering/subscan/plugins/proxy/dao"
+	"github.com/itering/subscan/plugins/proxy/http"
+	"github.com/itering/subscan/plugins/proxy/model"
+	"github.com/itering/subscan/plugins/proxy/service"
+	"github.com/shopspring/decimal"
+	"github.com/urfave/cli"
+)
+
+var srv *service.Service
+
+type Proxy struct {
+	d storage.Dao
+}
+
+func New() *Proxy {
+	return &Proxy{}
+}
+
+func (a *Proxy) Commands() []cli.Command {
+	return []cli.Command{
+		{
+			Name: "InitProxy",
+			Action: func(c *cli.Context) error {
+				dao.InitDelegations(a.d)
+				return nil
+			},
+		},
+	}
+}
+
+func (a *Proxy) ConsumptionQueue() []string {
+	return nil
+}
+
+func (a *Proxy) Enable() bool {
+	return true
+}
+
+func (a *Proxy) ProcessBlock(context.Context, *storage.Block) error { return nil }
+
+func (a *Proxy) SetRedisPool(subscan_plugin.RedisPool) {}
+
+func (a *Proxy) InitDao(d storage.Dao) {
+	srv = service.New(d)
+	a.d = d
+	a.Migrate()
+}
+
+func (a *Proxy) InitHttp() []router.Http {
+	return http.Router(srv)
+}
+
+func (a *Proxy) ProcessExtrinsic(block *storage.Block, extrinsic *storage.Extrinsic, events []storage.Event) error {
+	if extrinsic == nil {
+		return nil
+	}
+	return dao.EmitExtrinsic(context.TODO(), a.d, block, extrinsic, events)
+}
+
+func (a *Proxy) ProcessEvent(block *storage.Block, event *storage.Event, _ decimal.Decimal) error {
+	if event == nil {
+		return nil
+	}
+	return dao.EmitEvent(context.TODO(), a.d, event, block)
+}
+
+func (a *Proxy) SubscribeExtrinsic() []string {
+	return []string{"proxy"}
+}
+
+func (a *Proxy) SubscribeEvent() []string {
+	return []string{"proxy"}
+}
+
+func (a *Proxy) Version() string {
+	return "0.1"
+}
+
+func (a *Proxy) Migrate() {
+	_ = a.d.AutoMigration(&model.Delegation{})
+	_ = a.d.AutoMigration(&model.PureProxy{})
+	_ = a.d.AutoMigration(&model.Announcement{})
+	_ = a.d.AutoMigration(&model.ProxyCall{})
+}
+
+func (a *Proxy) ExecWorker(context.Context, string, string, interface{}) error { return nil }
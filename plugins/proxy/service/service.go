@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"github.com/itering/subscan-plugin/storage"
+	cmodel "github.com/itering/subscan/model"
+	"github.com/itering/subscan/plugins/proxy/dao"
+	"github.com/itering/subscan/plugins/proxy/model"
+	"github.com/itering/subscan/util/address"
+)
+
+type Service struct {
+	d storage.Dao
+}
+
+func New(d storage.Dao) *Service {
+	return &Service{d: d}
+}
+
+func encodeDelegations(list []model.Delegation) []model.Delegation {
+	for i := range list {
+		list[i].Delegator = address.Encode(list[i].Delegator)
+		list[i].Delegate = address.Encode(list[i].Delegate)
+	}
+	return list
+}
+
+func encodePure(p *model.PureProxy) {
+	p.Pure = address.Encode(p.Pure)
+	p.Spawner = address.Encode(p.Spawner)
+}
+
+func (s *Service) GetAccountProxy(ctx context.Context, addr string) *model.AccountProxy {
+	account := model.AccountProxy{
+		Proxies: encodeDelegations(dao.GetDelegations(ctx, s.d, cmodel.Where("delegator = ?", addr))),
+		Proxied: encodeDelegations(dao.GetDelegations(ctx, s.d, cmodel.Where("delegate = ?", addr))),
+		Pure:    dao.GetPureProxy(ctx, s.d, addr),
+	}
+	if account.Pure != nil {
+		encodePure(account.Pure)
+	}
+	account.Spawned, _, _ = dao.PureProxiesCursor(ctx, s.d, 100, nil, nil, cmodel.Where("spawner = ?", addr))
+	for i := range account.Spawned {
+		encodePure(&account.Spawned[i])
+	}
+	return &account
+}
+
+func (s *Service) GetPureProxiesCursor(ctx context.Context, spawner string, limit int, before, after *uint) ([]model.PureProxy, map[string]interface{}) {
+	var opts []cmodel.Option
+	if spawner != "" {
+		opts = append(opts, cmodel.Where("spawner = ?", spawner))
+	}
+	list, hasPrev, hasNext := dao.PureProxiesCursor(ctx, s.d, limit, before, after, opts...)
+	for i := range list {
+		encodePure(&list[i])
+	}
+	var start, end *uint
+	if len(list) > 0 {
+		start = &list[0].ID
+		end = &list[len(list)-1].ID
+	}
+	return list, map[string]interface{}{
+		"start_cursor":      start,
+		"end_cursor":        end,
+		"has_previous_page": hasPrev,
+		"has_next_page":     hasNext,
+	}
+}
+
+func (s *Service) GetProxyCallsCursor(ctx context.Context, addr string, limit int, before, after *uint) ([]model.ProxyCall, map[string]interface{}) {
+	var opts []cmodel.Option
+	if addr != "" {
+		opts = append(opts, cmodel.Where("delegate = ? or real_account = ?", addr, addr))
+	}
+	list, hasPrev, hasNext := dao.ProxyCallsCursor(ctx, s.d, limit, before, after, opts...)
+	for i := range list {
+		list[i].Delegate = address.Encode(list[i].Delegate)
+		list[i].Real = address.Encode(list[i].Real)
+	}
+	var start, end *uint
+	if len(list) > 0 {
+		start = &list[0].ID
+		end = &list[len(list)-1].ID
+	}
+	return list, map[string]interface{}{
+		"start_cursor":      start,
+		"end_cursor":        end,
+		"has_previous_page": hasPrev,
+		"has_next_page":     hasNext,
+	}
+}
+
+func (s *Service) GetAnnouncementsCursor(ctx context.Context, addr string, limit int, before, after *uint) ([]model.Announcement, map[string]interface{}) {
+	var opts []cmodel.Option
+	if addr != "" {
+		opts = append(opts, cmodel.Where("delegate = ? or real_account = ?", addr, addr))
+	}
+	list, hasPrev, hasNext := dao.AnnouncementsCursor(ctx, s.d, limit, before, after, opts...)
+	for i := range list {
+		list[i].Delegate = address.Encode(list[i].Delegate)
+		list[i].Real = address.Encode(list[i].Real)
+	}
+	var start, end *uint
+	if len(list) > 0 {
+		start = &list[0].ID
+		end = &list[len(list)-1].ID
+	}
+	return list, map[string]interface{}{
+		"start_cursor":      start,
+		"end_cursor":        end,
+		"has_previous_page": hasPrev,
+		"has_next_page":     hasNext,
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/itering/subscan-plugin"
 	"github.com/itering/subscan/plugins/balance"
 	"github.com/itering/subscan/plugins/evm"
+	"github.com/itering/subscan/plugins/nominationpools"
+	"github.com/itering/subscan/plugins/proxy"
 	"github.com/itering/subscan/plugins/system"
 	"reflect"
 	"strings"
@@ -18,6 +20,8 @@ func init() {
 	registerNative(balance.New())
 	registerNative(system.New())
 	registerNative(evm.New())
+	registerNative(proxy.New())
+	registerNative(nominationpools.New())
 }
 
 func register(name string, f subscan_plugin.Plugin) {
@@ -0,0 +1,26 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	EvmBlockProcessCost = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "subscan",
+		Subsystem: "evm",
+		Name:      "block_process_duration_seconds",
+		Help:      "Time spent on each stage of processing evm block",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.2, 0.5, 1, 2, 5, 10, 30},
+	}, []string{"stage"})
+	EvmReceiptsFetch = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "subscan",
+		Subsystem: "evm",
+		Name:      "receipts_fetch_total",
+		Help:      "The number of evm blocks receipts fetched by each rpc method",
+	}, []string{"method"})
+	EvmBlockTransactions = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "subscan",
+		Subsystem: "evm",
+		Name:      "block_transactions",
+		Help:      "The number of transactions of processed evm block",
+		Buckets:   []float64{0, 1, 5, 10, 20, 50, 100, 200, 500},
+	})
+)
@@ -8,5 +8,7 @@ func init() {
 		subBlockStatusGauge, SubBlockFillError,
 		// worker
 		WorkerProcessCost,
+		// evm
+		EvmBlockProcessCost, EvmReceiptsFetch, EvmBlockTransactions,
 	)
 }
@@ -0,0 +1,134 @@
+package price
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const dateLayout = "2006-01-02"
+
+// NativeKey price key of native token
+const NativeKey = "native"
+
+// AssetKey price key of substrate asset, e.g. assets/1984.
+// prices are never looked up by symbol, symbol of asset or token is set by its creator
+func AssetKey(category, assetId string) string {
+	if category == "" || category == NativeKey {
+		return NativeKey
+	}
+	return NormalizeKey(category + "/" + assetId)
+}
+
+// ContractKey price key of evm token contract
+func ContractKey(contract string) string {
+	return NormalizeKey(contract)
+}
+
+func NormalizeKey(key string) string {
+	return strings.ToLower(strings.TrimSpace(key))
+}
+
+// TokenPrice daily usd price of asset key, native, <category>/<asset_id> or evm contract address
+type TokenPrice struct {
+	Id     uint            `gorm:"primary_key" json:"-"`
+	Asset  string          `json:"asset" gorm:"size:100;index:asset_date,unique,priority:1"`
+	Date   string          `json:"date" gorm:"size:10;index:asset_date,unique,priority:2"`
+	Price  decimal.Decimal `json:"price" gorm:"type:decimal(40,18);"`
+	Source string          `json:"source" gorm:"size:100"`
+}
+
+func (t *TokenPrice) TableName() string {
+	return "token_prices"
+}
+
+func dateOf(timestamp int64) string {
+	return time.Unix(timestamp, 0).UTC().Format(dateLayout)
+}
+
+// Refresh fetch prices from source and save as price of today
+func Refresh(ctx context.Context, db *gorm.DB, source PriceSource) (int, error) {
+	prices, err := source.Prices(ctx)
+	if err != nil {
+		return 0, err
+	}
+	today := dateOf(time.Now().Unix())
+	var list []TokenPrice
+	for key, value := range prices {
+		list = append(list, TokenPrice{Asset: NormalizeKey(key), Date: today, Price: value, Source: source.Name()})
+	}
+	if len(list) == 0 {
+		return 0, nil
+	}
+	q := db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "asset"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"price", "source"}),
+	}).Create(&list)
+	return len(list), q.Error
+}
+
+// Latest the newest price of asset key
+func Latest(ctx context.Context, db *gorm.DB, key string) *decimal.Decimal {
+	var tokenPrice TokenPrice
+	if q := db.WithContext(ctx).Where("asset = ?", NormalizeKey(key)).Order("date desc").Take(&tokenPrice); q.Error != nil {
+		return nil
+	}
+	return &tokenPrice.Price
+}
+
+// At price of asset key on the day of timestamp, fallback to the closest day before
+func At(ctx context.Context, db *gorm.DB, key string, timestamp int64) *decimal.Decimal {
+	var tokenPrice TokenPrice
+	if q := db.WithContext(ctx).Where("asset = ?", NormalizeKey(key)).Where("date <= ?", dateOf(timestamp)).
+		Order("date desc").Take(&tokenPrice); q.Error != nil {
+		return nil
+	}
+	return &tokenPrice.Price
+}
+
+// History daily prices of asset key, order by date desc
+func History(ctx context.Context, db *gorm.DB, key string, days int) (list []TokenPrice) {
+	db.WithContext(ctx).Where("asset = ?", NormalizeKey(key)).Order("date desc").Limit(days).Find(&list)
+	return
+}
+
+// ValueUSD usd value of raw amount with decimals, nil if price unknown
+func ValueUSD(amount decimal.Decimal, decimals int, price *decimal.Decimal) *decimal.Decimal {
+	if price == nil {
+		return nil
+	}
+	value := amount.Shift(int32(-decimals)).Mul(*price).Round(6)
+	return &value
+}
+
+// Book memoize prices lookup of a response
+type Book struct {
+	db     *gorm.DB
+	latest map[string]*decimal.Decimal
+	daily  map[string]*decimal.Decimal
+}
+
+func NewBook(db *gorm.DB) *Book {
+	return &Book{db: db, latest: make(map[string]*decimal.Decimal), daily: make(map[string]*decimal.Decimal)}
+}
+
+func (b *Book) Latest(ctx context.Context, key string) *decimal.Decimal {
+	if v, ok := b.latest[key]; ok {
+		return v
+	}
+	b.latest[key] = Latest(ctx, b.db, key)
+	return b.latest[key]
+}
+
+func (b *Book) At(ctx context.Context, key string, timestamp int64) *decimal.Decimal {
+	day := key + "_" + dateOf(timestamp)
+	if v, ok := b.daily[day]; ok {
+		return v
+	}
+	b.daily[day] = At(ctx, b.db, key, timestamp)
+	return b.daily[day]
+}
@@ -0,0 +1,65 @@
+package price
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StaticSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"DOT":"4.21","USDT":1}`), 0644))
+	source := NewSource("file://" + path)
+	assert.IsType(t, &StaticSource{}, source)
+	prices, err := source.Prices(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "4.21", prices["DOT"].String())
+	assert.Equal(t, "1", prices["USDT"].String())
+
+	_, err = NewSource(filepath.Join(t.TempDir(), "missing.json")).Prices(context.TODO())
+	assert.Error(t, err)
+}
+
+func Test_HttpSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"GLMR":"0.25"}`))
+	}))
+	defer server.Close()
+	source := NewSource(server.URL)
+	assert.IsType(t, &HttpSource{}, source)
+	prices, err := source.Prices(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "0.25", prices["GLMR"].String())
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`not json`))
+	}))
+	defer broken.Close()
+	_, err = NewSource(broken.URL).Prices(context.TODO())
+	assert.Error(t, err)
+
+	assert.Nil(t, NewSource(""))
+}
+
+func Test_ValueUSD(t *testing.T) {
+	price := decimal.RequireFromString("4.5")
+	assert.Nil(t, ValueUSD(decimal.NewFromInt(1), 10, nil))
+	assert.Equal(t, "9", ValueUSD(decimal.NewFromInt(20000000000), 10, &price).String())
+	assert.Equal(t, "0.000045", ValueUSD(decimal.NewFromInt(100000), 10, &price).String())
+	assert.Equal(t, "2021-06-01", dateOf(1622548800))
+}
+
+func Test_PriceKey(t *testing.T) {
+	assert.Equal(t, NativeKey, AssetKey("", ""))
+	assert.Equal(t, NativeKey, AssetKey("native", "DOT"))
+	assert.Equal(t, "assets/1984", AssetKey("assets", "1984"))
+	assert.Equal(t, "foreignassets/0xabcd", AssetKey("foreignAssets", "0xABCD"))
+	assert.Equal(t, "0x66b8c60c79dfad02fc04f1f13aab0f6feff8615b", ContractKey("0x66B8C60C79dfAD02fc04f1f13aaB0f6FefF8615b"))
+	assert.Equal(t, "native", NormalizeKey(" Native "))
+}
@@ -0,0 +1,77 @@
+package price
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/itering/subscan/util"
+	"github.com/shopspring/decimal"
+)
+
+// PriceSource provide current usd price of asset keys, see NativeKey, AssetKey and ContractKey
+type PriceSource interface {
+	Name() string
+	Prices(ctx context.Context) (map[string]decimal.Decimal, error)
+}
+
+// StaticSource json file of asset key => price, e.g. {"native": "4.21", "assets/1984": "1"}
+type StaticSource struct {
+	Path string
+}
+
+func (s *StaticSource) Name() string {
+	return "static"
+}
+
+func (s *StaticSource) Prices(_ context.Context) (map[string]decimal.Decimal, error) {
+	raw, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	return decodePrices(raw)
+}
+
+// HttpSource http endpoint respond json of asset key => price
+type HttpSource struct {
+	Endpoint string
+}
+
+func (s *HttpSource) Name() string {
+	return "http"
+}
+
+func (s *HttpSource) Prices(ctx context.Context) (map[string]decimal.Decimal, error) {
+	raw, err := util.HttpGet(ctx, s.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return decodePrices(raw)
+}
+
+func decodePrices(raw []byte) (map[string]decimal.Decimal, error) {
+	prices := make(map[string]decimal.Decimal)
+	if err := json.Unmarshal(raw, &prices); err != nil {
+		return nil, fmt.Errorf("invalid prices: %w", err)
+	}
+	return prices, nil
+}
+
+// NewSource http(s) url is HttpSource, otherwise file path of StaticSource
+func NewSource(uri string) PriceSource {
+	switch {
+	case uri == "":
+		return nil
+	case strings.HasPrefix(uri, "http://"), strings.HasPrefix(uri, "https://"):
+		return &HttpSource{Endpoint: uri}
+	default:
+		return &StaticSource{Path: strings.TrimPrefix(uri, "file://")}
+	}
+}
+
+// DefaultSource set by env PRICE_SOURCE, nil if price feed disabled
+func DefaultSource() PriceSource {
+	return NewSource(os.Getenv("PRICE_SOURCE"))
+}
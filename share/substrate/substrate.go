@@ -17,6 +17,7 @@ import (
 	"github.com/itering/substrate-api-rpc/storageKey"
 	"github.com/itering/substrate-api-rpc/websocket"
 	"math/rand"
+	"strings"
 )
 
 func DecodeExtrinsicParams(raw string, metadata *metadata.Instant, call *types.MetadataCalls, spec int) (params []scalecodec.ExtrinsicParam, err error) {
@@ -303,3 +304,22 @@ func Size(hasher string) int {
 
 	return defaultHashSize
 }
+
+// StorageKeyTypes returns the key types of module storage map from the latest metadata
+func StorageKeyTypes(module, method string) []string {
+	m := metadata.Latest(nil)
+	if m == nil {
+		return nil
+	}
+	for _, mm := range m.Metadata.Modules {
+		if !strings.EqualFold(mm.Name, module) {
+			continue
+		}
+		for _, s := range mm.Storage {
+			if strings.EqualFold(s.Name, method) {
+				return CheckoutHasherAndType(&s.Type).Keys
+			}
+		}
+	}
+	return nil
+}
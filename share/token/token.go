@@ -1,13 +1,37 @@
 package token
 
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+const NativeCategory = "native"
+
 type Token struct {
-	TokenId string `json:"token_id"`
+	TokenId  string `json:"token_id"`
+	Category string `json:"category,omitempty"`
 
 	Symbol   string `json:"symbol"`
 	Decimals int    `json:"decimals"`
+	Holders  uint   `json:"holders,omitempty"`
+}
+
+type Holder struct {
+	Address string          `json:"address"`
+	Balance decimal.Decimal `json:"balance"`
+}
+
+// Registry provide tokens registered on chain besides the native token, e.g. assets, foreignAssets and tokens pallets
+type Registry interface {
+	Tokens(ctx context.Context) []Token
+	Holders(ctx context.Context, category, tokenId string, limit int) []Holder
 }
 
-var defaultToken *Token
+var (
+	defaultToken *Token
+	registry     Registry
+)
 
 func GetDefaultToken() *Token {
 	return defaultToken
@@ -16,3 +40,29 @@ func GetDefaultToken() *Token {
 func SetDefault(t *Token) {
 	defaultToken = t
 }
+
+func SetRegistry(r Registry) {
+	registry = r
+}
+
+// List native token first, then all registered tokens
+func List(ctx context.Context) []Token {
+	var list []Token
+	if defaultToken != nil {
+		native := *defaultToken
+		native.Category = NativeCategory
+		list = append(list, native)
+	}
+	if registry != nil {
+		list = append(list, registry.Tokens(ctx)...)
+	}
+	return list
+}
+
+// Holders of registered token, sorted by balance desc
+func Holders(ctx context.Context, category, tokenId string, limit int) []Holder {
+	if registry == nil {
+		return nil
+	}
+	return registry.Holders(ctx, category, tokenId, limit)
+}
@@ -0,0 +1,53 @@
+package token
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRegistry struct{}
+
+func (m mockRegistry) Tokens(_ context.Context) []Token {
+	return []Token{{TokenId: "1984", Category: "assets", Symbol: "USDT", Decimals: 6, Holders: 2}}
+}
+
+func (m mockRegistry) Holders(_ context.Context, category, tokenId string, limit int) []Holder {
+	if category != "assets" || tokenId != "1984" {
+		return nil
+	}
+	holders := []Holder{
+		{Address: "alice", Balance: decimal.New(2, 6)},
+		{Address: "bob", Balance: decimal.New(1, 6)},
+	}
+	if limit < len(holders) {
+		holders = holders[:limit]
+	}
+	return holders
+}
+
+func Test_List(t *testing.T) {
+	ctx := context.TODO()
+	SetDefault(&Token{TokenId: "DOT", Symbol: "DOT", Decimals: 10})
+	defer SetDefault(nil)
+
+	assert.Equal(t, []Token{{TokenId: "DOT", Category: NativeCategory, Symbol: "DOT", Decimals: 10}}, List(ctx))
+	assert.Nil(t, Holders(ctx, "assets", "1984", 10))
+	// default token not changed
+	assert.Equal(t, "", GetDefaultToken().Category)
+
+	SetRegistry(mockRegistry{})
+	defer SetRegistry(nil)
+	list := List(ctx)
+	assert.Len(t, list, 2)
+	assert.Equal(t, NativeCategory, list[0].Category)
+	assert.Equal(t, "1984", list[1].TokenId)
+	assert.Equal(t, "USDT", list[1].Symbol)
+
+	holders := Holders(ctx, "assets", "1984", 1)
+	assert.Len(t, holders, 1)
+	assert.Equal(t, "alice", holders[0].Address)
+	assert.Nil(t, Holders(ctx, "assets", "1", 10))
+}
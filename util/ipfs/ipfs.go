@@ -2,25 +2,163 @@ package ipfs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/ipfs/go-cid"
 	"github.com/itering/subscan/util"
+	"io"
+	"net"
+	"net/http"
 	"net/url"
 	"strings"
+	"syscall"
 	"time"
 )
 
-// https://ipfs.github.io/public-gateway-checker/
+const (
+	defaultFetchTimeout = 10
+	defaultMaxFileSize  = 10 << 20
+	maxRedirects        = 5
+)
+
+var (
+	// IpfsGateways ipfs gateways tried in order, https://ipfs.github.io/public-gateway-checker/
+	IpfsGateways = splitGateways(util.GetEnv("IPFS_GATEWAYS", "https://ipfs.nftstorage.link,https://ipfs.io"))
+	// ArweaveGateways arweave gateways tried in order
+	ArweaveGateways = splitGateways(util.GetEnv("ARWEAVE_GATEWAYS", "https://arweave.net"))
+	// FetchTimeout timeout of each gateway request
+	FetchTimeout = time.Duration(positiveInt(util.GetEnv("IPFS_FETCH_TIMEOUT", ""), defaultFetchTimeout)) * time.Second
+	// MaxFileSize max bytes of fetched file
+	MaxFileSize = int64(positiveInt(util.GetEnv("IPFS_MAX_FILE_SIZE", ""), defaultMaxFileSize))
+)
+
+var (
+	ErrPrivateAddress = errors.New("private address not allowed")
+	ErrFileTooLarge   = errors.New("file too large")
+	ErrNotImage       = errors.New("not image content type")
+)
+
+var (
+	// gatewayClient request configured gateways, which may be a local ipfs node
+	gatewayClient = &http.Client{}
+	// publicClient request uri set by token contract, only public addresses are allowed,
+	// checked on every connection after dns resolution, include redirects
+	publicClient = &http.Client{
+		Transport: &http.Transport{
+			DialContext:         (&net.Dialer{Timeout: 30 * time.Second, Control: denyPrivateAddress}).DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("unsupported redirect scheme %s", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+)
+
+func positiveInt(raw string, fallback int) int {
+	if i := util.StringToInt(strings.TrimSpace(raw)); i > 0 {
+		return i
+	}
+	return fallback
+}
+
+// publicIP not loopback, private, link-local, multicast, unspecified or shared address space
+func publicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	// 100.64.0.0/10 carrier-grade nat
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64 {
+		return false
+	}
+	return true
+}
+
+func denyPrivateAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// get read at most MaxFileSize bytes of endpoint, content type must be image/* if image is set
+func get(ctx context.Context, client *http.Client, endpoint string, image bool) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	if resp.StatusCode > 300 {
+		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+	if image && !strings.HasPrefix(strings.ToLower(resp.Header.Get("Content-Type")), "image/") {
+		return nil, fmt.Errorf("%w: %s", ErrNotImage, resp.Header.Get("Content-Type"))
+	}
+	if resp.ContentLength > MaxFileSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrFileTooLarge, resp.ContentLength)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > MaxFileSize {
+		return nil, fmt.Errorf("%w: over %d bytes", ErrFileTooLarge, MaxFileSize)
+	}
+	return body, nil
+}
+
+func splitGateways(raw string) (gateways []string) {
+	for _, gateway := range strings.Split(raw, ",") {
+		if gateway = strings.TrimSuffix(strings.TrimSpace(gateway), "/"); gateway != "" {
+			gateways = append(gateways, gateway)
+		}
+	}
+	return
+}
+
+// openGateways request path on gateways until one of them success
+func openGateways(ctx context.Context, gateways []string, path string, image bool) ([]byte, error) {
+	err := fmt.Errorf("no gateway configured")
+	for _, gateway := range gateways {
+		var data []byte
+		subCtx, cancel := context.WithTimeout(ctx, FetchTimeout)
+		data, err = get(subCtx, gatewayClient, fmt.Sprintf("%s/%s", gateway, path), image)
+		cancel()
+		if err == nil {
+			return data, nil
+		}
+		err = fmt.Errorf("gateway %s: %w", gateway, err)
+	}
+	return nil, err
+}
+
 func OpenFile(ctx context.Context, id string) ([]byte, error) {
-	subCtx, cancel := context.WithTimeout(ctx, time.Second*10)
-	var gateways = "ipfs.nftstorage.link"
-	defer cancel()
+	return openFile(ctx, id, false)
+}
+
+func openFile(ctx context.Context, id string, image bool) ([]byte, error) {
 	if err := verifyCid(id); err != nil {
 		return nil, fmt.Errorf("cid %s verify failed %s", id, err)
 	}
-	return util.HttpGet(subCtx, fmt.Sprintf("https://%s.%s", id, gateways))
-
+	return openGateways(ctx, IpfsGateways, "ipfs/"+id, image)
 }
+
 func verifyCid(id string) error {
 	ids := strings.Split(id, "/")
 	if len(ids) == 0 {
@@ -63,8 +201,29 @@ func TrimMetadataUri(data string) string {
 }
 
 func OpenArFile(ctx context.Context, id string) ([]byte, error) {
-	subCtx, cancel := context.WithTimeout(ctx, time.Second*10)
-	defer cancel()
-	endpoint := fmt.Sprintf("https://%s/%s", "www.arweave.net", id)
-	return util.HttpGet(subCtx, endpoint)
+	return openGateways(ctx, ArweaveGateways, id, false)
+}
+
+// Open ipfs, arweave or http uri
+func Open(ctx context.Context, uri string) ([]byte, error) {
+	return open(ctx, uri, false)
+}
+
+// OpenImage ipfs, arweave or http uri, respond content type must be image/*
+func OpenImage(ctx context.Context, uri string) ([]byte, error) {
+	return open(ctx, uri, true)
+}
+
+func open(ctx context.Context, uri string, image bool) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(uri, "ipfs://") || strings.HasPrefix(uri, "https://ipfs.io"):
+		return openFile(ctx, TrimMetadataUri(uri), image)
+	case strings.HasPrefix(uri, "ar://"):
+		return openGateways(ctx, ArweaveGateways, TrimMetadataUri(uri), image)
+	case strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://"):
+		subCtx, cancel := context.WithTimeout(ctx, FetchTimeout)
+		defer cancel()
+		return get(subCtx, publicClient, uri, image)
+	}
+	return nil, fmt.Errorf("unsupported uri %s", uri)
 }
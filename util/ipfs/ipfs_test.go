@@ -1,8 +1,13 @@
 package ipfs
 
 import (
+	"bytes"
 	"context"
 	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -47,3 +52,91 @@ func Test_TrimMetadataUri(t *testing.T) {
 		assert.Equal(t, test.expected, result)
 	}
 }
+
+func Test_OpenGateways(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer gateway.Close()
+
+	origin := IpfsGateways
+	defer func() { IpfsGateways = origin }()
+	IpfsGateways = splitGateways(broken.URL + ", " + gateway.URL + "/")
+	data, err := Open(context.Background(), "ipfs://bafkreidyeivj7adnnac6ljvzj2e3rd5xdw3revw4da7mx2ckrstapoupoq")
+	assert.NoError(t, err)
+	assert.Equal(t, "/ipfs/bafkreidyeivj7adnnac6ljvzj2e3rd5xdw3revw4da7mx2ckrstapoupoq", string(data))
+
+	IpfsGateways = splitGateways(broken.URL)
+	_, err = OpenFile(context.Background(), "bafkreidyeivj7adnnac6ljvzj2e3rd5xdw3revw4da7mx2ckrstapoupoq")
+	assert.ErrorContains(t, err, broken.URL)
+
+	_, err = Open(context.Background(), "did:dkg:otp/0x1")
+	assert.Error(t, err)
+}
+
+func Test_positiveInt(t *testing.T) {
+	assert.Equal(t, 30, positiveInt("30", defaultFetchTimeout))
+	assert.Equal(t, defaultFetchTimeout, positiveInt("", defaultFetchTimeout))
+	assert.Equal(t, defaultFetchTimeout, positiveInt("10s", defaultFetchTimeout))
+	assert.Equal(t, defaultFetchTimeout, positiveInt("-1", defaultFetchTimeout))
+}
+
+func Test_publicIP(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.0.0.1", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fe80::1", "fc00::1", "::ffff:127.0.0.1"} {
+		assert.False(t, publicIP(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"8.8.8.8", "100.128.0.1", "2606:4700:4700::1111"} {
+		assert.True(t, publicIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func Test_OpenPrivateAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+	_, err := Open(context.Background(), server.URL)
+	assert.ErrorIs(t, err, ErrPrivateAddress)
+
+	// localhost resolved to loopback address
+	_, err = Open(context.Background(), strings.Replace(server.URL, "127.0.0.1", "localhost", 1))
+	assert.ErrorIs(t, err, ErrPrivateAddress)
+}
+
+func Test_OpenImage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ipfs/bafkreidyeivj7adnnac6ljvzj2e3rd5xdw3revw4da7mx2ckrstapoupoq":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("png"))
+		case "/ipfs/bafybeiftkn4roy2j3b2qipq3i4oavyvnjubvnavxmnkt3mmj6fnw5bvngq":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(bytes.Repeat([]byte("0"), 64))
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html></html>"))
+		}
+	}))
+	defer server.Close()
+
+	origin, originSize := IpfsGateways, MaxFileSize
+	defer func() { IpfsGateways, MaxFileSize = origin, originSize }()
+	IpfsGateways, MaxFileSize = splitGateways(server.URL), 32
+
+	data, err := OpenImage(context.Background(), "ipfs://bafkreidyeivj7adnnac6ljvzj2e3rd5xdw3revw4da7mx2ckrstapoupoq")
+	assert.NoError(t, err)
+	assert.Equal(t, "png", string(data))
+
+	_, err = OpenImage(context.Background(), "ipfs://bafybeiftkn4roy2j3b2qipq3i4oavyvnjubvnavxmnkt3mmj6fnw5bvngq")
+	assert.ErrorIs(t, err, ErrFileTooLarge)
+
+	_, err = OpenImage(context.Background(), "ipfs://QmTzQ1Nj5x1Z7pN9f6dNVxv1b5DmS5aK9CYXq1v4TqQZ7x")
+	assert.ErrorIs(t, err, ErrNotImage)
+	// metadata json is not required to be image
+	_, err = Open(context.Background(), "ipfs://QmTzQ1Nj5x1Z7pN9f6dNVxv1b5DmS5aK9CYXq1v4TqQZ7x")
+	assert.NoError(t, err)
+}
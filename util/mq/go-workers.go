@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/itering/subscan/configs"
 	"github.com/itering/subscan/util"
+	"time"
 
 	"github.com/itering/go-workers"
 )
@@ -56,3 +57,11 @@ func (g *GoWorker) ForcePublish(queue, class string, args interface{}) error {
 	}
 	return nil
 }
+
+// PublishIn enqueue job to scheduled set, run after delay
+func (g *GoWorker) PublishIn(queue, class string, delay time.Duration, args interface{}) error {
+	if _, err := workers.EnqueueIn(queue, class, delay.Seconds(), args); err != nil {
+		return err
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/itering/subscan/util"
 	redisUtil "github.com/itering/subscan/util/redis"
+	"time"
 
 	"github.com/gomodule/redigo/redis"
 )
@@ -16,6 +17,7 @@ type IJob interface {
 	Consumption()
 	Publish(string, string, interface{}) error
 	ForcePublish(string, string, interface{}) error
+	PublishIn(string, string, time.Duration, interface{}) error
 	Shutdown(_ context.Context) error
 	SubscribePublish(any) error
 }